@@ -0,0 +1,458 @@
+// Package config merges quest's settings from its layers: built-in
+// defaults, the user's config file, the project's, the environment's
+// settings, environment variables and command-line flags, each overriding
+// the ones before it.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pixperk/quest/internal/http"
+)
+
+const (
+	// ProjectFile is the project's config, read from the current directory
+	ProjectFile = ".quest-config"
+	// EnvironmentFile holds the settings of each environment, by name
+	EnvironmentFile = ".quest-environments"
+	// DefaultTheme is the theme used when no layer picks one
+	DefaultTheme = "dark"
+)
+
+// Config is the effective configuration
+type Config struct {
+	// Environment names the environment, which picks the settings in
+	// EnvironmentFile and the cookie jar
+	Environment string
+	Timeout     time.Duration
+	// MaxBodyInMemory is how much of a response body is held in memory
+	// before the rest is spilled to a temp file
+	MaxBodyInMemory int64
+	// Headers are sent with every request, under the request's own
+	Headers   map[string]string
+	UserAgent string
+	Proxy     http.ProxyConfig
+	TLS       http.TLSConfig
+	Theme     string
+	// Keys rebinds actions, by name, to the keys listed
+	Keys map[string][]string
+	// Cookies starts the cookie jar on; it can still be turned off and on
+	// during the session
+	Cookies bool
+
+	// Layers lists where settings were looked for, lowest first, and
+	// Settings every value in effect with the layer it came from
+	Layers   []Layer
+	Settings []Setting
+}
+
+// Layer is one place settings are read from
+type Layer struct {
+	Name string
+	// Found is false when the layer's file doesn't exist or it set nothing
+	Found bool
+}
+
+// Setting is one value in effect and the layer that set it
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+	// Secret marks values that shouldn't be shown in full
+	Secret bool
+}
+
+// file is the JSON form of a config file
+type file struct {
+	Timeout         string              `json:"timeout,omitempty"`
+	MaxBodyInMemory string              `json:"max_body_in_memory,omitempty"`
+	Headers         map[string]string   `json:"headers,omitempty"`
+	UserAgent       string              `json:"user_agent,omitempty"`
+	Proxy           *http.ProxyConfig   `json:"proxy,omitempty"`
+	TLS             *http.TLSConfig     `json:"tls,omitempty"`
+	Theme           string              `json:"theme,omitempty"`
+	Keys            map[string][]string `json:"keys,omitempty"`
+	Cookies         *bool               `json:"cookies,omitempty"`
+}
+
+// Environment holds the settings every request in an environment shares
+type Environment struct {
+	TLS   http.TLSConfig   `json:"tls"`
+	Proxy http.ProxyConfig `json:"proxy"`
+}
+
+// leaf is one value in a flattened layer, at its path of object keys
+type leaf struct {
+	path   []string
+	value  any
+	source string
+}
+
+// merged is the layers flattened and stacked, keyed by dotted path
+type merged map[string]leaf
+
+// add flattens a layer's JSON object into m, replacing values lower layers
+// set at the same paths. It reports whether the layer set anything.
+func (m merged) add(data []byte, source string) (bool, error) {
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return false, err
+	}
+
+	// Check the layer on its own, so that errors point at it
+	layer := merged{}
+	set := layer.flatten(nil, object, source)
+	if err := layer.decode(&file{}); err != nil {
+		return false, err
+	}
+	for _, group := range replacements {
+		for _, trigger := range group.triggers {
+			if _, ok := layer[trigger]; ok {
+				for _, key := range group.keys {
+					delete(m, key)
+				}
+				break
+			}
+		}
+	}
+	for key, l := range layer {
+		m[key] = l
+	}
+	return set > 0, nil
+}
+
+// replacements are settings that go together, as in the Merge methods of
+// http.ProxyConfig and http.TLSConfig: a layer setting any of triggers
+// clears what lower layers set for keys
+var replacements = []struct{ triggers, keys []string }{
+	{
+		triggers: []string{"proxy.url", "proxy.direct"},
+		keys:     []string{"proxy.url", "proxy.direct"},
+	},
+	{
+		triggers: []string{"tls.cert_file", "tls.pkcs12_file"},
+		keys:     []string{"tls.cert_file", "tls.key_file", "tls.pkcs12_file", "tls.pkcs12_password"},
+	},
+}
+
+// flatten adds each value in object under path, returning how many it set
+func (m merged) flatten(path []string, object map[string]any, source string) int {
+	set := 0
+	for key, value := range object {
+		if len(path) == 1 && path[0] == "headers" {
+			key = textproto.CanonicalMIMEHeaderKey(key)
+		}
+		keyPath := append(append([]string{}, path...), key)
+		if nested, ok := value.(map[string]any); ok {
+			set += m.flatten(keyPath, nested, source)
+			continue
+		}
+		if number, ok := value.(float64); ok && (key == "timeout" || key == "max_body_in_memory") && len(path) == 0 {
+			value = strconv.FormatFloat(number, 'f', -1, 64)
+		}
+		m[strings.Join(keyPath, ".")] = leaf{path: keyPath, value: value, source: source}
+		set++
+	}
+	return set
+}
+
+// decode rebuilds the JSON object from the stacked values and reads it
+// into f, rejecting keys quest doesn't know
+func (m merged) decode(f *file) error {
+	object := map[string]any{}
+	for _, l := range m {
+		parent := object
+		for _, key := range l.path[:len(l.path)-1] {
+			child, ok := parent[key].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				parent[key] = child
+			}
+			parent = child
+		}
+		parent[l.path[len(l.path)-1]] = l.value
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(f)
+}
+
+// Load merges the layers. args are the command-line arguments after the
+// program name.
+func Load(args []string) (Config, error) {
+	flags, err := parseFlags(args)
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg := Config{Environment: "default"}
+	if env := strings.TrimSpace(os.Getenv("QUEST_ENV")); env != "" {
+		cfg.Environment = env
+	}
+	if flags.environment != "" {
+		cfg.Environment = flags.environment
+	}
+
+	values := merged{}
+	values.add([]byte(fmt.Sprintf(`{"timeout": %q, "max_body_in_memory": "%dMB", "user_agent": %q, "theme": %q, "cookies": true}`,
+		http.DefaultTimeout, http.DefaultMaxBodyInMemory>>20, http.DefaultUserAgent, DefaultTheme)), "default")
+	cfg.Layers = append(cfg.Layers, Layer{Name: "default", Found: true})
+
+	for _, path := range []string{userFile(), ProjectFile} {
+		found, err := values.addFile(path)
+		if err != nil {
+			return Config{}, err
+		}
+		cfg.Layers = append(cfg.Layers, Layer{Name: path, Found: found})
+	}
+
+	envName := EnvironmentFile + " (" + cfg.Environment + ")"
+	env, err := loadEnvironment(EnvironmentFile, cfg.Environment)
+	if err != nil {
+		return Config{}, err
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		return Config{}, err
+	}
+	found, err := values.add(data, envName)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", envName, err)
+	}
+	cfg.Layers = append(cfg.Layers, Layer{Name: envName, Found: found})
+
+	variables, err := environmentVariables()
+	if err != nil {
+		return Config{}, err
+	}
+	for _, layer := range []struct {
+		name   string
+		values map[string]any
+	}{
+		{"environment variables", variables},
+		{"flags", flags.values},
+	} {
+		found := false
+		for _, source := range sortedKeys(layer.values) {
+			data, err := json.Marshal(layer.values[source])
+			if err != nil {
+				return Config{}, err
+			}
+			set, err := values.add(data, source)
+			if err != nil {
+				return Config{}, fmt.Errorf("%s: %w", source, err)
+			}
+			found = found || set
+		}
+		cfg.Layers = append(cfg.Layers, Layer{Name: layer.name, Found: found})
+	}
+
+	if err := cfg.apply(values); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// addFile adds a config file's layer; a missing file sets nothing
+func (m merged) addFile(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	found, err := m.add(data, path)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", path, err)
+	}
+	return found, nil
+}
+
+// apply reads the stacked values into cfg
+func (cfg *Config) apply(values merged) error {
+	var f file
+	if err := values.decode(&f); err != nil {
+		return err
+	}
+
+	timeout, err := ParseTimeout(f.Timeout)
+	if err != nil {
+		return fmt.Errorf("timeout (from %s): %w", values["timeout"].source, err)
+	}
+	cfg.Timeout = timeout
+	size, err := ParseSize(f.MaxBodyInMemory)
+	if err != nil {
+		return fmt.Errorf("max_body_in_memory (from %s): %w", values["max_body_in_memory"].source, err)
+	}
+	cfg.MaxBodyInMemory = size
+	cfg.Headers = f.Headers
+	cfg.UserAgent = f.UserAgent
+	cfg.Theme = f.Theme
+	cfg.Keys = f.Keys
+	cfg.Cookies = f.Cookies == nil || *f.Cookies
+	if f.Proxy != nil {
+		cfg.Proxy = *f.Proxy
+		if cfg.Proxy.URL != "" {
+			if _, err := http.ParseProxyURL(cfg.Proxy.URL); err != nil {
+				return fmt.Errorf("proxy.url (from %s): %w", values["proxy.url"].source, err)
+			}
+		}
+	}
+	if f.TLS != nil {
+		cfg.TLS = *f.TLS
+	}
+
+	for _, key := range sortedKeys(values) {
+		l := values[key]
+		value := fmt.Sprint(l.value)
+		switch v := l.value.(type) {
+		case []any:
+			parts := make([]string, len(v))
+			for i, item := range v {
+				parts[i] = fmt.Sprint(item)
+			}
+			value = strings.Join(parts, ", ")
+			if len(v) == 0 {
+				value = "none"
+			}
+		case string:
+			if key == "proxy.url" {
+				if u, err := url.Parse(v); err == nil {
+					value = u.Redacted()
+				}
+			}
+		}
+		cfg.Settings = append(cfg.Settings, Setting{Key: key, Value: value, Source: l.source, Secret: isSecret(l.path)})
+	}
+	return nil
+}
+
+// ParseTimeout reads a timeout as a duration ("30s", "1m") or a number of
+// seconds; zero means no timeout
+func ParseTimeout(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		value += "s"
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q isn't a duration like 30s or 2m", value)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("%q is negative", value)
+	}
+	return timeout, nil
+}
+
+// ParseSize reads a size as a number of bytes or with a KB, MB or GB
+// suffix, counted in 1024s; it must be more than zero
+func ParseSize(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	number, unit := value, int64(1)
+	for _, suffix := range []struct {
+		name string
+		unit int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(strings.ToUpper(value), suffix.name) {
+			number, unit = strings.TrimSpace(value[:len(value)-len(suffix.name)]), suffix.unit
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n <= 0 || n*float64(unit) >= 1<<62 {
+		return 0, fmt.Errorf("%q isn't a size like 10MB or 1GB", value)
+	}
+	return max(int64(n*float64(unit)), 1), nil
+}
+
+// userFile is the user's config, under $XDG_CONFIG_HOME or ~/.config
+func userFile() string {
+	return filepath.Join(userDir(), "config")
+}
+
+// ThemeDir holds the user's theme files, named <theme>.json
+func ThemeDir() string {
+	return filepath.Join(userDir(), "themes")
+}
+
+// userDir is quest's directory under $XDG_CONFIG_HOME or ~/.config
+func userDir() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return filepath.Join(".config", "quest")
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "quest")
+}
+
+// loadEnvironment reads the named environment's settings; a missing file
+// or environment leaves everything at its default
+func loadEnvironment(path, name string) (Environment, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Environment{}, nil
+	}
+	if err != nil {
+		return Environment{}, err
+	}
+
+	var environments map[string]json.RawMessage
+	if err := json.Unmarshal(data, &environments); err != nil {
+		return Environment{}, fmt.Errorf("%s: %w", path, err)
+	}
+	raw, ok := environments[name]
+	if !ok {
+		return Environment{}, nil
+	}
+
+	// Only tls and proxy belong to an environment, so typos are reported
+	var env Environment
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&env); err != nil {
+		return Environment{}, fmt.Errorf("%s (%s): %w", path, name, err)
+	}
+	return env, nil
+}
+
+// isSecret reports whether the value at path holds a password or token
+func isSecret(path []string) bool {
+	switch {
+	case path[0] == "tls" && path[len(path)-1] == "pkcs12_password":
+		return true
+	case path[0] == "headers" && len(path) == 2:
+		name := strings.ToLower(path[1])
+		return name == "authorization" || name == "proxy-authorization" || name == "cookie" ||
+			strings.Contains(name, "token") || strings.Contains(name, "secret") || strings.Contains(name, "key")
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,233 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// inTempDir runs the test in an empty directory with no user config and
+// none of quest's variables set
+func inTempDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+	for _, name := range []string{"QUEST_ENV", "QUEST_TIMEOUT", "QUEST_MAX_BODY", "QUEST_DEFAULT_HEADERS", "QUEST_USER_AGENT", "QUEST_THEME", "QUEST_COOKIES"} {
+		t.Setenv(name, "")
+	}
+	return dir
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name        string
+		userFile    string
+		projectFile string
+		env         map[string]string
+		args        []string
+		timeout     time.Duration
+		source      string
+	}{
+		{name: "default", timeout: 30 * time.Second, source: "default"},
+		{name: "user file", userFile: `{"timeout": "5s"}`, timeout: 5 * time.Second, source: "xdg/quest/config"},
+		{
+			name:        "project over user file",
+			userFile:    `{"timeout": "5s"}`,
+			projectFile: `{"timeout": 6}`,
+			timeout:     6 * time.Second,
+			source:      ProjectFile,
+		},
+		{
+			name:        "variable over files",
+			projectFile: `{"timeout": "6s"}`,
+			env:         map[string]string{"QUEST_TIMEOUT": "7s"},
+			timeout:     7 * time.Second,
+			source:      "$QUEST_TIMEOUT",
+		},
+		{
+			name:        "flag over variable and files",
+			projectFile: `{"timeout": "6s"}`,
+			env:         map[string]string{"QUEST_TIMEOUT": "7s"},
+			args:        []string{"-timeout", "8s"},
+			timeout:     8 * time.Second,
+			source:      "-timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := inTempDir(t)
+			if tt.userFile != "" {
+				writeFile(t, filepath.Join(dir, "xdg", "quest", "config"), tt.userFile)
+			}
+			if tt.projectFile != "" {
+				writeFile(t, ProjectFile, tt.projectFile)
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := Load(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Timeout != tt.timeout {
+				t.Errorf("timeout = %s, want %s", cfg.Timeout, tt.timeout)
+			}
+			if source := settingSource(cfg, "timeout"); !strings.HasSuffix(source, tt.source) {
+				t.Errorf("timeout set by %q, want %q", source, tt.source)
+			}
+		})
+	}
+}
+
+func settingSource(cfg Config, key string) string {
+	for _, setting := range cfg.Settings {
+		if setting.Key == key {
+			return setting.Source
+		}
+	}
+	return ""
+}
+
+func TestLoadMergesLayers(t *testing.T) {
+	inTempDir(t)
+	writeFile(t, ProjectFile, `{"headers": {"accept": "text/html", "X-Team": "a"}, "cookies": false}`)
+	writeFile(t, EnvironmentFile, `{"staging": {"tls": {"ca_file": "ca.pem"}}}`)
+	t.Setenv("QUEST_DEFAULT_HEADERS", "X-Team: b\nAccept: application/json, text/plain")
+	t.Setenv("QUEST_COOKIES", "on")
+
+	cfg, err := Load([]string{"-env", "staging", "-H", "X-Trace: 1", "-max-body", "1MB"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headers := map[string]string{"Accept": "application/json, text/plain", "X-Team": "b", "X-Trace": "1"}
+	if !reflect.DeepEqual(cfg.Headers, headers) {
+		t.Errorf("headers = %v, want %v", cfg.Headers, headers)
+	}
+	if !cfg.Cookies {
+		t.Error("$QUEST_COOKIES didn't override the project file")
+	}
+	if cfg.MaxBodyInMemory != 1<<20 {
+		t.Errorf("max body = %d, want %d", cfg.MaxBodyInMemory, 1<<20)
+	}
+	if cfg.TLS.CAFile != "ca.pem" {
+		t.Errorf("CA file = %q from the staging environment", cfg.TLS.CAFile)
+	}
+	if cfg.Environment != "staging" {
+		t.Errorf("environment = %q", cfg.Environment)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		projectFile string
+		environment string
+		env         map[string]string
+		args        []string
+		want        string
+	}{
+		{name: "unknown key", projectFile: `{"timeout": "5s", "colour": "red"}`, want: ProjectFile},
+		{name: "bad timeout", projectFile: `{"timeout": "soon"}`, want: "timeout (from .quest-config)"},
+		{name: "unknown environment key", environment: `{"default": {"tls": {"ca": "x.pem"}}}`, want: EnvironmentFile},
+		{name: "setting outside tls and proxy", environment: `{"default": {"timeout": "5s"}}`, want: EnvironmentFile},
+		{name: "malformed environments", environment: `{"default": `, want: EnvironmentFile},
+		{name: "bad variable", env: map[string]string{"QUEST_COOKIES": "maybe"}, want: "$QUEST_COOKIES"},
+		{name: "bad header variable", env: map[string]string{"QUEST_DEFAULT_HEADERS": "Accept"}, want: "$QUEST_DEFAULT_HEADERS"},
+		{name: "bad flag", args: []string{"-max-body", "0"}, want: "-max-body"},
+		{name: "unknown flag", args: []string{"-verbose"}, want: "verbose"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inTempDir(t)
+			if tt.projectFile != "" {
+				writeFile(t, ProjectFile, tt.projectFile)
+			}
+			if tt.environment != "" {
+				writeFile(t, EnvironmentFile, tt.environment)
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			_, err := Load(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() = %v, want an error naming %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value string
+		size  int64
+		ok    bool
+	}{
+		{"2048", 2048, true},
+		{"10MB", 10 << 20, true},
+		{"64mb", 64 << 20, true},
+		{"1.5GB", 3 << 29, true},
+		{"512 KB", 512 << 10, true},
+		{"100B", 100, true},
+		{"0", 0, false},
+		{"-1MB", 0, false},
+		{"MB", 0, false},
+		{"lots", 0, false},
+		{"1e30GB", 0, false},
+	}
+
+	for _, tt := range tests {
+		size, err := ParseSize(tt.value)
+		if size != tt.size || (err == nil) != tt.ok {
+			t.Errorf("ParseSize(%q) = %d, %v, want %d, ok %v", tt.value, size, err, tt.size, tt.ok)
+		}
+	}
+}
+
+func TestParseTimeout(t *testing.T) {
+	tests := []struct {
+		value   string
+		timeout time.Duration
+		ok      bool
+	}{
+		{"30s", 30 * time.Second, true},
+		{"2m", 2 * time.Minute, true},
+		{"1.5", 1500 * time.Millisecond, true},
+		{"0", 0, true},
+		{"-5s", 0, false},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		timeout, err := ParseTimeout(tt.value)
+		if timeout != tt.timeout || (err == nil) != tt.ok {
+			t.Errorf("ParseTimeout(%q) = %s, %v, want %s, ok %v", tt.value, timeout, err, tt.timeout, tt.ok)
+		}
+	}
+}
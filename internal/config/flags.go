@@ -0,0 +1,169 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Usage explains the command-line flags
+const Usage = `Usage: quest [flags]
+
+Flags override environment variables, which override .quest-environments,
+.quest-config and ~/.config/quest/config, in that order.
+
+  -env name          environment for .quest-environments and cookies ($QUEST_ENV)
+  -timeout duration  request timeout, e.g. 10s or 2m; 0 disables it ($QUEST_TIMEOUT)
+  -max-body size     response body held in memory before spilling to a temp file,
+                     e.g. 64MB ($QUEST_MAX_BODY)
+  -H 'Name: value'   header sent with every request, repeatable ($QUEST_DEFAULT_HEADERS,
+                     one 'Name: value' per line)
+  -user-agent value  User-Agent header ($QUEST_USER_AGENT)
+  -proxy url         proxy for every request, or "direct" to ignore $HTTP_PROXY
+  -no-proxy list     hosts, domains and CIDR ranges reached directly
+  -insecure          skip verifying server certificates
+  -cacert file       PEM bundle of extra trusted roots
+  -cert file         PEM client certificate
+  -key file          PEM client key
+  -theme name        dark, light, high-contrast, mono or a theme file ($QUEST_THEME)
+  -no-cookies        start with the cookie jar off ($QUEST_COOKIES=off)
+`
+
+// flagValues are the settings given on the command line, each keyed by the
+// flag that set it
+type flagValues struct {
+	environment string
+	values      map[string]any
+}
+
+// headerFlags collects repeated -H flags
+type headerFlags map[string]any
+
+func (h headerFlags) String() string { return fmt.Sprint(map[string]any(h)) }
+
+func (h headerFlags) Set(value string) error {
+	name, val, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("want 'Name: value', got %q", value)
+	}
+	h[strings.TrimSpace(name)] = strings.TrimSpace(val)
+	return nil
+}
+
+// parseFlags reads the command-line flags into a layer
+func parseFlags(args []string) (flagValues, error) {
+	fs := flag.NewFlagSet("quest", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var result flagValues
+	headers := headerFlags{}
+	fs.StringVar(&result.environment, "env", "", "")
+	timeout := fs.String("timeout", "", "")
+	maxBody := fs.String("max-body", "", "")
+	fs.Var(headers, "H", "")
+	fs.Var(headers, "header", "")
+	userAgent := fs.String("user-agent", "", "")
+	proxy := fs.String("proxy", "", "")
+	noProxy := fs.String("no-proxy", "", "")
+	insecure := fs.Bool("insecure", false, "")
+	caFile := fs.String("cacert", "", "")
+	certFile := fs.String("cert", "", "")
+	keyFile := fs.String("key", "", "")
+	theme := fs.String("theme", "", "")
+	noCookies := fs.Bool("no-cookies", false, "")
+	if err := fs.Parse(args); err != nil {
+		return flagValues{}, err
+	}
+	if fs.NArg() > 0 {
+		return flagValues{}, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	result.values = map[string]any{}
+	fs.Visit(func(f *flag.Flag) {
+		source := "-" + f.Name
+		switch f.Name {
+		case "timeout":
+			result.values[source] = map[string]any{"timeout": *timeout}
+		case "max-body":
+			result.values[source] = map[string]any{"max_body_in_memory": *maxBody}
+		case "H", "header":
+			result.values["-H"] = map[string]any{"headers": map[string]any(headers)}
+		case "user-agent":
+			result.values[source] = map[string]any{"user_agent": *userAgent}
+		case "proxy":
+			if strings.EqualFold(*proxy, "direct") {
+				result.values[source] = map[string]any{"proxy": map[string]any{"direct": true}}
+			} else {
+				result.values[source] = map[string]any{"proxy": map[string]any{"url": *proxy}}
+			}
+		case "no-proxy":
+			result.values[source] = map[string]any{"proxy": map[string]any{"no_proxy": *noProxy}}
+		case "insecure":
+			result.values[source] = map[string]any{"tls": map[string]any{"insecure": *insecure}}
+		case "cacert":
+			result.values[source] = map[string]any{"tls": map[string]any{"ca_file": *caFile}}
+		case "cert":
+			result.values[source] = map[string]any{"tls": map[string]any{"cert_file": *certFile}}
+		case "key":
+			result.values[source] = map[string]any{"tls": map[string]any{"key_file": *keyFile}}
+		case "theme":
+			result.values[source] = map[string]any{"theme": *theme}
+		case "no-cookies":
+			result.values[source] = map[string]any{"cookies": !*noCookies}
+		}
+	})
+	return result, nil
+}
+
+// environmentVariables reads the QUEST_* variables into a layer, each keyed
+// by the variable that set it
+func environmentVariables() (map[string]any, error) {
+	values := map[string]any{}
+	if timeout := os.Getenv("QUEST_TIMEOUT"); timeout != "" {
+		values["$QUEST_TIMEOUT"] = map[string]any{"timeout": timeout}
+	}
+	if maxBody := os.Getenv("QUEST_MAX_BODY"); maxBody != "" {
+		values["$QUEST_MAX_BODY"] = map[string]any{"max_body_in_memory": maxBody}
+	}
+	if list := os.Getenv("QUEST_DEFAULT_HEADERS"); list != "" {
+		headers := headerFlags{}
+		// Headers are split by line, since values may hold commas
+		for _, header := range strings.Split(list, "\n") {
+			if strings.TrimSpace(header) == "" {
+				continue
+			}
+			if err := headers.Set(header); err != nil {
+				return nil, fmt.Errorf("$QUEST_DEFAULT_HEADERS: %w", err)
+			}
+		}
+		values["$QUEST_DEFAULT_HEADERS"] = map[string]any{"headers": map[string]any(headers)}
+	}
+	if userAgent := os.Getenv("QUEST_USER_AGENT"); userAgent != "" {
+		values["$QUEST_USER_AGENT"] = map[string]any{"user_agent": userAgent}
+	}
+	if theme := os.Getenv("QUEST_THEME"); theme != "" {
+		values["$QUEST_THEME"] = map[string]any{"theme": theme}
+	}
+	if cookies := os.Getenv("QUEST_COOKIES"); cookies != "" {
+		on, err := parseSwitch(cookies)
+		if err != nil {
+			return nil, fmt.Errorf("$QUEST_COOKIES: %w", err)
+		}
+		values["$QUEST_COOKIES"] = map[string]any{"cookies": on}
+	}
+	return values, nil
+}
+
+// parseSwitch reads an on or off setting, also taken as true or false, 1 or
+// 0 and yes or no
+func parseSwitch(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "true", "1", "yes":
+		return true, nil
+	case "off", "false", "0", "no":
+		return false, nil
+	}
+	return false, fmt.Errorf("want on or off, got %q", value)
+}
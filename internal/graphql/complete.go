@@ -0,0 +1,232 @@
+package graphql
+
+import (
+	"sort"
+	"strings"
+)
+
+// Suggestion is a completion candidate
+type Suggestion struct {
+	Label  string
+	Detail string
+}
+
+// completionContext is what the cursor sits in, worked out by replaying the
+// tokens before it
+type completionContext struct {
+	// stack holds the parent type of each open selection set
+	stack []*Type
+
+	// pending is the type the next "{" opens: an operation root, a fragment
+	// type condition or the type of the last field
+	pending *Type
+
+	lastField *Field
+	argField  *Field
+	argName   string
+	inArgs    bool
+	// argNesting counts brackets and braces open inside an argument list
+	argNesting  int
+	expectValue bool
+	afterOn     bool
+}
+
+// Complete suggests what can be typed at cursor (a byte offset into src):
+// fields of the enclosing type, arguments inside parentheses, enum values
+// for enum arguments, type names after "on", and operation keywords at the
+// top level. It also returns the partial word before the cursor, which every
+// suggestion starts with.
+func Complete(schema *Schema, src string, cursor int) ([]Suggestion, string) {
+	cursor = max(0, min(cursor, len(src)))
+	before := src[:cursor]
+
+	start := len(before)
+	for start > 0 && isNameContinue(before[start-1]) {
+		start--
+	}
+	prefix := before[start:]
+
+	// Tokenize tolerates everything but unterminated strings, in which case
+	// there is nothing sensible to complete
+	tokens, err := Tokenize(before[:start])
+	if err != nil {
+		return nil, prefix
+	}
+
+	ctx := &completionContext{}
+	ctx.replay(schema, tokens)
+
+	var suggestions []Suggestion
+	switch {
+	case ctx.inArgs && ctx.expectValue && ctx.argNesting == 0:
+		if arg := findInputValue(ctx.argField.argsOrNil(), ctx.argName); arg != nil {
+			if typ := schema.Types[arg.Type.Named()]; typ != nil && typ.Kind == "ENUM" {
+				for _, value := range typ.EnumValues {
+					suggestions = append(suggestions, Suggestion{Label: value, Detail: typ.Name})
+				}
+			}
+		}
+
+	case ctx.inArgs && ctx.argNesting == 0:
+		for _, arg := range ctx.argField.argsOrNil() {
+			suggestions = append(suggestions, Suggestion{Label: arg.Name, Detail: arg.Type.String()})
+		}
+
+	case ctx.inArgs:
+		// Inside a list or input object value
+
+	case ctx.afterOn:
+		var names []string
+		for name, typ := range schema.Types {
+			if typ.IsComposite() && !strings.HasPrefix(name, "__") {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			suggestions = append(suggestions, Suggestion{Label: name, Detail: strings.ToLower(schema.Types[name].Kind)})
+		}
+
+	case len(ctx.stack) > 0:
+		parent := ctx.stack[len(ctx.stack)-1]
+		if parent != nil {
+			if parent.Kind != "UNION" {
+				for _, f := range parent.Fields {
+					suggestions = append(suggestions, Suggestion{Label: f.Name, Detail: f.Type.String()})
+				}
+			}
+			suggestions = append(suggestions, Suggestion{Label: "__typename", Detail: "String!"})
+		}
+
+	default:
+		for _, keyword := range []string{"query", "mutation", "subscription", "fragment"} {
+			suggestions = append(suggestions, Suggestion{Label: keyword, Detail: "keyword"})
+		}
+	}
+
+	var matching []Suggestion
+	for _, s := range suggestions {
+		if strings.HasPrefix(s.Label, prefix) && s.Label != prefix {
+			matching = append(matching, s)
+		}
+	}
+	return matching, prefix
+}
+
+func (f *Field) argsOrNil() []*InputValue {
+	if f == nil {
+		return nil
+	}
+	return f.Args
+}
+
+// replay walks the tokens before the cursor, tracking enclosing types
+func (c *completionContext) replay(schema *Schema, tokens []Token) {
+	for i, tok := range tokens {
+		if tok.Kind == EOF {
+			break
+		}
+		prev := Token{}
+		if i > 0 {
+			prev = tokens[i-1]
+		}
+
+		if c.inArgs {
+			c.replayArgument(tok)
+			continue
+		}
+
+		switch {
+		case tok.Kind == Name && len(c.stack) == 0 && prev.Kind != Name:
+			switch tok.Value {
+			case "query", "mutation", "subscription":
+				c.pending = schema.RootType(tok.Value)
+			}
+
+		case tok.Kind == Name && prev.Kind == Punct && prev.Value == "@":
+			// Directive name; the field it applies to stays current
+
+		case tok.Kind == Name && tok.Value == "on" && prev.Value != "on":
+			c.afterOn = true
+
+		case tok.Kind == Name && c.afterOn:
+			c.afterOn = false
+			c.pending = schema.Types[tok.Value]
+
+		case tok.Kind == Name && len(c.stack) > 0:
+			// A name followed by ":" is an alias; the field comes next
+			if next := tokens[i+1]; next.Kind == Punct && next.Value == ":" {
+				continue
+			}
+			if parent := c.stack[len(c.stack)-1]; parent != nil {
+				c.lastField = schema.Field(parent, tok.Value)
+			} else {
+				c.lastField = nil
+			}
+			c.pending = nil
+			if c.lastField != nil {
+				c.pending = schema.Types[c.lastField.Type.Named()]
+			}
+
+		case tok.Kind == Punct && tok.Value == "(" && c.lastField != nil && len(c.stack) > 0 && !isDirective(tokens, i):
+			c.inArgs = true
+			c.argField = c.lastField
+			c.expectValue = false
+
+		case tok.Kind == Punct && tok.Value == "(":
+			// Variable definitions or directive arguments; treat as opaque
+			c.inArgs = true
+			c.argField = nil
+
+		case tok.Kind == Punct && tok.Value == "{":
+			if len(c.stack) == 0 && c.pending == nil {
+				c.pending = schema.RootType("query")
+			}
+			c.stack = append(c.stack, c.pending)
+			c.pending = nil
+			c.lastField = nil
+
+		case tok.Kind == Punct && tok.Value == "}":
+			if len(c.stack) > 0 {
+				c.stack = c.stack[:len(c.stack)-1]
+			}
+			c.lastField = nil
+			c.pending = nil
+
+		case tok.Kind == Punct && tok.Value == "...":
+			c.lastField = nil
+			if len(c.stack) > 0 {
+				c.pending = c.stack[len(c.stack)-1]
+			}
+		}
+	}
+}
+
+// isDirective reports whether the "(" at tokens[i] opens directive arguments
+func isDirective(tokens []Token, i int) bool {
+	return i >= 2 && tokens[i-2].Kind == Punct && tokens[i-2].Value == "@"
+}
+
+// replayArgument follows an argument list: name, colon, value
+func (c *completionContext) replayArgument(tok Token) {
+	switch {
+	case tok.Kind == Punct && (tok.Value == "[" || tok.Value == "{"):
+		c.argNesting++
+	case tok.Kind == Punct && (tok.Value == "]" || tok.Value == "}"):
+		c.argNesting--
+		if c.argNesting == 0 {
+			c.expectValue = false
+		}
+	case c.argNesting > 0:
+	case tok.Kind == Punct && tok.Value == ")":
+		c.inArgs = false
+		c.expectValue = false
+	case tok.Kind == Punct && tok.Value == ":":
+		c.expectValue = true
+	case tok.Kind == Punct && tok.Value == "$":
+	case tok.Kind == Name && !c.expectValue:
+		c.argName = tok.Value
+	default:
+		c.expectValue = false
+	}
+}
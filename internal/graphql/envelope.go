@@ -0,0 +1,126 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Envelope is the JSON body of a GraphQL request over HTTP
+type Envelope struct {
+	Query         string          `json:"query"`
+	Variables     json.RawMessage `json:"variables,omitempty"`
+	OperationName string          `json:"operationName,omitempty"`
+}
+
+// NewEnvelope builds the request body for query. variables may be empty or
+// must be a JSON object. The operation name is taken from the first named
+// operation so documents with several operations still run the first.
+func NewEnvelope(query, variables string) ([]byte, error) {
+	envelope := Envelope{Query: query}
+
+	if vars := strings.TrimSpace(variables); vars != "" {
+		var object map[string]any
+		if err := json.Unmarshal([]byte(vars), &object); err != nil {
+			return nil, fmt.Errorf("variables must be a JSON object: %w", err)
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, []byte(vars)); err != nil {
+			return nil, err
+		}
+		envelope.Variables = compact.Bytes()
+	}
+
+	if doc, err := Parse(query); err == nil && len(doc.Operations) > 1 {
+		envelope.OperationName = doc.Operations[0].Name
+	}
+
+	return json.Marshal(envelope)
+}
+
+// ParseEnvelope splits a request body back into its query and pretty
+// printed variables
+func ParseEnvelope(body string) (query, variables string, err error) {
+	var envelope Envelope
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		return "", "", err
+	}
+	if envelope.Query == "" {
+		return "", "", errors.New("body has no query")
+	}
+
+	if len(envelope.Variables) > 0 && string(envelope.Variables) != "null" {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, envelope.Variables, "", "  "); err == nil {
+			variables = indented.String()
+		}
+	}
+	return envelope.Query, variables, nil
+}
+
+// Response is a GraphQL response: data and/or a list of errors
+type Response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []ResponseError `json:"errors"`
+}
+
+// ResponseError is an entry of a response's errors array
+type ResponseError struct {
+	Message   string `json:"message"`
+	Locations []struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	} `json:"locations"`
+	Path       []any          `json:"path"`
+	Extensions map[string]any `json:"extensions"`
+}
+
+// PathString renders an error path like user.friends[0].name
+func (e ResponseError) PathString() string {
+	var b strings.Builder
+	for _, segment := range e.Path {
+		switch s := segment.(type) {
+		case float64:
+			fmt.Fprintf(&b, "[%d]", int(s))
+		default:
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			fmt.Fprint(&b, s)
+		}
+	}
+	return b.String()
+}
+
+// ParseResponse recognises a GraphQL response body: a JSON object with only
+// data, errors and extensions keys, where errors (if present) is a list of
+// objects with a message
+func ParseResponse(body string) (*Response, bool) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body), &raw); err != nil {
+		return nil, false
+	}
+	_, hasData := raw["data"]
+	_, hasErrors := raw["errors"]
+	if !hasData && !hasErrors {
+		return nil, false
+	}
+	for key := range raw {
+		if key != "data" && key != "errors" && key != "extensions" {
+			return nil, false
+		}
+	}
+
+	var resp Response
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return nil, false
+	}
+	for _, e := range resp.Errors {
+		if e.Message == "" {
+			return nil, false
+		}
+	}
+	return &resp, true
+}
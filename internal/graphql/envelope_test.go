@@ -0,0 +1,113 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewEnvelope(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables string
+		want      string
+		err       string
+	}{
+		{
+			name:  "query only",
+			query: "{ a }",
+			want:  `{"query":"{ a }"}`,
+		},
+		{
+			name:      "variables are compacted",
+			query:     "query($id: ID) { a }",
+			variables: "{\n  \"id\": 1\n}",
+			want:      `{"query":"query($id: ID) { a }","variables":{"id":1}}`,
+		},
+		{
+			name:      "blank variables",
+			query:     "{ a }",
+			variables: "  \n",
+			want:      `{"query":"{ a }"}`,
+		},
+		{
+			name:  "first of several operations",
+			query: "query One { a } query Two { b }",
+			want:  `{"query":"query One { a } query Two { b }","operationName":"One"}`,
+		},
+		{
+			name:      "variables must be an object",
+			query:     "{ a }",
+			variables: "[1]",
+			err:       "variables must be a JSON object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEnvelope(tt.query, tt.variables)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("NewEnvelope error = %v, want one mentioning %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil || string(got) != tt.want {
+				t.Errorf("NewEnvelope = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEnvelope(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		query     string
+		variables string
+		err       bool
+	}{
+		{"query and variables", `{"query":"{ a }","variables":{"id":1}}`, "{ a }", "{\n  \"id\": 1\n}", false},
+		{"null variables", `{"query":"{ a }","variables":null}`, "{ a }", "", false},
+		{"no query", `{"variables":{}}`, "", "", true},
+		{"not JSON", `query { a }`, "", "", true},
+	}
+
+	for _, tt := range tests {
+		query, variables, err := ParseEnvelope(tt.body)
+		if (err != nil) != tt.err || query != tt.query || variables != tt.variables {
+			t.Errorf("%s: ParseEnvelope = %q, %q, %v", tt.name, query, variables, err)
+		}
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		ok     bool
+		errors int
+	}{
+		{"data", `{"data":{"a":1}}`, true, 0},
+		{"errors", `{"errors":[{"message":"boom","path":["a",0,"b"]}]}`, true, 1},
+		{"data, errors and extensions", `{"data":null,"errors":[{"message":"x"}],"extensions":{}}`, true, 1},
+		{"other keys", `{"data":{},"status":"ok"}`, false, 0},
+		{"error without a message", `{"errors":[{"code":1}]}`, false, 0},
+		{"plain JSON", `{"a":1}`, false, 0},
+		{"array", `[{"data":1}]`, false, 0},
+	}
+
+	for _, tt := range tests {
+		resp, ok := ParseResponse(tt.body)
+		if ok != tt.ok || (ok && len(resp.Errors) != tt.errors) {
+			t.Errorf("%s: ParseResponse = %+v, %v, want ok %v with %d errors", tt.name, resp, ok, tt.ok, tt.errors)
+		}
+	}
+}
+
+func TestResponseErrorPathString(t *testing.T) {
+	e := ResponseError{Path: []any{"user", "friends", float64(0), "name"}}
+	if got := e.PathString(); got != "user.friends[0].name" {
+		t.Errorf("PathString = %q, want user.friends[0].name", got)
+	}
+}
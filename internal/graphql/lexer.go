@@ -0,0 +1,166 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TokenKind classifies a lexical token of a GraphQL document
+type TokenKind int
+
+const (
+	EOF TokenKind = iota
+	Punct
+	Name
+	IntValue
+	FloatValue
+	StringValue
+)
+
+// Token is a lexical token with its position in the source
+type Token struct {
+	Kind  TokenKind
+	Value string
+	Pos   Position
+}
+
+// Position locates a token: Offset is a byte offset, Line and Column are
+// 1-based
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// Error is a syntax or validation error at a position in the document
+type Error struct {
+	Message string
+	Pos     Position
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Pos.Line, e.Pos.Column, e.Message)
+}
+
+// Tokenize splits a document into tokens. Whitespace, commas and comments
+// are skipped. The final token is always EOF.
+func Tokenize(src string) ([]Token, error) {
+	var tokens []Token
+
+	line, lineStart := 1, 0
+	pos := func(i int) Position {
+		return Position{Offset: i, Line: line, Column: i - lineStart + 1}
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == '\n':
+			i++
+			line, lineStart = line+1, i
+
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			i++
+
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+
+		case strings.HasPrefix(src[i:], "\ufeff"):
+			i += len("\ufeff")
+
+		case strings.HasPrefix(src[i:], "..."):
+			tokens = append(tokens, Token{Kind: Punct, Value: "...", Pos: pos(i)})
+			i += 3
+
+		case strings.IndexByte("!$&():=@[]{|}", c) >= 0:
+			tokens = append(tokens, Token{Kind: Punct, Value: string(c), Pos: pos(i)})
+			i++
+
+		case isNameStart(c):
+			start := i
+			for i < len(src) && isNameContinue(src[i]) {
+				i++
+			}
+			tokens = append(tokens, Token{Kind: Name, Value: src[start:i], Pos: pos(start)})
+
+		case c == '-' || isDigit(c):
+			start := i
+			kind := IntValue
+			if c == '-' {
+				i++
+			}
+			for i < len(src) && isDigit(src[i]) {
+				i++
+			}
+			if i < len(src) && src[i] == '.' {
+				kind = FloatValue
+				i++
+				for i < len(src) && isDigit(src[i]) {
+					i++
+				}
+			}
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				kind = FloatValue
+				i++
+				if i < len(src) && (src[i] == '+' || src[i] == '-') {
+					i++
+				}
+				for i < len(src) && isDigit(src[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, Token{Kind: kind, Value: src[start:i], Pos: pos(start)})
+
+		case strings.HasPrefix(src[i:], `"""`):
+			start := pos(i)
+			end := strings.Index(src[i+3:], `"""`)
+			if end < 0 {
+				return tokens, &Error{Message: "unterminated block string", Pos: start}
+			}
+			value := src[i+3 : i+3+end]
+			for j := i; j < i+3+end; j++ {
+				if src[j] == '\n' {
+					line, lineStart = line+1, j+1
+				}
+			}
+			tokens = append(tokens, Token{Kind: StringValue, Value: value, Pos: start})
+			i += 6 + end
+
+		case c == '"':
+			start := i
+			i++
+			for i < len(src) && src[i] != '"' && src[i] != '\n' {
+				if src[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(src) || src[i] != '"' {
+				return tokens, &Error{Message: "unterminated string", Pos: pos(start)}
+			}
+			i++
+			tokens = append(tokens, Token{Kind: StringValue, Value: src[start+1 : i-1], Pos: pos(start)})
+
+		default:
+			return tokens, &Error{Message: fmt.Sprintf("unexpected character %q", c), Pos: pos(i)}
+		}
+	}
+
+	tokens = append(tokens, Token{Kind: EOF, Pos: pos(len(src))})
+	return tokens, nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameContinue(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,105 @@
+package graphql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []Token
+	}{
+		{
+			name: "punctuation and names",
+			src:  "query($a: [Int!]) { ...f }",
+			want: []Token{
+				{Name, "query", Position{0, 1, 1}},
+				{Punct, "(", Position{5, 1, 6}},
+				{Punct, "$", Position{6, 1, 7}},
+				{Name, "a", Position{7, 1, 8}},
+				{Punct, ":", Position{8, 1, 9}},
+				{Punct, "[", Position{10, 1, 11}},
+				{Name, "Int", Position{11, 1, 12}},
+				{Punct, "!", Position{14, 1, 15}},
+				{Punct, "]", Position{15, 1, 16}},
+				{Punct, ")", Position{16, 1, 17}},
+				{Punct, "{", Position{18, 1, 19}},
+				{Punct, "...", Position{20, 1, 21}},
+				{Name, "f", Position{23, 1, 24}},
+				{Punct, "}", Position{25, 1, 26}},
+				{EOF, "", Position{26, 1, 27}},
+			},
+		},
+		{
+			name: "numbers",
+			src:  "-1 2.5 3e10 4.0E-2",
+			want: []Token{
+				{IntValue, "-1", Position{0, 1, 1}},
+				{FloatValue, "2.5", Position{3, 1, 4}},
+				{FloatValue, "3e10", Position{7, 1, 8}},
+				{FloatValue, "4.0E-2", Position{12, 1, 13}},
+				{EOF, "", Position{18, 1, 19}},
+			},
+		},
+		{
+			name: "strings keep their escapes",
+			src:  `"a\"b" "é"`,
+			want: []Token{
+				{StringValue, `a\"b`, Position{0, 1, 1}},
+				{StringValue, "é", Position{7, 1, 8}},
+				{EOF, "", Position{11, 1, 12}},
+			},
+		},
+		{
+			name: "block strings count lines",
+			src:  "\"\"\"one\ntwo\"\"\"\nx",
+			want: []Token{
+				{StringValue, "one\ntwo", Position{0, 1, 1}},
+				{Name, "x", Position{14, 3, 1}},
+				{EOF, "", Position{15, 3, 2}},
+			},
+		},
+		{
+			name: "commas, comments and a byte order mark are skipped",
+			src:  "\ufeffa, # comment\n b",
+			want: []Token{
+				{Name, "a", Position{3, 1, 4}},
+				{Name, "b", Position{17, 2, 2}},
+				{EOF, "", Position{18, 2, 3}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Tokenize(tt.src)
+			if err != nil {
+				t.Fatalf("Tokenize(%q): %v", tt.src, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize(%q) =\n%v\nwant\n%v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`"open`, "1:1: unterminated string"},
+		{"\"line\nbreak\"", "1:1: unterminated string"},
+		{`x """block`, "1:3: unterminated block string"},
+		{"a\n  ?", `2:3: unexpected character '?'`},
+	}
+
+	for _, tt := range tests {
+		_, err := Tokenize(tt.src)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Tokenize(%q) error = %v, want %s", tt.src, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,462 @@
+package graphql
+
+import "fmt"
+
+// Document is a parsed executable GraphQL document
+type Document struct {
+	Operations []*Operation
+	Fragments  []*Fragment
+}
+
+// Operation is a query, mutation or subscription definition
+type Operation struct {
+	Type       string
+	Name       string
+	Variables  []*VariableDefinition
+	Selections []*Selection
+	Pos        Position
+}
+
+// VariableDefinition declares an operation variable such as $id: ID!
+type VariableDefinition struct {
+	Name string
+	Type string
+	Pos  Position
+}
+
+// Fragment is a named fragment definition
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Selections    []*Selection
+	Pos           Position
+}
+
+// SelectionKind distinguishes the three kinds of selection
+type SelectionKind int
+
+const (
+	FieldSelection SelectionKind = iota
+	FragmentSpread
+	InlineFragment
+)
+
+// Selection is a field, fragment spread or inline fragment
+type Selection struct {
+	Kind SelectionKind
+	// Name is the field name, or the fragment name for spreads
+	Name      string
+	Alias     string
+	Arguments []*Argument
+	// TypeCondition is set for inline fragments with "on Type"
+	TypeCondition string
+	// Selections is nil when the selection has no selection set
+	Selections []*Selection
+	Pos        Position
+}
+
+// Argument is a field argument; Variables lists any $variables its value
+// refers to
+type Argument struct {
+	Name      string
+	Variables []Token
+	Pos       Position
+}
+
+type parser struct {
+	tokens []Token
+	i      int
+}
+
+// Parse parses an executable document: operations and fragments
+func Parse(src string) (*Document, error) {
+	tokens, err := Tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	doc := &Document{}
+
+	for p.peek().Kind != EOF {
+		tok := p.peek()
+		switch {
+		case tok.Kind == Punct && tok.Value == "{":
+			selections, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &Operation{Type: "query", Selections: selections, Pos: tok.Pos})
+
+		case tok.Kind == Name && (tok.Value == "query" || tok.Value == "mutation" || tok.Value == "subscription"):
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+
+		case tok.Kind == Name && tok.Value == "fragment":
+			fragment, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			doc.Fragments = append(doc.Fragments, fragment)
+
+		default:
+			return nil, p.unexpected("an operation or fragment")
+		}
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, &Error{Message: "document has no operation", Pos: p.peek().Pos}
+	}
+	return doc, nil
+}
+
+func (p *parser) peek() Token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() Token {
+	tok := p.tokens[p.i]
+	if tok.Kind != EOF {
+		p.i++
+	}
+	return tok
+}
+
+func (p *parser) isPunct(value string) bool {
+	tok := p.peek()
+	return tok.Kind == Punct && tok.Value == value
+}
+
+func (p *parser) expectPunct(value string) (Token, error) {
+	if !p.isPunct(value) {
+		return Token{}, p.unexpected(fmt.Sprintf("%q", value))
+	}
+	return p.next(), nil
+}
+
+func (p *parser) expectName() (Token, error) {
+	if p.peek().Kind != Name {
+		return Token{}, p.unexpected("a name")
+	}
+	return p.next(), nil
+}
+
+func (p *parser) unexpected(want string) error {
+	tok := p.peek()
+	got := fmt.Sprintf("%q", tok.Value)
+	if tok.Kind == EOF {
+		got = "end of document"
+	}
+	return &Error{Message: fmt.Sprintf("expected %s, found %s", want, got), Pos: tok.Pos}
+}
+
+func (p *parser) operation() (*Operation, error) {
+	start := p.next()
+	op := &Operation{Type: start.Value, Pos: start.Pos}
+
+	if p.peek().Kind == Name {
+		op.Name = p.next().Value
+	}
+
+	if p.isPunct("(") {
+		p.next()
+		for !p.isPunct(")") {
+			def, err := p.variableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			op.Variables = append(op.Variables, def)
+		}
+		p.next()
+	}
+
+	if err := p.directives(); err != nil {
+		return nil, err
+	}
+
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.Selections = selections
+	return op, nil
+}
+
+func (p *parser) variableDefinition() (*VariableDefinition, error) {
+	dollar, err := p.expectPunct("$")
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expectPunct(":"); err != nil {
+		return nil, err
+	}
+	typ, err := p.typeReference()
+	if err != nil {
+		return nil, err
+	}
+	if p.isPunct("=") {
+		p.next()
+		if _, err := p.value(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.directives(); err != nil {
+		return nil, err
+	}
+
+	return &VariableDefinition{Name: name.Value, Type: typ, Pos: dollar.Pos}, nil
+}
+
+// typeReference parses a type such as [String!]! and returns it as written
+func (p *parser) typeReference() (string, error) {
+	var typ string
+	if p.isPunct("[") {
+		p.next()
+		inner, err := p.typeReference()
+		if err != nil {
+			return "", err
+		}
+		if _, err := p.expectPunct("]"); err != nil {
+			return "", err
+		}
+		typ = "[" + inner + "]"
+	} else {
+		name, err := p.expectName()
+		if err != nil {
+			return "", err
+		}
+		typ = name.Value
+	}
+
+	if p.isPunct("!") {
+		p.next()
+		typ += "!"
+	}
+	return typ, nil
+}
+
+func (p *parser) fragment() (*Fragment, error) {
+	start := p.next()
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if on := p.peek(); on.Kind != Name || on.Value != "on" {
+		return nil, p.unexpected(`"on"`)
+	}
+	p.next()
+	typ, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.directives(); err != nil {
+		return nil, err
+	}
+
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Fragment{Name: name.Value, TypeCondition: typ.Value, Selections: selections, Pos: start.Pos}, nil
+}
+
+func (p *parser) selectionSet() ([]*Selection, error) {
+	if _, err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+
+	selections := []*Selection{}
+	for !p.isPunct("}") {
+		if p.peek().Kind == EOF {
+			return nil, p.unexpected(`"}"`)
+		}
+		selection, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	p.next()
+
+	if len(selections) == 0 {
+		return nil, &Error{Message: "selection set cannot be empty", Pos: p.tokens[p.i-1].Pos}
+	}
+	return selections, nil
+}
+
+func (p *parser) selection() (*Selection, error) {
+	if p.isPunct("...") {
+		return p.fragmentSelection()
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	selection := &Selection{Kind: FieldSelection, Name: name.Value, Pos: name.Pos}
+
+	if p.isPunct(":") {
+		p.next()
+		field, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		selection.Alias, selection.Name, selection.Pos = name.Value, field.Value, field.Pos
+	}
+
+	if p.isPunct("(") {
+		p.next()
+		for !p.isPunct(")") {
+			argName, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expectPunct(":"); err != nil {
+				return nil, err
+			}
+			variables, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			selection.Arguments = append(selection.Arguments, &Argument{Name: argName.Value, Variables: variables, Pos: argName.Pos})
+		}
+		p.next()
+	}
+
+	if err := p.directives(); err != nil {
+		return nil, err
+	}
+
+	if p.isPunct("{") {
+		selections, err := p.selectionSet()
+		if err != nil {
+			return nil, err
+		}
+		selection.Selections = selections
+	}
+
+	return selection, nil
+}
+
+func (p *parser) fragmentSelection() (*Selection, error) {
+	dots := p.next()
+
+	if tok := p.peek(); tok.Kind == Name && tok.Value != "on" {
+		p.next()
+		if err := p.directives(); err != nil {
+			return nil, err
+		}
+		return &Selection{Kind: FragmentSpread, Name: tok.Value, Pos: tok.Pos}, nil
+	}
+
+	selection := &Selection{Kind: InlineFragment, Pos: dots.Pos}
+	if tok := p.peek(); tok.Kind == Name && tok.Value == "on" {
+		p.next()
+		typ, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		selection.TypeCondition = typ.Value
+	}
+	if err := p.directives(); err != nil {
+		return nil, err
+	}
+
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	selection.Selections = selections
+	return selection, nil
+}
+
+func (p *parser) directives() error {
+	for p.isPunct("@") {
+		p.next()
+		if _, err := p.expectName(); err != nil {
+			return err
+		}
+		if p.isPunct("(") {
+			p.next()
+			for !p.isPunct(")") {
+				if _, err := p.expectName(); err != nil {
+					return err
+				}
+				if _, err := p.expectPunct(":"); err != nil {
+					return err
+				}
+				if _, err := p.value(); err != nil {
+					return err
+				}
+			}
+			p.next()
+		}
+	}
+	return nil
+}
+
+// value skips over a value, returning the variables it refers to
+func (p *parser) value() ([]Token, error) {
+	tok := p.peek()
+
+	switch {
+	case tok.Kind == Punct && tok.Value == "$":
+		p.next()
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		name.Pos = tok.Pos
+		return []Token{name}, nil
+
+	case tok.Kind == Punct && tok.Value == "[":
+		p.next()
+		var variables []Token
+		for !p.isPunct("]") {
+			if p.peek().Kind == EOF {
+				return nil, p.unexpected(`"]"`)
+			}
+			inner, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			variables = append(variables, inner...)
+		}
+		p.next()
+		return variables, nil
+
+	case tok.Kind == Punct && tok.Value == "{":
+		p.next()
+		var variables []Token
+		for !p.isPunct("}") {
+			if _, err := p.expectName(); err != nil {
+				return nil, err
+			}
+			if _, err := p.expectPunct(":"); err != nil {
+				return nil, err
+			}
+			inner, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			variables = append(variables, inner...)
+		}
+		p.next()
+		return variables, nil
+
+	case tok.Kind == Name || tok.Kind == IntValue || tok.Kind == FloatValue || tok.Kind == StringValue:
+		p.next()
+		return nil, nil
+	}
+
+	return nil, p.unexpected("a value")
+}
@@ -0,0 +1,242 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// IntrospectionQuery fetches everything validation and completion need
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      kind
+      name
+      description
+      fields(includeDeprecated: true) {
+        name
+        description
+        args { ...InputValue }
+        type { ...TypeRef }
+      }
+      inputFields { ...InputValue }
+      enumValues(includeDeprecated: true) { name }
+      possibleTypes { name }
+    }
+  }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType { kind name }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// Schema is the subset of an introspected schema used for validation and
+// completion
+type Schema struct {
+	QueryType        string
+	MutationType     string
+	SubscriptionType string
+	Types            map[string]*Type
+}
+
+// Type is a named type in the schema
+type Type struct {
+	Kind          string
+	Name          string
+	Description   string
+	Fields        []*Field
+	InputFields   []*InputValue
+	EnumValues    []string
+	PossibleTypes []string
+}
+
+// Field is a field of an object or interface type
+type Field struct {
+	Name        string
+	Description string
+	Args        []*InputValue
+	Type        *TypeRef
+}
+
+// InputValue is an argument or input object field
+type InputValue struct {
+	Name         string
+	Description  string
+	Type         *TypeRef
+	DefaultValue *string
+}
+
+// TypeRef is a possibly wrapped reference to a named type
+type TypeRef struct {
+	Kind   string
+	Name   string
+	OfType *TypeRef
+}
+
+// Named unwraps list and non-null wrappers to the underlying type name
+func (t *TypeRef) Named() string {
+	for t != nil {
+		if t.Name != "" {
+			return t.Name
+		}
+		t = t.OfType
+	}
+	return ""
+}
+
+// NonNull reports whether the reference is a non-null type
+func (t *TypeRef) NonNull() bool {
+	return t != nil && t.Kind == "NON_NULL"
+}
+
+// String renders the reference in SDL notation, e.g. [User!]!
+func (t *TypeRef) String() string {
+	if t == nil {
+		return ""
+	}
+	switch t.Kind {
+	case "NON_NULL":
+		return t.OfType.String() + "!"
+	case "LIST":
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+// ParseSchema reads the response to IntrospectionQuery
+func ParseSchema(body []byte) (*Schema, error) {
+	var resp struct {
+		Data *struct {
+			Schema *struct {
+				QueryType        *struct{ Name string } `json:"queryType"`
+				MutationType     *struct{ Name string } `json:"mutationType"`
+				SubscriptionType *struct{ Name string } `json:"subscriptionType"`
+				Types            []struct {
+					Kind          string        `json:"kind"`
+					Name          string        `json:"name"`
+					Description   string        `json:"description"`
+					Fields        []*Field      `json:"fields"`
+					InputFields   []*InputValue `json:"inputFields"`
+					EnumValues    []struct{ Name string }
+					PossibleTypes []struct{ Name string }
+				} `json:"types"`
+			} `json:"__schema"`
+		} `json:"data"`
+		Errors []ResponseError `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("invalid introspection response: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("introspection failed: %s", resp.Errors[0].Message)
+	}
+	if resp.Data == nil || resp.Data.Schema == nil {
+		return nil, errors.New("introspection response has no __schema")
+	}
+
+	raw := resp.Data.Schema
+	schema := &Schema{Types: make(map[string]*Type, len(raw.Types))}
+	if raw.QueryType != nil {
+		schema.QueryType = raw.QueryType.Name
+	}
+	if raw.MutationType != nil {
+		schema.MutationType = raw.MutationType.Name
+	}
+	if raw.SubscriptionType != nil {
+		schema.SubscriptionType = raw.SubscriptionType.Name
+	}
+
+	for _, t := range raw.Types {
+		typ := &Type{
+			Kind:        t.Kind,
+			Name:        t.Name,
+			Description: t.Description,
+			Fields:      t.Fields,
+			InputFields: t.InputFields,
+		}
+		for _, v := range t.EnumValues {
+			typ.EnumValues = append(typ.EnumValues, v.Name)
+		}
+		for _, v := range t.PossibleTypes {
+			typ.PossibleTypes = append(typ.PossibleTypes, v.Name)
+		}
+		schema.Types[t.Name] = typ
+	}
+
+	return schema, nil
+}
+
+// RootType returns the root type for an operation type
+func (s *Schema) RootType(operation string) *Type {
+	switch operation {
+	case "mutation":
+		return s.Types[s.MutationType]
+	case "subscription":
+		return s.Types[s.SubscriptionType]
+	}
+	return s.Types[s.QueryType]
+}
+
+// Field looks up a field on a type, including the __typename meta field
+// available everywhere and the __schema and __type fields on the query root
+func (s *Schema) Field(t *Type, name string) *Field {
+	if t == nil {
+		return nil
+	}
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	switch {
+	case name == "__typename":
+		return &Field{Name: name, Type: &TypeRef{Kind: "NON_NULL", OfType: &TypeRef{Kind: "SCALAR", Name: "String"}}}
+	case name == "__schema" && t.Name == s.QueryType:
+		return &Field{Name: name, Type: &TypeRef{Kind: "NON_NULL", OfType: &TypeRef{Kind: "OBJECT", Name: "__Schema"}}}
+	case name == "__type" && t.Name == s.QueryType:
+		return &Field{
+			Name: name,
+			Args: []*InputValue{{Name: "name", Type: &TypeRef{Kind: "NON_NULL", OfType: &TypeRef{Kind: "SCALAR", Name: "String"}}}},
+			Type: &TypeRef{Kind: "OBJECT", Name: "__Type"},
+		}
+	}
+	return nil
+}
+
+// IsComposite reports whether values of t have fields to select
+func (t *Type) IsComposite() bool {
+	return t != nil && (t.Kind == "OBJECT" || t.Kind == "INTERFACE" || t.Kind == "UNION")
+}
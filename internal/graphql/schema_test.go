@@ -0,0 +1,98 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+)
+
+// testIntrospection is a small schema in the shape of an introspection
+// response:
+//
+//	type Query { user(id: ID!): User  users(role: Role = MEMBER): [User!]!  search(term: String!): [SearchResult] }
+//	type Mutation { rename(id: ID!, name: String!): User }
+//	type User { id: ID!  name: String  friends: [User] }
+//	enum Role { ADMIN MEMBER }
+//	union SearchResult = User
+const testIntrospection = `{"data": {"__schema": {
+  "queryType": {"name": "Query"},
+  "mutationType": {"name": "Mutation"},
+  "subscriptionType": null,
+  "types": [
+    {"kind": "OBJECT", "name": "Query", "fields": [
+      {"name": "user", "args": [{"name": "id", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}}],
+       "type": {"kind": "OBJECT", "name": "User"}},
+      {"name": "users", "args": [{"name": "role", "type": {"kind": "ENUM", "name": "Role"}, "defaultValue": "MEMBER"}],
+       "type": {"kind": "NON_NULL", "ofType": {"kind": "LIST", "ofType": {"kind": "NON_NULL", "ofType": {"kind": "OBJECT", "name": "User"}}}}},
+      {"name": "search", "args": [{"name": "term", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "String"}}}],
+       "type": {"kind": "LIST", "ofType": {"kind": "UNION", "name": "SearchResult"}}}
+    ]},
+    {"kind": "OBJECT", "name": "Mutation", "fields": [
+      {"name": "rename", "args": [
+        {"name": "id", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}},
+        {"name": "name", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "String"}}}
+      ], "type": {"kind": "OBJECT", "name": "User"}}
+    ]},
+    {"kind": "OBJECT", "name": "User", "fields": [
+      {"name": "id", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}},
+      {"name": "name", "args": [], "type": {"kind": "SCALAR", "name": "String"}},
+      {"name": "friends", "args": [], "type": {"kind": "LIST", "ofType": {"kind": "OBJECT", "name": "User"}}}
+    ]},
+    {"kind": "ENUM", "name": "Role", "enumValues": [{"name": "ADMIN"}, {"name": "MEMBER"}]},
+    {"kind": "UNION", "name": "SearchResult", "possibleTypes": [{"name": "User"}]},
+    {"kind": "SCALAR", "name": "ID"},
+    {"kind": "SCALAR", "name": "String"}
+  ]
+}}}`
+
+func testSchema(t *testing.T) *Schema {
+	t.Helper()
+	schema, err := ParseSchema([]byte(testIntrospection))
+	if err != nil {
+		t.Fatalf("ParseSchema: %v", err)
+	}
+	return schema
+}
+
+func TestParseSchema(t *testing.T) {
+	schema := testSchema(t)
+
+	if schema.QueryType != "Query" || schema.MutationType != "Mutation" || schema.SubscriptionType != "" {
+		t.Errorf("root types = %q, %q, %q", schema.QueryType, schema.MutationType, schema.SubscriptionType)
+	}
+	if schema.RootType("subscription") != nil {
+		t.Error("RootType(subscription) is set for a schema without subscriptions")
+	}
+
+	users := schema.Field(schema.RootType("query"), "users")
+	if users == nil || users.Type.String() != "[User!]!" || users.Type.Named() != "User" || !users.Type.NonNull() {
+		t.Errorf("Query.users = %+v, want a [User!]! field", users)
+	}
+	if role := schema.Types["Role"]; role == nil || strings.Join(role.EnumValues, ",") != "ADMIN,MEMBER" {
+		t.Errorf("Role = %+v, want the ADMIN and MEMBER values", role)
+	}
+	if schema.Field(schema.Types["User"], "__typename") == nil {
+		t.Error("__typename isn't available on User")
+	}
+	if schema.Field(schema.Types["User"], "__schema") != nil {
+		t.Error("__schema is available outside the query root")
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  string
+	}{
+		{"not JSON", "<html>", "invalid introspection response"},
+		{"errors", `{"errors": [{"message": "introspection disabled"}]}`, "introspection disabled"},
+		{"no schema", `{"data": {}}`, "no __schema"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseSchema([]byte(tt.body))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: ParseSchema error = %v, want one mentioning %q", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Validate checks a document against the schema: fields and arguments must
+// exist, required arguments must be given, leaf fields can't have selections
+// and object fields must have them, and fragments and variables must be
+// defined. A syntax error is returned as the only error.
+func Validate(schema *Schema, src string) []*Error {
+	doc, err := Parse(src)
+	if err != nil {
+		if e, ok := err.(*Error); ok {
+			return []*Error{e}
+		}
+		return []*Error{{Message: err.Error()}}
+	}
+
+	v := &validator{schema: schema, fragments: make(map[string]*Fragment)}
+	for _, fragment := range doc.Fragments {
+		v.fragments[fragment.Name] = fragment
+	}
+
+	for _, op := range doc.Operations {
+		v.variables = make(map[string]bool)
+		for _, def := range op.Variables {
+			v.variables[def.Name] = true
+		}
+		v.visiting = make(map[string]bool)
+
+		root := schema.RootType(op.Type)
+		if root == nil {
+			v.errorf(op.Pos, "schema does not support %s operations", op.Type)
+			continue
+		}
+		v.selections(root, op.Selections)
+	}
+
+	for _, fragment := range doc.Fragments {
+		typ := schema.Types[fragment.TypeCondition]
+		if !typ.IsComposite() {
+			v.errorf(fragment.Pos, "fragment %q is on unknown or non-composite type %q", fragment.Name, fragment.TypeCondition)
+		}
+	}
+
+	sort.SliceStable(v.errors, func(i, j int) bool {
+		return v.errors[i].Pos.Offset < v.errors[j].Pos.Offset
+	})
+	return v.errors
+}
+
+type validator struct {
+	schema    *Schema
+	fragments map[string]*Fragment
+	variables map[string]bool
+	// visiting guards against fragment cycles
+	visiting map[string]bool
+	errors   []*Error
+	seen     map[string]bool
+}
+
+func (v *validator) errorf(pos Position, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+
+	// Fragments are checked once per use; report each problem once
+	key := fmt.Sprintf("%d:%s", pos.Offset, msg)
+	if v.seen == nil {
+		v.seen = make(map[string]bool)
+	}
+	if v.seen[key] {
+		return
+	}
+	v.seen[key] = true
+
+	v.errors = append(v.errors, &Error{Message: msg, Pos: pos})
+}
+
+func (v *validator) selections(parent *Type, selections []*Selection) {
+	if parent == nil {
+		return
+	}
+	for _, selection := range selections {
+		switch selection.Kind {
+		case FieldSelection:
+			v.field(parent, selection)
+
+		case FragmentSpread:
+			fragment, ok := v.fragments[selection.Name]
+			if !ok {
+				v.errorf(selection.Pos, "unknown fragment %q", selection.Name)
+				continue
+			}
+			if v.visiting[fragment.Name] {
+				v.errorf(selection.Pos, "fragment %q spreads itself", fragment.Name)
+				continue
+			}
+			if typ := v.schema.Types[fragment.TypeCondition]; typ.IsComposite() {
+				v.visiting[fragment.Name] = true
+				v.selections(typ, fragment.Selections)
+				delete(v.visiting, fragment.Name)
+			}
+
+		case InlineFragment:
+			typ := parent
+			if selection.TypeCondition != "" {
+				typ = v.schema.Types[selection.TypeCondition]
+				if !typ.IsComposite() {
+					v.errorf(selection.Pos, "unknown or non-composite type %q", selection.TypeCondition)
+					continue
+				}
+			}
+			v.selections(typ, selection.Selections)
+		}
+	}
+}
+
+func (v *validator) field(parent *Type, selection *Selection) {
+	field := v.schema.Field(parent, selection.Name)
+	if field == nil || (parent.Kind == "UNION" && selection.Name != "__typename") {
+		msg := fmt.Sprintf("cannot query field %q on type %q", selection.Name, parent.Name)
+		if suggestion := closest(selection.Name, fieldNames(parent)); suggestion != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+		}
+		v.errorf(selection.Pos, "%s", msg)
+		return
+	}
+
+	given := make(map[string]bool)
+	for _, arg := range selection.Arguments {
+		given[arg.Name] = true
+		if findInputValue(field.Args, arg.Name) == nil {
+			v.errorf(arg.Pos, "unknown argument %q on field %s.%s", arg.Name, parent.Name, field.Name)
+		}
+		for _, variable := range arg.Variables {
+			if !v.variables[variable.Value] {
+				v.errorf(variable.Pos, "variable $%s is not defined", variable.Value)
+			}
+		}
+	}
+	for _, arg := range field.Args {
+		if arg.Type.NonNull() && arg.DefaultValue == nil && !given[arg.Name] {
+			v.errorf(selection.Pos, "field %q requires argument %q of type %s", field.Name, arg.Name, arg.Type)
+		}
+	}
+
+	typ := v.schema.Types[field.Type.Named()]
+	switch {
+	case typ.IsComposite() && selection.Selections == nil:
+		v.errorf(selection.Pos, "field %q of type %s must have a selection of subfields", field.Name, field.Type)
+	case !typ.IsComposite() && selection.Selections != nil && typ != nil:
+		v.errorf(selection.Pos, "field %q of type %s has no subfields", field.Name, field.Type)
+	case selection.Selections != nil:
+		v.selections(typ, selection.Selections)
+	}
+}
+
+func findInputValue(values []*InputValue, name string) *InputValue {
+	for _, value := range values {
+		if value.Name == name {
+			return value
+		}
+	}
+	return nil
+}
+
+func fieldNames(t *Type) []string {
+	names := make([]string, 0, len(t.Fields))
+	for _, f := range t.Fields {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// closest returns the candidate nearest to name by edit distance, if any is
+// close enough to be a likely typo
+func closest(name string, candidates []string) string {
+	best, bestDistance := "", len(name)/2+1
+	for _, candidate := range candidates {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,131 @@
+package graphql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "valid query",
+			query: `query($id: ID!) { user(id: $id) { id name friends { __typename name } } }`,
+		},
+		{
+			name:  "fragments and default arguments",
+			query: `{ users { ...bits } search(term: "a") { ... on User { id } } } fragment bits on User { name }`,
+		},
+		{
+			name:  "mutation",
+			query: `mutation { rename(id: 1, name: "b") { id } }`,
+		},
+		{
+			name:  "unknown field with suggestion",
+			query: `{ user(id: 1) { nmae } }`,
+			want:  []string{`1:17: cannot query field "nmae" on type "User" (did you mean "name"?)`},
+		},
+		{
+			name:  "missing required argument",
+			query: `{ user { id } }`,
+			want:  []string{`1:3: field "user" requires argument "id" of type ID!`},
+		},
+		{
+			name:  "unknown argument and undefined variable",
+			query: `{ user(id: $id, limit: 1) { id } }`,
+			want: []string{
+				`1:12: variable $id is not defined`,
+				`1:17: unknown argument "limit" on field Query.user`,
+			},
+		},
+		{
+			name:  "object field without selections",
+			query: `{ user(id: 1) }`,
+			want:  []string{`1:3: field "user" of type User must have a selection of subfields`},
+		},
+		{
+			name:  "leaf field with selections",
+			query: `{ user(id: 1) { name { x } } }`,
+			want:  []string{`1:17: field "name" of type String has no subfields`},
+		},
+		{
+			name:  "fields on a union",
+			query: `{ search(term: "a") { id __typename } }`,
+			want:  []string{`1:23: cannot query field "id" on type "SearchResult"`},
+		},
+		{
+			name:  "unknown and cyclic fragments",
+			query: "{ users { ...missing ...loop } }\nfragment loop on User { ...loop }",
+			want: []string{
+				`1:14: unknown fragment "missing"`,
+				`2:28: fragment "loop" spreads itself`,
+			},
+		},
+		{
+			name:  "fragment on a scalar",
+			query: `{ users { id } } fragment f on String { x }`,
+			want:  []string{`1:18: fragment "f" is on unknown or non-composite type "String"`},
+		},
+		{
+			name:  "unsupported operation",
+			query: `subscription { users { id } }`,
+			want:  []string{`1:1: schema does not support subscription operations`},
+		},
+		{
+			name:  "syntax error",
+			query: `{ user(id: 1) { id }`,
+			want:  []string{`1:21: expected "}", found end of document`},
+		},
+	}
+
+	schema := testSchema(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, err := range Validate(schema, tt.query) {
+				got = append(got, err.Error())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate(%q) =\n%q\nwant\n%q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		want   []string
+		prefix string
+	}{
+		{"top level", "", []string{"query", "mutation", "subscription", "fragment"}, ""},
+		{"keyword prefix", "mu", []string{"mutation"}, "mu"},
+		{"root fields", "{ ", []string{"user", "users", "search", "__typename"}, ""},
+		{"field prefix", "{ us", []string{"user", "users"}, "us"},
+		{"nested fields", "{ user(id: 1) { friends { n", []string{"name"}, "n"},
+		{"mutation fields", "mutation { ", []string{"rename", "__typename"}, ""},
+		{"arguments", "mutation { rename(", []string{"id", "name"}, ""},
+		{"enum values", "{ users(role: ", []string{"ADMIN", "MEMBER"}, ""},
+		{"type condition", "{ search(term: \"a\") { ... on ", []string{"Mutation", "Query", "SearchResult", "User"}, ""},
+		{"union", "{ search(term: \"a\") { ", []string{"__typename"}, ""},
+		{"unterminated string", "{ search(term: \"a", nil, "a"},
+	}
+
+	schema := testSchema(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions, prefix := Complete(schema, tt.src, len(tt.src))
+			var got []string
+			for _, s := range suggestions {
+				got = append(got, s.Label)
+			}
+			if !reflect.DeepEqual(got, tt.want) || prefix != tt.prefix {
+				t.Errorf("Complete(%q) = %q, %q, want %q, %q", tt.src, got, prefix, tt.want, tt.prefix)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// templateDepth limits how deeply nested messages are expanded in templates
+const templateDepth = 4
+
+// Catalog is the set of services and methods known for a target, with the
+// descriptors needed to encode requests and decode responses
+type Catalog struct {
+	// Source describes where the descriptors came from
+	Source  string
+	Methods []Method
+
+	types *dynamicpb.Types
+}
+
+// Method is an RPC method of a service
+type Method struct {
+	desc protoreflect.MethodDescriptor
+}
+
+// FullName is the method name as written in a gRPC path,
+// e.g. helloworld.Greeter/SayHello
+func (m Method) FullName() string {
+	return string(m.desc.Parent().FullName()) + "/" + string(m.desc.Name())
+}
+
+// Path is the HTTP/2 path the method is invoked on
+func (m Method) Path() string {
+	return "/" + m.FullName()
+}
+
+// Kind describes how the method streams
+func (m Method) Kind() string {
+	switch {
+	case m.desc.IsStreamingClient() && m.desc.IsStreamingServer():
+		return "bidi streaming"
+	case m.desc.IsStreamingClient():
+		return "client streaming"
+	case m.desc.IsStreamingServer():
+		return "server streaming"
+	}
+	return "unary"
+}
+
+// Input and Output are the request and response message names
+func (m Method) Input() string  { return string(m.desc.Input().FullName()) }
+func (m Method) Output() string { return string(m.desc.Output().FullName()) }
+
+// newCatalog lists the methods of the named services, or of every service
+// when names is empty
+func newCatalog(source string, files *protoregistry.Files, names []string) *Catalog {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	catalog := &Catalog{Source: source, types: dynamicpb.NewTypes(files)}
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			service := services.Get(i)
+			if len(wanted) > 0 && !wanted[string(service.FullName())] {
+				continue
+			}
+			methods := service.Methods()
+			for j := 0; j < methods.Len(); j++ {
+				catalog.Methods = append(catalog.Methods, Method{desc: methods.Get(j)})
+			}
+		}
+		return true
+	})
+
+	sort.Slice(catalog.Methods, func(i, j int) bool {
+		return catalog.Methods[i].FullName() < catalog.Methods[j].FullName()
+	})
+	return catalog
+}
+
+// Find looks a method up by its full name, with or without a leading slash
+func (c *Catalog) Find(name string) (Method, bool) {
+	name = strings.TrimPrefix(name, "/")
+	for _, method := range c.Methods {
+		if method.FullName() == name {
+			return method, true
+		}
+	}
+	return Method{}, false
+}
+
+// LoadFiles reads .proto sources and binary descriptor sets (as written by
+// protoc --descriptor_set_out, usually .protoset or .pb). Imports of .proto
+// files are resolved relative to the directories of the files given, and
+// the well-known types are always available.
+func LoadFiles(paths []string) (*Catalog, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no files given")
+	}
+
+	var sources, importPaths []string
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, path := range paths {
+		if strings.EqualFold(filepath.Ext(path), ".proto") {
+			dir := filepath.Dir(path)
+			if !contains(importPaths, dir) {
+				importPaths = append(importPaths, dir)
+			}
+			sources = append(sources, filepath.Base(path))
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var fds descriptorpb.FileDescriptorSet
+		if err := proto.Unmarshal(data, &fds); err != nil {
+			return nil, fmt.Errorf("%s is not a descriptor set: %w", path, err)
+		}
+		set.File = append(set.File, fds.File...)
+	}
+
+	if len(sources) > 0 {
+		compiler := protocompile.Compiler{
+			Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{ImportPaths: importPaths}),
+		}
+		compiled, err := compiler.Compile(context.Background(), sources...)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, file := range compiled {
+			set.File = appendWithImports(set.File, file, seen)
+		}
+	}
+
+	files, err := protodesc.NewFiles(dedupe(set))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, path := range paths {
+		if name := filepath.Base(path); !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return newCatalog(strings.Join(names, ", "), files, nil), nil
+}
+
+// appendWithImports adds file and everything it imports, dependencies first
+func appendWithImports(protos []*descriptorpb.FileDescriptorProto, file protoreflect.FileDescriptor, seen map[string]bool) []*descriptorpb.FileDescriptorProto {
+	if seen[file.Path()] {
+		return protos
+	}
+	seen[file.Path()] = true
+
+	imports := file.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		protos = appendWithImports(protos, imports.Get(i).FileDescriptor, seen)
+	}
+	return append(protos, protodesc.ToFileDescriptorProto(file))
+}
+
+// dedupe drops repeated files, which descriptor sets loaded side by side
+// commonly share
+func dedupe(set *descriptorpb.FileDescriptorSet) *descriptorpb.FileDescriptorSet {
+	seen := make(map[string]bool)
+	unique := &descriptorpb.FileDescriptorSet{}
+	for _, file := range set.File {
+		if !seen[file.GetName()] {
+			seen[file.GetName()] = true
+			unique.File = append(unique.File, file)
+		}
+	}
+	return unique
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Template returns a JSON skeleton of the method's request message with
+// every field set to an example value. Recursive fields are left empty.
+func (c *Catalog) Template(method Method) string {
+	var b strings.Builder
+	writeMessage(&b, method.desc.Input(), "", templateDepth, make(map[protoreflect.FullName]bool))
+	return b.String()
+}
+
+func writeMessage(b *strings.Builder, desc protoreflect.MessageDescriptor, indent string, depth int, expanding map[protoreflect.FullName]bool) {
+	fields := desc.Fields()
+	if fields.Len() == 0 || depth == 0 || expanding[desc.FullName()] {
+		b.WriteString("{}")
+		return
+	}
+	expanding[desc.FullName()] = true
+	defer delete(expanding, desc.FullName())
+
+	b.WriteString("{\n")
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		fmt.Fprintf(b, "%s  %q: ", indent, field.JSONName())
+		switch {
+		case field.IsMap():
+			b.WriteString("{}")
+		case field.IsList():
+			b.WriteString("[")
+			writeValue(b, field, indent+"  ", depth, expanding)
+			b.WriteString("]")
+		default:
+			writeValue(b, field, indent+"  ", depth, expanding)
+		}
+		if i < fields.Len()-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(indent + "}")
+}
+
+func writeValue(b *strings.Builder, field protoreflect.FieldDescriptor, indent string, depth int, expanding map[protoreflect.FullName]bool) {
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if example, ok := wellKnownExamples[field.Message().FullName()]; ok {
+			b.WriteString(example)
+			return
+		}
+		writeMessage(b, field.Message(), indent, depth-1, expanding)
+	case protoreflect.EnumKind:
+		if values := field.Enum().Values(); values.Len() > 0 {
+			fmt.Fprintf(b, "%q", values.Get(0).Name())
+		} else {
+			b.WriteString("0")
+		}
+	case protoreflect.BoolKind:
+		b.WriteString("false")
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		b.WriteString(`""`)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson writes 64-bit integers as strings
+		b.WriteString(`"0"`)
+	default:
+		b.WriteString("0")
+	}
+}
+
+// wellKnownExamples are the JSON forms of well-known types, which don't
+// follow their message structure
+var wellKnownExamples = map[protoreflect.FullName]string{
+	"google.protobuf.Timestamp":   `"1970-01-01T00:00:00Z"`,
+	"google.protobuf.Duration":    `"0s"`,
+	"google.protobuf.Struct":      `{}`,
+	"google.protobuf.Value":       `null`,
+	"google.protobuf.ListValue":   `[]`,
+	"google.protobuf.FieldMask":   `""`,
+	"google.protobuf.Any":         `{"@type": ""}`,
+	"google.protobuf.Empty":       `{}`,
+	"google.protobuf.StringValue": `""`,
+	"google.protobuf.BytesValue":  `""`,
+	"google.protobuf.BoolValue":   `false`,
+	"google.protobuf.Int32Value":  `0`,
+	"google.protobuf.UInt32Value": `0`,
+	"google.protobuf.Int64Value":  `"0"`,
+	"google.protobuf.UInt64Value": `"0"`,
+	"google.protobuf.FloatValue":  `0`,
+	"google.protobuf.DoubleValue": `0`,
+}
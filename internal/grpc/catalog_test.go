@@ -0,0 +1,183 @@
+package grpc
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const testProto = `syntax = "proto3";
+
+package shop.v1;
+
+import "google/protobuf/timestamp.proto";
+import "common.proto";
+
+service Orders {
+  rpc Get(GetRequest) returns (Order);
+  rpc Watch(GetRequest) returns (stream Order);
+  rpc Upload(stream Order) returns (GetRequest);
+  rpc Chat(stream Order) returns (stream Order);
+}
+
+message GetRequest {
+  string id = 1;
+}
+
+message Order {
+  string id = 1;
+  int64 total_cents = 2;
+  repeated Item items = 3;
+  map<string, string> labels = 4;
+  Status status = 5;
+  google.protobuf.Timestamp placed_at = 6;
+  Order parent = 7;
+  shop.common.Money price = 8;
+}
+
+message Item {
+  string sku = 1;
+  bool gift = 2;
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_PAID = 1;
+}
+`
+
+const testCommonProto = `syntax = "proto3";
+
+package shop.common;
+
+message Money {
+  double amount = 1;
+}
+`
+
+// loadTestCatalog writes the test protos to a temp dir and loads them
+func loadTestCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	dir := t.TempDir()
+	for name, source := range map[string]string{"orders.proto": testProto, "common.proto": testCommonProto} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(source), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	catalog, err := LoadFiles([]string{filepath.Join(dir, "orders.proto")})
+	if err != nil {
+		t.Fatalf("LoadFiles: %v", err)
+	}
+	return catalog
+}
+
+func TestLoadFiles(t *testing.T) {
+	catalog := loadTestCatalog(t)
+
+	if catalog.Source != "orders.proto" {
+		t.Errorf("Source = %q, want orders.proto", catalog.Source)
+	}
+
+	type method struct{ name, kind, input, output string }
+	var got []method
+	for _, m := range catalog.Methods {
+		got = append(got, method{m.FullName(), m.Kind(), m.Input(), m.Output()})
+	}
+	want := []method{
+		{"shop.v1.Orders/Chat", "bidi streaming", "shop.v1.Order", "shop.v1.Order"},
+		{"shop.v1.Orders/Get", "unary", "shop.v1.GetRequest", "shop.v1.Order"},
+		{"shop.v1.Orders/Upload", "client streaming", "shop.v1.Order", "shop.v1.GetRequest"},
+		{"shop.v1.Orders/Watch", "server streaming", "shop.v1.GetRequest", "shop.v1.Order"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Methods =\n%v\nwant\n%v", got, want)
+	}
+
+	for _, name := range []string{"shop.v1.Orders/Get", "/shop.v1.Orders/Get"} {
+		if m, ok := catalog.Find(name); !ok || m.Path() != "/shop.v1.Orders/Get" {
+			t.Errorf("Find(%q) = %v, %v", name, m.Path(), ok)
+		}
+	}
+	if _, ok := catalog.Find("shop.v1.Orders/Missing"); ok {
+		t.Error("Find found a method that doesn't exist")
+	}
+}
+
+func TestLoadFilesErrors(t *testing.T) {
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "broken.proto")
+	if err := os.WriteFile(broken, []byte("syntax = \"proto3\";\nmessage {"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	garbage := filepath.Join(dir, "garbage.protoset")
+	if err := os.WriteFile(garbage, []byte("not a descriptor set"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		paths []string
+	}{
+		{"no files", nil},
+		{"syntax error", []string{broken}},
+		{"missing descriptor set", []string{filepath.Join(dir, "missing.pb")}},
+		{"invalid descriptor set", []string{garbage}},
+	}
+
+	for _, tt := range tests {
+		if _, err := LoadFiles(tt.paths); err == nil {
+			t.Errorf("%s: LoadFiles succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	catalog := loadTestCatalog(t)
+	method, _ := catalog.Find("shop.v1.Orders/Upload")
+
+	want := `{
+  "id": "",
+  "totalCents": "0",
+  "items": [{
+    "sku": "",
+    "gift": false
+  }],
+  "labels": {},
+  "status": "STATUS_UNSPECIFIED",
+  "placedAt": "1970-01-01T00:00:00Z",
+  "parent": {},
+  "price": {
+    "amount": 0
+  }
+}`
+	if got := catalog.Template(method); got != want {
+		t.Errorf("Template =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		target string
+		addr   string
+		secure bool
+		isURL  bool
+	}{
+		{"grpcs://api.example.com", "api.example.com:443", true, true},
+		{"grpcs://api.example.com:8443/", "api.example.com:8443", true, true},
+		{"grpc://localhost", "localhost:80", false, true},
+		{"grpc://localhost:50051", "localhost:50051", false, true},
+		{"api.example.com:443", "api.example.com:443", true, false},
+		{" localhost:50051 ", "localhost:50051", false, false},
+	}
+
+	for _, tt := range tests {
+		addr, secure := ParseTarget(tt.target)
+		if addr != tt.addr || secure != tt.secure {
+			t.Errorf("ParseTarget(%q) = %q, %v, want %q, %v", tt.target, addr, secure, tt.addr, tt.secure)
+		}
+		if got := IsURL(tt.target); got != tt.isURL {
+			t.Errorf("IsURL(%q) = %v, want %v", tt.target, got, tt.isURL)
+		}
+	}
+}
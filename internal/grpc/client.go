@@ -0,0 +1,220 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// DefaultTimeout bounds reflection and unary calls. Server-streaming calls
+// run until the server ends them or they are cancelled.
+const DefaultTimeout = 30 * time.Second
+
+// maxBufferedMessages is how many streamed responses may queue up before
+// the call waits for the reader
+const maxBufferedMessages = 64
+
+// ParseTarget splits a target into a dialable address and whether to use
+// TLS. grpcs:// targets and bare targets on port 443 use TLS; grpc:// and
+// other bare host:port targets are plaintext.
+func ParseTarget(target string) (string, bool) {
+	target = strings.TrimSpace(target)
+	switch {
+	case strings.HasPrefix(target, "grpcs://"):
+		return withPort(strings.TrimPrefix(target, "grpcs://"), "443"), true
+	case strings.HasPrefix(target, "grpc://"):
+		return withPort(strings.TrimPrefix(target, "grpc://"), "80"), false
+	}
+	_, port, _ := net.SplitHostPort(target)
+	return target, port == "443"
+}
+
+func withPort(addr, port string) string {
+	addr = strings.TrimSuffix(addr, "/")
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return net.JoinHostPort(addr, port)
+	}
+	return addr
+}
+
+// IsURL reports whether target uses a grpc:// or grpcs:// scheme
+func IsURL(target string) bool {
+	target = strings.ToLower(strings.TrimSpace(target))
+	return strings.HasPrefix(target, "grpc://") || strings.HasPrefix(target, "grpcs://")
+}
+
+// Dial creates a client connection to target. Connecting is lazy, so
+// failures surface on the first call.
+func Dial(target string) (*grpc.ClientConn, error) {
+	addr, secure := ParseTarget(target)
+	if addr == "" {
+		return nil, errors.New("no target given")
+	}
+
+	creds := insecure.NewCredentials()
+	if secure {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+	return grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+}
+
+func withMetadata(ctx context.Context, md map[string]string) context.Context {
+	if len(md) == 0 {
+		return ctx
+	}
+	return metadata.NewOutgoingContext(ctx, metadata.New(md))
+}
+
+// Status is the outcome of a call
+type Status struct {
+	Code    codes.Code
+	Message string
+}
+
+// OK reports whether the call succeeded
+func (s Status) OK() bool {
+	return s.Code == codes.OK
+}
+
+// Message is a decoded response message
+type Message struct {
+	JSON string
+	Time time.Time
+}
+
+// Call is an in-flight unary or server-streaming call. Responses are
+// delivered on Messages, which is closed when the call ends; Status, Header
+// and Trailer are valid after that.
+type Call struct {
+	Messages <-chan Message
+	Method   Method
+
+	messages chan Message
+	cancel   context.CancelFunc
+	types    *dynamicpb.Types
+
+	status  Status
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+// Invoke calls method on target with a request decoded from JSON, sending
+// metadata along with it. Client and bidi streaming methods aren't
+// supported.
+func Invoke(target string, catalog *Catalog, method Method, body string, md map[string]string) (*Call, error) {
+	if method.desc.IsStreamingClient() {
+		return nil, fmt.Errorf("%s calls are not supported", method.Kind())
+	}
+
+	req := dynamicpb.NewMessage(method.desc.Input())
+	if strings.TrimSpace(body) != "" {
+		if err := (protojson.UnmarshalOptions{Resolver: catalog.types}).Unmarshal([]byte(body), req); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", method.Input(), err)
+		}
+	}
+
+	conn, err := Dial(target)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := withMetadata(context.Background(), md)
+	var cancel context.CancelFunc
+	if method.desc.IsStreamingServer() {
+		ctx, cancel = context.WithCancel(ctx)
+	} else {
+		ctx, cancel = context.WithTimeout(ctx, DefaultTimeout)
+	}
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: method.desc.IsStreamingServer()}, method.Path())
+	if err != nil {
+		cancel()
+		conn.Close()
+		return nil, err
+	}
+
+	// A failed send ends the stream; its status comes from the receive below
+	if err := stream.SendMsg(req); err == nil {
+		stream.CloseSend()
+	}
+
+	messages := make(chan Message, maxBufferedMessages)
+	call := &Call{
+		Messages: messages,
+		Method:   method,
+		messages: messages,
+		cancel:   cancel,
+		types:    catalog.types,
+	}
+	go call.receive(ctx, conn, stream)
+	return call, nil
+}
+
+func (c *Call) receive(ctx context.Context, conn *grpc.ClientConn, stream grpc.ClientStream) {
+	defer close(c.messages)
+	defer conn.Close()
+	defer c.cancel()
+
+	marshal := protojson.MarshalOptions{Multiline: true, Indent: "  ", Resolver: c.types}
+	for {
+		resp := dynamicpb.NewMessage(c.Method.desc.Output())
+		err := stream.RecvMsg(resp)
+		if err == io.EOF {
+			c.status = Status{Code: codes.OK}
+			break
+		}
+		if err != nil {
+			st := status.Convert(err)
+			c.status = Status{Code: st.Code(), Message: st.Message()}
+			break
+		}
+
+		data, err := marshal.Marshal(resp)
+		if err != nil {
+			data = []byte(fmt.Sprintf("%q", err.Error()))
+		}
+		select {
+		case c.messages <- Message{JSON: string(data), Time: time.Now()}:
+		case <-ctx.Done():
+		}
+	}
+
+	c.header, _ = stream.Header()
+	c.trailer = stream.Trailer()
+}
+
+// Cancel abandons the call; it ends with a Canceled status
+func (c *Call) Cancel() {
+	c.cancel()
+}
+
+// Status is the call's final status
+func (c *Call) Status() Status {
+	return c.status
+}
+
+// Header and Trailer are the response metadata, with repeated keys joined
+func (c *Call) Header() map[string]string  { return flatten(c.header) }
+func (c *Call) Trailer() map[string]string { return flatten(c.trailer) }
+
+func flatten(md metadata.MD) map[string]string {
+	flat := make(map[string]string, len(md))
+	for key, values := range md {
+		flat[key] = strings.Join(values, ", ")
+	}
+	return flat
+}
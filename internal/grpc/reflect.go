@@ -0,0 +1,208 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	rpbalpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Reflect lists the services a server exposes through the server reflection
+// API, trying v1 first and falling back to v1alpha for older servers
+func Reflect(ctx context.Context, target string, metadata map[string]string) (*Catalog, error) {
+	conn, err := Dial(target)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(withMetadata(ctx, metadata), DefaultTimeout)
+	defer cancel()
+
+	catalog, err := reflect(ctx, conn, false)
+	if status.Code(err) == codes.Unimplemented {
+		catalog, err = reflect(ctx, conn, true)
+	}
+	if status.Code(err) == codes.Unimplemented {
+		return nil, errors.New("server reflection is not enabled on this server; load .proto or descriptor set files instead")
+	}
+	if err != nil {
+		return nil, err
+	}
+	catalog.Source = "reflection on " + target
+	return catalog, nil
+}
+
+// reflectionStream is the part of the reflection stream used here. The v1
+// and v1alpha messages are identical on the wire, so v1 types are used
+// throughout and converted for v1alpha servers.
+type reflectionStream interface {
+	Send(*rpb.ServerReflectionRequest) error
+	Recv() (*rpb.ServerReflectionResponse, error)
+}
+
+type alphaStream struct {
+	stream rpbalpha.ServerReflection_ServerReflectionInfoClient
+}
+
+func (s alphaStream) Send(req *rpb.ServerReflectionRequest) error {
+	var alpha rpbalpha.ServerReflectionRequest
+	if err := convert(req, &alpha); err != nil {
+		return err
+	}
+	return s.stream.Send(&alpha)
+}
+
+func (s alphaStream) Recv() (*rpb.ServerReflectionResponse, error) {
+	alpha, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	var resp rpb.ServerReflectionResponse
+	return &resp, convert(alpha, &resp)
+}
+
+func convert(from, to proto.Message) error {
+	data, err := proto.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, to)
+}
+
+func reflect(ctx context.Context, conn *grpc.ClientConn, alpha bool) (*Catalog, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var stream reflectionStream
+	var err error
+	if alpha {
+		var s rpbalpha.ServerReflection_ServerReflectionInfoClient
+		s, err = rpbalpha.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+		stream = alphaStream{s}
+	} else {
+		stream, err = rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r := &reflector{stream: stream, files: make(map[string]*descriptorpb.FileDescriptorProto)}
+
+	resp, err := r.request(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var services []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		if strings.HasPrefix(service.Name, "grpc.reflection.") {
+			continue
+		}
+		services = append(services, service.Name)
+		if err := r.fileContaining(service.Name); err != nil {
+			return nil, fmt.Errorf("describing %s: %w", service.Name, err)
+		}
+	}
+
+	if err := r.resolveDependencies(); err != nil {
+		return nil, err
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range r.files {
+		set.File = append(set.File, file)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, err
+	}
+	return newCatalog("", files, services), nil
+}
+
+// reflector collects file descriptors from a reflection stream
+type reflector struct {
+	stream reflectionStream
+	files  map[string]*descriptorpb.FileDescriptorProto
+}
+
+func (r *reflector) request(req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) {
+	if err := r.stream.Send(req); err != nil {
+		return nil, err
+	}
+	resp, err := r.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if e := resp.GetErrorResponse(); e != nil {
+		return nil, status.Error(codes.Code(e.ErrorCode), e.ErrorMessage)
+	}
+	return resp, nil
+}
+
+func (r *reflector) fileContaining(symbol string) error {
+	resp, err := r.request(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+	})
+	if err != nil {
+		return err
+	}
+	return r.add(resp)
+}
+
+func (r *reflector) fileByName(name string) error {
+	resp, err := r.request(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+	})
+	if err != nil {
+		return err
+	}
+	return r.add(resp)
+}
+
+func (r *reflector) add(resp *rpb.ServerReflectionResponse) error {
+	for _, data := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		file := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(data, file); err != nil {
+			return err
+		}
+		r.files[file.GetName()] = file
+	}
+	return nil
+}
+
+// resolveDependencies fetches imported files the server didn't send along
+func (r *reflector) resolveDependencies() error {
+	for {
+		var missing []string
+		for _, file := range r.files {
+			for _, dep := range file.GetDependency() {
+				if _, ok := r.files[dep]; !ok && !contains(missing, dep) {
+					missing = append(missing, dep)
+				}
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		for _, name := range missing {
+			if err := r.fileByName(name); err != nil {
+				return fmt.Errorf("fetching %s: %w", name, err)
+			}
+			if _, ok := r.files[name]; !ok {
+				return fmt.Errorf("server did not return %s", name)
+			}
+		}
+	}
+}
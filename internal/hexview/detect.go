@@ -0,0 +1,154 @@
+package hexview
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// signature identifies a format by the bytes found at a fixed offset
+type signature struct {
+	offset int
+	magic  []byte
+	name   string
+}
+
+var signatures = []signature{
+	{0, []byte("\x89PNG\r\n\x1a\n"), "PNG image"},
+	{0, []byte("\xff\xd8\xff"), "JPEG image"},
+	{0, []byte("GIF87a"), "GIF image"},
+	{0, []byte("GIF89a"), "GIF image"},
+	{0, []byte("BM"), "BMP image"},
+	{0, []byte("\x00\x00\x01\x00"), "ICO icon"},
+	{0, []byte("%PDF-"), "PDF document"},
+	{0, []byte("\x1f\x8b"), "gzip compressed data"},
+	{0, []byte("BZh"), "bzip2 compressed data"},
+	{0, []byte("\x28\xb5\x2f\xfd"), "Zstandard compressed data"},
+	{0, []byte("\xfd7zXZ\x00"), "XZ compressed data"},
+	{0, []byte("7z\xbc\xaf\x27\x1c"), "7-Zip archive"},
+	{0, []byte("PK\x03\x04"), "ZIP archive"},
+	{0, []byte("PK\x05\x06"), "ZIP archive (empty)"},
+	{0, []byte("Rar!\x1a\x07"), "RAR archive"},
+	{257, []byte("ustar"), "tar archive"},
+	{0, []byte("\x7fELF"), "ELF executable"},
+	{0, []byte("MZ"), "Windows executable"},
+	{0, []byte("\xcf\xfa\xed\xfe"), "Mach-O executable"},
+	{0, []byte("\x00asm"), "WebAssembly module"},
+	{0, []byte("SQLite format 3\x00"), "SQLite database"},
+	{0, []byte("ID3"), "MP3 audio"},
+	{0, []byte("OggS"), "Ogg media"},
+	{0, []byte("fLaC"), "FLAC audio"},
+	{0, []byte("\x1aE\xdf\xa3"), "Matroska/WebM video"},
+	{4, []byte("ftyp"), "MP4/QuickTime media"},
+	{0, []byte("wOFF"), "WOFF font"},
+	{0, []byte("wOF2"), "WOFF2 font"},
+	{0, []byte("\x00\x01\x00\x00\x00"), "TrueType font"},
+	{0, []byte("OTTO"), "OpenType font"},
+	{0, []byte("\xef\xbb\xbf"), "UTF-8 text with BOM"},
+	{0, []byte("\xff\xfe"), "UTF-16LE text"},
+	{0, []byte("\xfe\xff"), "UTF-16BE text"},
+}
+
+// riffFormats distinguishes RIFF containers by their form type
+var riffFormats = map[string]string{
+	"WEBP": "WebP image",
+	"WAVE": "WAV audio",
+	"AVI ": "AVI video",
+}
+
+// Detect names the format of data from its leading bytes, falling back to
+// structural heuristics for formats without a magic number. It returns ""
+// when nothing matches.
+func Detect(data []byte) string {
+	if len(data) >= 12 && bytes.HasPrefix(data, []byte("RIFF")) {
+		if name, ok := riffFormats[string(data[8:12])]; ok {
+			return name
+		}
+		return "RIFF container"
+	}
+
+	for _, sig := range signatures {
+		end := sig.offset + len(sig.magic)
+		if len(data) >= end && bytes.Equal(data[sig.offset:end], sig.magic) {
+			return sig.name
+		}
+	}
+
+	if isGRPCFrame(data) {
+		return "gRPC message frame"
+	}
+	// Plain text can happen to decode as protobuf, so only consider data that
+	// already has non-printable bytes in it
+	if !isPrintable(data) && looksLikeProtobuf(data) {
+		return "Protocol Buffers message (likely)"
+	}
+
+	return ""
+}
+
+// isGRPCFrame checks for gRPC's length-prefixed framing: a compression flag
+// byte followed by a big-endian length that accounts for the rest of data
+func isGRPCFrame(data []byte) bool {
+	if len(data) < 5 || data[0] > 1 {
+		return false
+	}
+	return int(binary.BigEndian.Uint32(data[1:5])) == len(data)-5
+}
+
+// looksLikeProtobuf walks data as protobuf wire format and reports whether
+// it decodes cleanly into a handful of well-formed fields
+func looksLikeProtobuf(data []byte) bool {
+	if len(data) < 2 {
+		return false
+	}
+
+	fields := 0
+	for i := 0; i < len(data); {
+		tag, n := binary.Uvarint(data[i:])
+		if n <= 0 {
+			return false
+		}
+		i += n
+
+		fieldNumber, wireType := tag>>3, tag&7
+		if fieldNumber == 0 || fieldNumber > 1<<29-1 {
+			return false
+		}
+
+		switch wireType {
+		case 0: // varint
+			_, n := binary.Uvarint(data[i:])
+			if n <= 0 {
+				return false
+			}
+			i += n
+		case 1: // 64-bit
+			i += 8
+		case 2: // length-delimited
+			length, n := binary.Uvarint(data[i:])
+			if n <= 0 || length > uint64(len(data)) {
+				return false
+			}
+			i += n + int(length)
+		case 5: // 32-bit
+			i += 4
+		default:
+			return false
+		}
+
+		if i > len(data) {
+			return false
+		}
+		fields++
+	}
+
+	return fields > 0
+}
+
+func isPrintable(data []byte) bool {
+	for _, c := range data {
+		if (c < 0x20 || c >= 0x7f) && c != '\n' && c != '\r' && c != '\t' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package hexview
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BytesPerLine is the number of bytes shown on each row of a dump
+const BytesPerLine = 16
+
+// Line formats one row of a hex dump: the offset, up to BytesPerLine bytes
+// in two groups of eight, and an ASCII gutter with non-printable bytes
+// shown as dots
+func Line(data []byte, offset int) string {
+	var b strings.Builder
+	b.Grow(80)
+
+	fmt.Fprintf(&b, "%08x  ", offset)
+
+	for i := 0; i < BytesPerLine; i++ {
+		if i == BytesPerLine/2 {
+			b.WriteByte(' ')
+		}
+		if i < len(data) {
+			fmt.Fprintf(&b, "%02x ", data[i])
+		} else {
+			b.WriteString("   ")
+		}
+	}
+
+	b.WriteString(" |")
+	b.WriteString(ASCII(data))
+	b.WriteString("|")
+
+	return b.String()
+}
+
+// ASCII renders bytes as printable ASCII, replacing everything else with '.'
+func ASCII(data []byte) string {
+	out := make([]byte, len(data))
+	for i, c := range data {
+		if c >= 0x20 && c < 0x7f {
+			out[i] = c
+		} else {
+			out[i] = '.'
+		}
+	}
+	return string(out)
+}
+
+// Dump formats data as hex dump rows
+func Dump(data []byte) []string {
+	lines := make([]string, 0, (len(data)+BytesPerLine-1)/BytesPerLine)
+	for offset := 0; offset < len(data); offset += BytesPerLine {
+		end := min(offset+BytesPerLine, len(data))
+		lines = append(lines, Line(data[offset:end], offset))
+	}
+	return lines
+}
@@ -0,0 +1,83 @@
+package hexview
+
+import (
+	"bytes"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+var hexPatternRegex = regexp.MustCompile(`^(0x)?([0-9a-fA-F]{2}[\s:]*)+$`)
+
+// ParsePattern turns a search query into the bytes to look for. Queries
+// made only of hex byte pairs (optionally prefixed with 0x and separated by
+// spaces or colons) are decoded as bytes, reported by isHex; a query wrapped
+// in double quotes is always taken literally; anything else is searched as
+// text.
+func ParsePattern(query string) (pattern []byte, isHex bool) {
+	if len(query) >= 2 && strings.HasPrefix(query, `"`) && strings.HasSuffix(query, `"`) {
+		return []byte(query[1 : len(query)-1]), false
+	}
+
+	if hexPatternRegex.MatchString(query) {
+		digits := strings.NewReplacer("0x", "", " ", "", ":", "", "\t", "").Replace(query)
+		if decoded, err := hex.DecodeString(digits); err == nil {
+			return decoded, true
+		}
+	}
+
+	return []byte(query), false
+}
+
+// Find returns the offset of every non-overlapping occurrence of pattern in
+// data. foldCase makes the ASCII letters A-Z and a-z match regardless of
+// case; every other byte, valid UTF-8 or not, must match exactly.
+func Find(data, pattern []byte, foldCase bool) []int {
+	if len(pattern) == 0 {
+		return nil
+	}
+
+	haystack, needle := data, pattern
+	if foldCase {
+		haystack, needle = foldASCII(data), foldASCII(pattern)
+	}
+
+	var offsets []int
+	for start := 0; start <= len(haystack)-len(needle); {
+		i := bytes.Index(haystack[start:], needle)
+		if i < 0 {
+			break
+		}
+		offsets = append(offsets, start+i)
+		start += i + len(needle)
+	}
+
+	return offsets
+}
+
+// foldASCII lowercases A-Z byte by byte, so offsets into the result are
+// offsets into b
+func foldASCII(b []byte) []byte {
+	folded := make([]byte, len(b))
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		folded[i] = c
+	}
+	return folded
+}
+
+// HexColumn is the position of byte i's first hex digit within a Line
+func HexColumn(i int) int {
+	col := 10 + i*3
+	if i >= BytesPerLine/2 {
+		col++
+	}
+	return col
+}
+
+// ASCIIColumn is the position of byte i within a Line's ASCII gutter
+func ASCIIColumn(i int) int {
+	return HexColumn(BytesPerLine) + 2 + i
+}
@@ -0,0 +1,64 @@
+package hexview
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		query   string
+		pattern []byte
+		isHex   bool
+	}{
+		{"41 42", []byte{0x41, 0x42}, true},
+		{"0x4142", []byte{0x41, 0x42}, true},
+		{"de:ad:be:ef", []byte{0xde, 0xad, 0xbe, 0xef}, true},
+		{`"41 42"`, []byte("41 42"), false},
+		{"hello", []byte("hello"), false},
+		{"4", []byte("4"), false},
+	}
+
+	for _, tt := range tests {
+		pattern, isHex := ParsePattern(tt.query)
+		if !reflect.DeepEqual(pattern, tt.pattern) || isHex != tt.isHex {
+			t.Errorf("ParsePattern(%q) = % x, %v, want % x, %v", tt.query, pattern, isHex, tt.pattern, tt.isHex)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		pattern  []byte
+		foldCase bool
+		want     []int
+	}{
+		{"exact", []byte("abcabc"), []byte("bc"), false, []int{1, 4}},
+		{"non-overlapping", []byte("aaaa"), []byte("aa"), false, []int{0, 2}},
+		{"case sensitive", []byte("ABC abc"), []byte("abc"), false, []int{4}},
+		{"fold case", []byte("ABC abc"), []byte("abc"), true, []int{0, 4}},
+		{"fold pattern", []byte("abc"), []byte("ABC"), true, []int{0}},
+		{"invalid UTF-8 before match", []byte{0xff, 0xfe, 0x00, 0x41, 0x42, 0x43}, []byte{0x41, 0x42}, true, []int{3}},
+		{"invalid UTF-8 in match", []byte{0x41, 0xff, 0x42}, []byte{0x61, 0xff, 0x62}, true, []int{0}},
+		{"only ASCII folds", []byte("É é"), []byte("é"), true, []int{3}},
+		{"no match", []byte("abc"), []byte("x"), true, nil},
+		{"pattern longer than data", []byte("ab"), []byte("abc"), false, nil},
+		{"empty pattern", []byte("abc"), nil, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Find(tt.data, tt.pattern, tt.foldCase)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find = %v, want %v", got, tt.want)
+			}
+			for _, offset := range got {
+				if offset+len(tt.pattern) > len(tt.data) {
+					t.Errorf("offset %d runs past the %d bytes of data", offset, len(tt.data))
+				}
+			}
+		})
+	}
+}
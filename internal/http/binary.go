@@ -0,0 +1,100 @@
+package http
+
+import (
+	"bytes"
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// sniffLength is how many leading bytes are inspected to classify a body
+const sniffLength = 8192
+
+var binaryMediaPrefixes = []string{"image/", "audio/", "video/", "font/"}
+
+var binaryMediaTypes = map[string]bool{
+	"application/octet-stream":    true,
+	"application/pdf":             true,
+	"application/zip":             true,
+	"application/gzip":            true,
+	"application/x-gzip":          true,
+	"application/x-tar":           true,
+	"application/x-protobuf":      true,
+	"application/protobuf":        true,
+	"application/grpc":            true,
+	"application/msgpack":         true,
+	"application/x-msgpack":       true,
+	"application/cbor":            true,
+	"application/wasm":            true,
+	"application/vnd.ms-excel":    true,
+	"application/x-7z-compressed": true,
+}
+
+// IsBinary reports whether a body should not be shown as text. A textual
+// Content-Type is trusted; otherwise the leading bytes are inspected for
+// NULs, invalid UTF-8 and control characters.
+func IsBinary(data []byte, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	if isTextMediaType(mediaType) {
+		return false
+	}
+	if binaryMediaTypes[mediaType] {
+		return true
+	}
+	for _, prefix := range binaryMediaPrefixes {
+		if strings.HasPrefix(mediaType, prefix) && !strings.HasSuffix(mediaType, "+xml") {
+			return true
+		}
+	}
+
+	return looksBinary(data)
+}
+
+func isTextMediaType(mediaType string) bool {
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	for _, suffix := range []string{"json", "xml", "yaml", "javascript", "graphql", "x-www-form-urlencoded"} {
+		if strings.HasSuffix(mediaType, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func looksBinary(data []byte) bool {
+	sample := data
+	if len(sample) > sniffLength {
+		sample = sample[:sniffLength]
+	}
+	if len(sample) == 0 {
+		return false
+	}
+
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return true
+	}
+
+	suspicious := 0
+	for i := 0; i < len(sample); {
+		r, size := utf8.DecodeRune(sample[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			// A rune cut off by the sample boundary isn't evidence of binary
+			if len(sample)-i < utf8.UTFMax && len(data) > len(sample) {
+				i = len(sample)
+				continue
+			}
+			suspicious++
+		case r < 0x20 && r != '\n' && r != '\r' && r != '\t' && r != '\f' && r != '\b' && r != 0x1b:
+			suspicious++
+		}
+		i += size
+	}
+
+	return suspicious*10 > len(sample)
+}
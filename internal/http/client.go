@@ -4,11 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
+	"net/textproto"
+	"os"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 )
 
 type Request struct {
@@ -16,66 +22,271 @@ type Request struct {
 	URL     string
 	Headers map[string]string
 	Body    string
+
+	// ContentType is sent with a raw Body unless a header sets one; when
+	// empty it is inferred from the body
+	ContentType string
+
+	Redirects RedirectPolicy
+
+	// TLS and Proxy override the client's settings for this request
+	TLS   TLSConfig
+	Proxy ProxyConfig
+	// Target overrides where the request connects
+	Target Target
+	// Retry sends the request again when it fails
+	Retry RetryPolicy
+
+	// BodyMode selects between Body and Form
+	BodyMode BodyMode
+	Form     []FormField
 }
 
 type Response struct {
 	StatusCode   int
+	Proto        string
 	Headers      map[string]string
 	Body         string
 	ContentType  string
 	ResponseTime time.Duration
 	Error        error
+
+	// BodySize is the full size of the body. When it exceeds the client's
+	// in-memory limit, Body holds only the first MaxBodyInMemory bytes and
+	// the complete body is spilled to BodyFile.
+	BodySize int64
+	BodyFile string
+	Binary   bool
+
+	// Stream is set instead of Body for text/event-stream responses, which
+	// stay open and deliver events as they arrive
+	Stream *EventStream
+
+	// URL is where the response came from, after any redirects
+	URL string
+	// Redirects lists the redirects followed, in order. RedirectStopped is
+	// set when the response is itself a redirect the policy didn't follow.
+	Redirects       []Hop
+	RedirectStopped bool
+
+	// SetCookies are the cookies set along the way, redirects included
+	SetCookies []SetCookie
+
+	// Connection describes the connection the response arrived on, or as
+	// much as was learned of it when the request failed
+	Connection *Connection
+
+	// Attempts lists every attempt the retry policy made, the last being
+	// this response
+	Attempts []Attempt
 }
 
+// DefaultTimeout bounds how long a request may wait for its response
+// headers, and then how long the body may go without sending data, so a
+// large download runs as long as it keeps arriving. Event streams are
+// exempt once their headers arrive.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultUserAgent is sent as User-Agent unless the client sets another
+const DefaultUserAgent = "Quest/1.0"
+
+// ErrTimeout is returned when a request exceeds the client's Timeout
+var ErrTimeout = errors.New("request timed out")
+
+// ErrCancelled is the cause to cancel a request's context with, and is
+// returned once it is
+var ErrCancelled = errors.New("request cancelled")
+
+// DefaultMaxBodyInMemory is how much of a response body is kept in memory
+// before the rest is spilled to a temp file
+const DefaultMaxBodyInMemory = 10 << 20
+
 type Client struct {
 	httpClient *http.Client
+
+	// Timeout limits the wait for each response's headers and any pause in
+	// its body; zero means no limit
+	Timeout time.Duration
+
+	// MaxBodyInMemory caps how many body bytes are held in memory
+	MaxBodyInMemory int64
+
+	// Jar stores cookies from responses and sends them on later requests;
+	// nil disables cookie handling
+	Jar *CookieJar
+
+	// TLS and Proxy apply to every request, under each request's own
+	// settings
+	TLS   TLSConfig
+	Proxy ProxyConfig
+
+	// UserAgent and Headers are sent with every request, under the
+	// request's own headers; an empty UserAgent sends Go's
+	UserAgent string
+	Headers   map[string]string
+
+	mu         sync.Mutex
+	transports map[transportKey]*http.Transport
 }
 
 func NewClient() *Client {
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		httpClient:      &http.Client{},
+		Timeout:         DefaultTimeout,
+		MaxBodyInMemory: DefaultMaxBodyInMemory,
+		UserAgent:       DefaultUserAgent,
+	}
+}
+
+// HeadersFor returns headers with the client's user agent and default
+// headers added beneath them
+func (c *Client) HeadersFor(headers map[string]string) map[string]string {
+	merged := make(map[string]string, len(c.Headers)+len(headers)+1)
+	if c.UserAgent != "" {
+		merged["User-Agent"] = c.UserAgent
+	}
+	for _, set := range []map[string]string{c.Headers, headers} {
+		for key, value := range set {
+			merged[textproto.CanonicalMIMEHeaderKey(key)] = value
+		}
 	}
+	return merged
 }
 
 func (c *Client) SendRequest(req Request) Response {
+	return c.SendRequestWithProgress(req, nil)
+}
+
+// SendRequestWithProgress sends req, reporting body download progress to
+// onProgress (which may be nil) as bytes arrive
+func (c *Client) SendRequestWithProgress(req Request, onProgress func(Progress)) Response {
+	return c.SendRequestContext(context.Background(), req, onProgress)
+}
+
+// SendRequestContext sends req until ctx is cancelled, reporting progress
+// to onProgress (which may be nil). Failed attempts are sent again as req's
+// retry policy allows, reporting each wait to onProgress; cancelling ctx
+// also ends a wait.
+func (c *Client) SendRequestContext(ctx context.Context, req Request, onProgress func(Progress)) Response {
 	start := time.Now()
+	var attempts []Attempt
+	for n := 1; ; n++ {
+		var progress func(Progress)
+		if onProgress != nil {
+			progress = func(p Progress) {
+				p.Attempt = n
+				onProgress(p)
+			}
+		}
 
-	// Prepare request body
-	var reqBody io.Reader
-	if req.Body != "" && (req.Method == "POST" || req.Method == "PUT" || req.Method == "PATCH") {
-		reqBody = bytes.NewBufferString(req.Body)
+		sent := time.Now()
+		resp, retryable := c.send(ctx, req, progress)
+		attempt, retry := req.Retry.next(n, req.Method, resp, retryable && ctx.Err() == nil)
+		attempt.Start, attempt.Duration = sent.Sub(start), time.Since(sent)
+		attempts = append(attempts, attempt)
+		if !retry {
+			resp.Attempts = attempts
+			return resp
+		}
+
+		if resp.BodyFile != "" {
+			os.Remove(resp.BodyFile)
+		}
+		if onProgress != nil {
+			onProgress(Progress{Attempt: n, Retry: &attempt})
+		}
+		wait := time.NewTimer(attempt.Wait)
+		select {
+		case <-wait.C:
+		case <-ctx.Done():
+			wait.Stop()
+			attempts[n-1].Wait = 0
+			attempts[n-1].GaveUp = "cancelled while waiting to retry"
+			resp.Attempts = attempts
+			return resp
+		}
+		if onProgress != nil {
+			onProgress(Progress{Attempt: n + 1})
+		}
 	}
+}
 
-	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(context.Background(), req.Method, req.URL, reqBody)
+// send makes one attempt at req. retryable reports whether a failure came
+// from the network, so that trying again might succeed.
+func (c *Client) send(parent context.Context, req Request, onProgress func(Progress)) (Response, bool) {
+	start := time.Now()
+
+	transport, err := c.transport(c.TLS.Merge(req.TLS), c.Proxy.Merge(req.Proxy), req.Target)
+	if err != nil {
+		return Response{Error: err}, false
+	}
+
+	// Prepare request body
+	reqBody, bodyType, err := req.body()
 	if err != nil {
-		return Response{Error: fmt.Errorf("failed to create request: %w", err)}
+		return Response{Error: fmt.Errorf("failed to build request body: %w", err)}, false
 	}
 
-	// Set default headers
-	httpReq.Header.Set("User-Agent", "Quest/1.0")
-	if reqBody != nil && httpReq.Header.Get("Content-Type") == "" {
-		httpReq.Header.Set("Content-Type", "application/json")
+	// The timeout is enforced through the context rather than http.Client so
+	// that it can be lifted for event streams
+	ctx, cancel := context.WithCancelCause(parent)
+	var timer *time.Timer
+	if c.Timeout > 0 {
+		timer = time.AfterFunc(c.Timeout, func() { cancel(ErrTimeout) })
+	}
+	stopTimer := func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+
+	// Create HTTP request, tracing the connection it goes out on
+	connection := &connectionTracker{}
+	traceCtx := httptrace.WithClientTrace(ctx, connection.trace())
+	httpReq, err := http.NewRequestWithContext(traceCtx, req.Method, req.URL, reqBody)
+	if err != nil {
+		cancel(nil)
+		if closer, ok := reqBody.(io.Closer); ok {
+			closer.Close()
+		}
+		return Response{Error: fmt.Errorf("failed to create request: %w", err)}, false
 	}
 
-	// Set custom headers
+	// Set the default headers, then the body's content type, then the
+	// request's own headers. A default Content-Type only gives way to one
+	// chosen for the request or set by its form, not one inferred from it.
+	for key, value := range c.HeadersFor(nil) {
+		httpReq.Header.Set(key, value)
+	}
+	if bodyType != "" && (req.ContentType != "" || req.BodyMode != RawBody || httpReq.Header.Get("Content-Type") == "") {
+		httpReq.Header.Set("Content-Type", bodyType)
+	}
 	for key, value := range req.Headers {
 		httpReq.Header.Set(key, value)
 	}
 
-	// Send request
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return Response{Error: fmt.Errorf("request failed: %w", err)}
+	// The multipart boundary is chosen here, so it can't be overridden
+	if req.BodyMode == MultipartBody && reqBody != nil {
+		httpReq.Header.Set("Content-Type", bodyType)
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Send request, following redirects as the request's policy allows
+	redirects := newRedirectTracker(req.Redirects, time.Now())
+	client := *c.httpClient
+	client.CheckRedirect = redirects.check
+	client.Transport = transport
+	if c.Jar != nil {
+		client.Jar = c.Jar
+	}
+	resp, err := client.Do(httpReq)
 	if err != nil {
-		return Response{Error: fmt.Errorf("failed to read response body: %w", err)}
+		stopTimer()
+		cancel(nil)
+		return Response{
+			Error:      fmt.Errorf("request failed: %w", c.timeoutError(ctx, err)),
+			Redirects:  redirects.hops,
+			Connection: connection.failure(err),
+		}, true
 	}
 
 	// Parse response headers
@@ -87,13 +298,128 @@ func (c *Client) SendRequest(req Request) Response {
 	// Get content type
 	contentType := resp.Header.Get("Content-Type")
 
+	if IsEventStream(contentType) {
+		stopTimer()
+		return Response{
+			StatusCode:   resp.StatusCode,
+			Proto:        resp.Proto,
+			Headers:      headers,
+			ContentType:  contentType,
+			ResponseTime: time.Since(start),
+			Stream:       newEventStream(ctx, func() { cancel(nil) }, resp.Body),
+
+			URL:             resp.Request.URL.String(),
+			Redirects:       redirects.hops,
+			RedirectStopped: redirects.stopped,
+			SetCookies:      redirects.finalCookies(resp),
+			Connection:      connection.response(resp),
+		}, false
+	}
+
+	defer cancel(nil)
+	defer stopTimer()
+	defer resp.Body.Close()
+
+	// From here the timeout only ends a body that stops arriving
+	if timer != nil {
+		resp.Body = &idleBody{ReadCloser: resp.Body, timer: timer, timeout: c.Timeout}
+	}
+
+	// Read response body
+	body, bodyFile, bodySize, err := c.readBody(resp, onProgress)
+	if err != nil {
+		if errors.Is(context.Cause(ctx), ErrTimeout) {
+			err = fmt.Errorf("%w: no data for %s", ErrTimeout, c.Timeout)
+		} else {
+			err = c.timeoutError(ctx, err)
+		}
+		return Response{Error: fmt.Errorf("failed to read response body: %w", err)}, true
+	}
+
 	return Response{
 		StatusCode:   resp.StatusCode,
+		Proto:        resp.Proto,
 		Headers:      headers,
 		Body:         string(body),
 		ContentType:  contentType,
 		ResponseTime: time.Since(start),
+		BodySize:     bodySize,
+		BodyFile:     bodyFile,
+		Binary:       IsBinary(body, contentType),
+
+		URL:             resp.Request.URL.String(),
+		Redirects:       redirects.hops,
+		RedirectStopped: redirects.stopped,
+		SetCookies:      redirects.finalCookies(resp),
+		Connection:      connection.response(resp),
+	}, false
+}
+
+// timeoutError replaces the context cancellation error with ErrTimeout when
+// the request was cancelled by the client's timer, or ErrCancelled when
+// its caller cancelled it
+func (c *Client) timeoutError(ctx context.Context, err error) error {
+	switch cause := context.Cause(ctx); {
+	case errors.Is(cause, ErrTimeout):
+		return fmt.Errorf("%w after %s", ErrTimeout, c.Timeout)
+	case errors.Is(cause, ErrCancelled):
+		return ErrCancelled
 	}
+	return err
+}
+
+// idleBody restarts the request's timer whenever body data arrives
+type idleBody struct {
+	io.ReadCloser
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (b *idleBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.timer.Reset(b.timeout)
+	}
+	return n, err
+}
+
+// readBody reads up to MaxBodyInMemory bytes into memory. Anything larger is
+// written in full to a temp file, and only the leading bytes are returned.
+func (c *Client) readBody(resp *http.Response, onProgress func(Progress)) ([]byte, string, int64, error) {
+	limit := c.MaxBodyInMemory
+	if limit <= 0 {
+		limit = DefaultMaxBodyInMemory
+	}
+
+	reader := newProgressReader(resp.Body, resp.ContentLength, onProgress)
+	defer reader.finish()
+
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, reader, limit+1)
+	if err == io.EOF {
+		return buf.Bytes(), "", n, nil
+	}
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	f, err := os.CreateTemp("", "quest-body-*")
+	if err != nil {
+		return nil, "", 0, err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		os.Remove(f.Name())
+		return nil, "", 0, err
+	}
+	rest, err := io.Copy(f, reader)
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, "", 0, err
+	}
+
+	return buf.Bytes()[:limit], f.Name(), n + rest, nil
 }
 
 func FormatResponse(body string) string {
@@ -120,3 +446,19 @@ func ValidateURL(url string) error {
 
 	return nil
 }
+
+// ValidateMethod checks that method is an HTTP token, which is all a
+// method name has to be; custom methods are sent exactly as written
+func ValidateMethod(method string) error {
+	if method == "" {
+		return fmt.Errorf("method cannot be empty")
+	}
+
+	for _, c := range method {
+		if c > unicode.MaxASCII || !(unicode.IsLetter(c) || unicode.IsDigit(c) || strings.ContainsRune("!#$%&'*+-.^_`|~", c)) {
+			return fmt.Errorf("method cannot contain %q", c)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSendContentType(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults map[string]string
+		req      Request
+		want     string
+	}{
+		{
+			name: "inferred",
+			req:  Request{Body: `{"a": 1}`},
+			want: "application/json",
+		},
+		{
+			name:     "default header over inferred",
+			defaults: map[string]string{"content-type": "application/vnd.api+json"},
+			req:      Request{Body: `{"a": 1}`},
+			want:     "application/vnd.api+json",
+		},
+		{
+			name:     "chosen over default header",
+			defaults: map[string]string{"Content-Type": "application/vnd.api+json"},
+			req:      Request{Body: `a=1`, ContentType: "text/plain"},
+			want:     "text/plain",
+		},
+		{
+			name:     "form over default header",
+			defaults: map[string]string{"Content-Type": "application/json"},
+			req:      Request{BodyMode: URLEncodedBody, Form: []FormField{{Name: "a", Value: "1"}}},
+			want:     "application/x-www-form-urlencoded",
+		},
+		{
+			name:     "request header over everything",
+			defaults: map[string]string{"Content-Type": "application/vnd.api+json"},
+			req:      Request{Body: `{"a": 1}`, ContentType: "text/plain", Headers: map[string]string{"content-type": "application/xml"}},
+			want:     "application/xml",
+		},
+		{
+			name:     "no body",
+			defaults: map[string]string{"X-Team": "a"},
+			req:      Request{},
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Values("Content-Type")
+			}))
+			defer server.Close()
+
+			client := NewClient()
+			client.Headers = tt.defaults
+			tt.req.Method, tt.req.URL = "POST", server.URL
+			if resp := client.SendRequest(tt.req); resp.Error != nil {
+				t.Fatal(resp.Error)
+			}
+			if len(got) > 1 {
+				t.Fatalf("sent %d Content-Type headers: %q", len(got), got)
+			}
+			if strings.Join(got, "") != tt.want {
+				t.Errorf("Content-Type = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMethod(t *testing.T) {
+	tests := []struct {
+		method string
+		valid  bool
+	}{
+		{"GET", true},
+		{"PROPFIND", true},
+		{"purge", true},
+		{"X-CUSTOM_1", true},
+		{"M!#$%&'*+-.^_`|~", true},
+		{"", false},
+		{"GET POST", false},
+		{"GET\r\n", false},
+		{"LIST(1)", false},
+		{"MÉTHODE", false},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateMethod(tt.method); (err == nil) != tt.valid {
+			t.Errorf("ValidateMethod(%q) = %v, want valid %v", tt.method, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Connection describes the connection a response arrived on
+type Connection struct {
+	RemoteAddr string
+	LocalAddr  string
+	// Reused is set when the request went over a kept-alive connection,
+	// which had been idle for IdleTime
+	Reused   bool
+	IdleTime time.Duration
+
+	// TLS is nil for plain HTTP
+	TLS *tls.ConnectionState
+	// Certificates is the chain the server presented, leaf first. It's set
+	// even when the chain failed verification.
+	Certificates []*x509.Certificate
+}
+
+// Verified reports whether the server's certificate chain was verified
+func (c Connection) Verified() bool {
+	return c.TLS != nil && len(c.TLS.VerifiedChains) > 0
+}
+
+// connectionTracker records the connection used by the last request it
+// traces. Dials may race, so it's guarded by a mutex.
+type connectionTracker struct {
+	mu   sync.Mutex
+	conn Connection
+}
+
+func (t *connectionTracker) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		ConnectDone: func(_, addr string, err error) {
+			if err == nil {
+				t.mu.Lock()
+				t.conn.RemoteAddr = addr
+				t.mu.Unlock()
+			}
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, _ error) {
+			t.mu.Lock()
+			t.conn.TLS = &state
+			t.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.conn.RemoteAddr = info.Conn.RemoteAddr().String()
+			t.conn.LocalAddr = info.Conn.LocalAddr().String()
+			t.conn.Reused = info.Reused
+			t.conn.IdleTime = info.IdleTime
+		},
+	}
+}
+
+// response describes the connection resp arrived on
+func (t *connectionTracker) response(resp *http.Response) *Connection {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	conn := t.conn
+	conn.TLS = resp.TLS
+	if resp.TLS != nil {
+		conn.Certificates = resp.TLS.PeerCertificates
+	}
+	return &conn
+}
+
+// failure describes what was learned about the connection before err
+// ended the request, including the chain of a certificate that failed
+// verification. It's nil if nothing was.
+func (t *connectionTracker) failure(err error) *Connection {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	conn := t.conn
+	var verifyErr *tls.CertificateVerificationError
+	switch {
+	case errors.As(err, &verifyErr):
+		conn.Certificates = verifyErr.UnverifiedCertificates
+	case conn.TLS != nil:
+		conn.Certificates = conn.TLS.PeerCertificates
+	}
+	if conn.RemoteAddr == "" && conn.TLS == nil && len(conn.Certificates) == 0 {
+		return nil
+	}
+	return &conn
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendRequestConnection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := NewClient()
+	for i, reused := range []bool{false, true} {
+		resp := client.SendRequest(Request{Method: "GET", URL: server.URL})
+		if resp.Error != nil {
+			t.Fatalf("request %d: %v", i, resp.Error)
+		}
+		conn := resp.Connection
+		if conn == nil {
+			t.Fatalf("request %d has no connection details", i)
+		}
+		if conn.RemoteAddr != server.Listener.Addr().String() || conn.LocalAddr == "" {
+			t.Errorf("request %d addresses = %q -> %q", i, conn.LocalAddr, conn.RemoteAddr)
+		}
+		if conn.Reused != reused {
+			t.Errorf("request %d Reused = %v, want %v", i, conn.Reused, reused)
+		}
+		if conn.TLS != nil || conn.Verified() {
+			t.Errorf("request %d over plain HTTP has TLS details", i)
+		}
+	}
+}
+
+func TestSendRequestConnectionTLS(t *testing.T) {
+	ca := issue(t, "Quest Test CA", nil, x509.ExtKeyUsageAny)
+	server := mutualTLSServer(t, ca)
+	caFile := ca.writePEM(t, t.TempDir(), "ca.pem", false)
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	url := "https://quest.test:" + port
+	target := Target{Resolve: []string{"quest.test:" + port + ":127.0.0.1"}}
+
+	tests := []struct {
+		name     string
+		tls      TLSConfig
+		failed   bool
+		verified bool
+	}{
+		{"verified", TLSConfig{CAFile: caFile}, false, true},
+		{"insecure", TLSConfig{Insecure: true}, false, false},
+		{"untrusted", TLSConfig{}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := NewClient().SendRequest(Request{Method: "GET", URL: url, TLS: tt.tls, Target: target})
+			if (resp.Error != nil) != tt.failed {
+				t.Fatalf("SendRequest error = %v, want failure %v", resp.Error, tt.failed)
+			}
+			conn := resp.Connection
+			if conn == nil {
+				t.Fatal("no connection details")
+			}
+			if conn.Verified() != tt.verified {
+				t.Errorf("Verified = %v, want %v", conn.Verified(), tt.verified)
+			}
+			// The chain is shown even when it fails verification
+			if len(conn.Certificates) != 1 || conn.Certificates[0].Subject.CommonName != "quest.test" {
+				t.Errorf("Certificates = %d, want the server's leaf", len(conn.Certificates))
+			}
+		})
+	}
+}
+
+func TestSendRequestConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	resp := NewClient().SendRequest(Request{Method: "GET", URL: "http://" + addr})
+	if resp.Error == nil {
+		t.Fatal("request to a closed port succeeded")
+	}
+	if resp.Connection != nil {
+		t.Errorf("Connection = %+v for a connection that was never made, want nil", resp.Connection)
+	}
+}
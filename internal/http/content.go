@@ -0,0 +1,115 @@
+package http
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// BodyContentTypes are the types offered for raw bodies, in the order the
+// Body tab cycles through them
+var BodyContentTypes = []string{
+	"application/json",
+	"application/xml",
+	"application/x-www-form-urlencoded",
+	"text/plain",
+}
+
+// InferContentType guesses the media type of a raw body: JSON, XML (or
+// HTML), URL-encoded form data, or plain text
+func InferContentType(body string) string {
+	trimmed := strings.TrimSpace(body)
+	switch {
+	case trimmed == "":
+		return ""
+	case (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid([]byte(trimmed)):
+		return "application/json"
+	case trimmed[0] == '<':
+		lower := strings.ToLower(trimmed)
+		if strings.HasPrefix(lower, "<!doctype html") || strings.HasPrefix(lower, "<html") {
+			return "text/html"
+		}
+		if wellFormedXML(trimmed) == nil {
+			return "application/xml"
+		}
+	case looksURLEncoded(trimmed):
+		return "application/x-www-form-urlencoded"
+	}
+	return "text/plain"
+}
+
+// ContentTypeMismatch explains why body doesn't look like the declared
+// type, or returns "" when it plausibly does or the type isn't checked
+func ContentTypeMismatch(declared, body string) string {
+	mediaType, _, err := mime.ParseMediaType(declared)
+	if err != nil || strings.TrimSpace(body) == "" {
+		return ""
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if !json.Valid([]byte(body)) {
+			return "body is not valid JSON"
+		}
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		if err := wellFormedXML(body); err != nil {
+			return "body is not well-formed XML: " + err.Error()
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		if !looksURLEncoded(strings.TrimSpace(body)) {
+			return "body is not URL-encoded form data"
+		}
+	case mediaType == "text/plain":
+		trimmed := strings.TrimSpace(body)
+		switch inferred := InferContentType(body); {
+		case inferred == "application/json" || inferred == "application/xml":
+			return fmt.Sprintf("body looks like %s", inferred)
+		case trimmed[0] == '{' || trimmed[0] == '[':
+			return "body looks like JSON but is not valid"
+		case trimmed[0] == '<' && inferred != "text/html":
+			return "body looks like XML but is not well-formed"
+		}
+	}
+	return ""
+}
+
+func wellFormedXML(body string) error {
+	decoder := xml.NewDecoder(strings.NewReader(body))
+	elements := 0
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := token.(xml.StartElement); ok {
+			elements++
+		}
+	}
+	if elements == 0 {
+		return errors.New("no root element")
+	}
+	return nil
+}
+
+// looksURLEncoded reports whether s is key=value pairs joined by & with
+// nothing that would need escaping
+func looksURLEncoded(s string) bool {
+	if s == "" || !strings.Contains(s, "=") || strings.ContainsAny(s, " \t\r\n\"<>{}") {
+		return false
+	}
+	for _, pair := range strings.Split(s, "&") {
+		if key, _, _ := strings.Cut(pair, "="); key == "" {
+			return false
+		}
+	}
+	_, err := url.ParseQuery(s)
+	return err == nil
+}
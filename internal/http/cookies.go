@@ -0,0 +1,337 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// Cookie is a cookie held in a CookieJar
+type Cookie struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Domain string `json:"domain"`
+	Path   string `json:"path"`
+	// HostOnly cookies are only sent to Domain itself, not its subdomains
+	HostOnly bool   `json:"host_only,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+	HTTPOnly bool   `json:"http_only,omitempty"`
+	SameSite string `json:"same_site,omitempty"`
+	// Expires is zero for session cookies, which are kept until cleared
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+// Expired reports whether the cookie has expired at now
+func (c Cookie) Expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !c.Expires.After(now)
+}
+
+func (c Cookie) sameKey(other Cookie) bool {
+	return c.Name == other.Name && c.Domain == other.Domain && c.Path == other.Path
+}
+
+// sentTo reports whether the cookie belongs in a request to u
+func (c Cookie) sentTo(u *url.URL) bool {
+	host := canonicalHost(u.Host)
+	if c.HostOnly && host != c.Domain {
+		return false
+	}
+	if !c.HostOnly && !domainMatch(host, c.Domain) {
+		return false
+	}
+	if c.Secure && u.Scheme != "https" && u.Scheme != "wss" {
+		return false
+	}
+	return pathMatch(u.EscapedPath(), c.Path)
+}
+
+// SetCookie is a cookie a response asked to set, with the URL that set it
+type SetCookie struct {
+	URL    string
+	Cookie *http.Cookie
+}
+
+// CookieJar is an http.CookieJar whose cookies can be listed, edited and
+// saved. As in browsers, a response can't set a cookie for a public suffix
+// such as com or co.uk, which would send it to every site under it.
+type CookieJar struct {
+	mu      sync.Mutex
+	cookies []Cookie
+}
+
+func NewCookieJar() *CookieJar {
+	return &CookieJar{}
+}
+
+// SetCookies stores the cookies a response from u set, following the
+// domain, path and expiry rules of RFC 6265
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	host := canonicalHost(u.Host)
+	for _, hc := range cookies {
+		c := Cookie{
+			Name:     hc.Name,
+			Value:    hc.Value,
+			Domain:   strings.ToLower(strings.TrimPrefix(hc.Domain, ".")),
+			Path:     hc.Path,
+			Secure:   hc.Secure,
+			HTTPOnly: hc.HttpOnly,
+			SameSite: sameSiteName(hc.SameSite),
+		}
+
+		switch {
+		case c.Domain == "":
+			c.Domain, c.HostOnly = host, true
+		case !domainMatch(host, c.Domain):
+			continue
+		case isPublicSuffix(c.Domain):
+			// Only the suffix's own host may set one, and only for itself
+			if host != c.Domain {
+				continue
+			}
+			c.HostOnly = true
+		}
+		if c.Path == "" || !strings.HasPrefix(c.Path, "/") {
+			c.Path = defaultPath(u.EscapedPath())
+		}
+
+		switch {
+		case hc.MaxAge < 0:
+			c.Expires = now
+		case hc.MaxAge > 0:
+			c.Expires = now.Add(time.Duration(hc.MaxAge) * time.Second)
+		case !hc.Expires.IsZero():
+			c.Expires = hc.Expires
+		}
+
+		j.remove(c)
+		if !c.Expired(now) {
+			j.cookies = append(j.cookies, c)
+		}
+	}
+}
+
+// Cookies returns the cookies to send with a request to u, longest paths
+// first
+func (j *CookieJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	var matched []Cookie
+	for _, c := range j.cookies {
+		if !c.Expired(now) && c.sentTo(u) {
+			matched = append(matched, c)
+		}
+	}
+	sort.SliceStable(matched, func(a, b int) bool {
+		return len(matched[a].Path) > len(matched[b].Path)
+	})
+
+	cookies := make([]*http.Cookie, len(matched))
+	for i, c := range matched {
+		cookies[i] = &http.Cookie{Name: c.Name, Value: c.Value}
+	}
+	return cookies
+}
+
+// All lists the unexpired cookies by domain, then path, then name
+func (j *CookieJar) All() []Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	var cookies []Cookie
+	for _, c := range j.cookies {
+		if !c.Expired(now) {
+			cookies = append(cookies, c)
+		}
+	}
+	sort.Slice(cookies, func(a, b int) bool {
+		ca, cb := cookies[a], cookies[b]
+		if ca.Domain != cb.Domain {
+			return ca.Domain < cb.Domain
+		}
+		if ca.Path != cb.Path {
+			return ca.Path < cb.Path
+		}
+		return ca.Name < cb.Name
+	})
+	return cookies
+}
+
+// Set adds c, replacing any cookie with the same name, domain and path
+func (j *CookieJar) Set(c Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.remove(c)
+	j.cookies = append(j.cookies, c)
+}
+
+// Delete removes the cookie with c's name, domain and path
+func (j *CookieJar) Delete(c Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.remove(c)
+}
+
+// DeleteDomain removes every cookie stored for domain
+func (j *CookieJar) DeleteDomain(domain string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	kept := j.cookies[:0]
+	for _, c := range j.cookies {
+		if c.Domain != domain {
+			kept = append(kept, c)
+		}
+	}
+	j.cookies = kept
+}
+
+// Clear removes every cookie
+func (j *CookieJar) Clear() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.cookies = nil
+}
+
+func (j *CookieJar) remove(c Cookie) {
+	for i, existing := range j.cookies {
+		if existing.sameKey(c) {
+			j.cookies = append(j.cookies[:i], j.cookies[i+1:]...)
+			return
+		}
+	}
+}
+
+// LoadCookieJar reads the jar for env from a cookie file, which holds a jar
+// per environment. A missing file gives an empty jar.
+func LoadCookieJar(path, env string) (*CookieJar, error) {
+	jars, err := readCookieFile(path)
+	if err != nil {
+		return NewCookieJar(), err
+	}
+
+	// Drop public suffix cookies saved before they were refused
+	var cookies []Cookie
+	for _, c := range jars[env] {
+		if c.HostOnly || !isPublicSuffix(c.Domain) {
+			cookies = append(cookies, c)
+		}
+	}
+	return &CookieJar{cookies: cookies}, nil
+}
+
+// SaveCookieJar writes the jar as env's cookies in the cookie file, leaving
+// other environments' cookies as they are
+func SaveCookieJar(path, env string, jar *CookieJar) error {
+	jars, err := readCookieFile(path)
+	if err != nil {
+		return err
+	}
+
+	if cookies := jar.All(); len(cookies) > 0 {
+		jars[env] = cookies
+	} else {
+		delete(jars, env)
+	}
+
+	data, err := json.MarshalIndent(jars, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Cookies are often credentials
+	return os.WriteFile(path, data, 0600)
+}
+
+func readCookieFile(path string) (map[string][]Cookie, error) {
+	jars := make(map[string][]Cookie)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return jars, nil
+	}
+	if err != nil {
+		return jars, err
+	}
+	if err := json.Unmarshal(data, &jars); err != nil {
+		return make(map[string][]Cookie), err
+	}
+	return jars, nil
+}
+
+// canonicalHost is the lowercased host without its port
+func canonicalHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.Trim(host, "[]"))
+}
+
+// domainMatch reports whether host is domain or one of its subdomains. IP
+// addresses only match themselves.
+func domainMatch(host, domain string) bool {
+	if host == domain {
+		return true
+	}
+	return net.ParseIP(host) == nil && strings.HasSuffix(host, "."+domain)
+}
+
+// isPublicSuffix reports whether domain is one under which anyone can
+// register names, such as com, co.uk or github.io. Names outside the list,
+// like localhost, count as suffixes of their own.
+func isPublicSuffix(domain string) bool {
+	if net.ParseIP(domain) != nil {
+		return false
+	}
+	suffix, _ := publicsuffix.PublicSuffix(domain)
+	return suffix == domain
+}
+
+// pathMatch implements the path-match rule of RFC 6265 section 5.1.4
+func pathMatch(requestPath, cookiePath string) bool {
+	if requestPath == "" {
+		requestPath = "/"
+	}
+	if requestPath == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(requestPath, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || requestPath[len(cookiePath)] == '/'
+}
+
+// defaultPath is the directory of the request path, per RFC 6265
+// section 5.1.4
+func defaultPath(requestPath string) string {
+	i := strings.LastIndex(requestPath, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return requestPath[:i]
+}
+
+func sameSiteName(mode http.SameSite) string {
+	switch mode {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	}
+	return ""
+}
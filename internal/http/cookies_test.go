@@ -0,0 +1,235 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+// cookieNames lists the names of the cookies the jar sends to rawURL
+func cookieNames(t *testing.T, jar *CookieJar, rawURL string) []string {
+	t.Helper()
+	var names []string
+	for _, c := range jar.Cookies(mustParseURL(t, rawURL)) {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestCookieJarDomains(t *testing.T) {
+	tests := []struct {
+		name   string
+		setBy  string
+		cookie *http.Cookie
+		sentTo map[string]bool
+	}{
+		{
+			name:   "host only",
+			setBy:  "https://api.example.com/",
+			cookie: &http.Cookie{Name: "c", Value: "1"},
+			sentTo: map[string]bool{
+				"https://api.example.com/":      true,
+				"https://api.example.com:8443/": true,
+				"https://example.com/":          false,
+				"https://v2.api.example.com/":   false,
+			},
+		},
+		{
+			name:   "domain",
+			setBy:  "https://api.example.com/",
+			cookie: &http.Cookie{Name: "c", Value: "1", Domain: ".Example.com"},
+			sentTo: map[string]bool{
+				"https://example.com/":        true,
+				"https://www.example.com/":    true,
+				"https://v2.api.example.com/": true,
+				"https://badexample.com/":     false,
+				"https://example.org/":        false,
+			},
+		},
+		{
+			name:   "domain not covering the host",
+			setBy:  "https://api.example.com/",
+			cookie: &http.Cookie{Name: "c", Value: "1", Domain: "other.com"},
+			sentTo: map[string]bool{"https://other.com/": false, "https://api.example.com/": false},
+		},
+		{
+			name:   "top-level domain",
+			setBy:  "https://api.example.com/",
+			cookie: &http.Cookie{Name: "c", Value: "1", Domain: "com"},
+			sentTo: map[string]bool{"https://other.com/": false, "https://api.example.com/": false},
+		},
+		{
+			name:   "multi-label public suffix",
+			setBy:  "https://shop.example.co.uk/",
+			cookie: &http.Cookie{Name: "c", Value: "1", Domain: "co.uk"},
+			sentTo: map[string]bool{"https://other.co.uk/": false, "https://shop.example.co.uk/": false},
+		},
+		{
+			name:   "private public suffix",
+			setBy:  "https://me.github.io/",
+			cookie: &http.Cookie{Name: "c", Value: "1", Domain: "github.io"},
+			sentTo: map[string]bool{"https://you.github.io/": false, "https://me.github.io/": false},
+		},
+		{
+			name:   "registrable domain under a public suffix",
+			setBy:  "https://shop.example.co.uk/",
+			cookie: &http.Cookie{Name: "c", Value: "1", Domain: "example.co.uk"},
+			sentTo: map[string]bool{"https://www.example.co.uk/": true, "https://other.co.uk/": false},
+		},
+		{
+			name:   "single label host",
+			setBy:  "http://localhost:8080/",
+			cookie: &http.Cookie{Name: "c", Value: "1", Domain: "localhost"},
+			sentTo: map[string]bool{"http://localhost:9090/": true, "http://api.localhost/": false},
+		},
+		{
+			name:   "IP address",
+			setBy:  "http://127.0.0.1:8080/",
+			cookie: &http.Cookie{Name: "c", Value: "1"},
+			sentTo: map[string]bool{"http://127.0.0.1/": true, "http://127.0.0.2/": false},
+		},
+		{
+			name:   "secure",
+			setBy:  "https://example.com/",
+			cookie: &http.Cookie{Name: "c", Value: "1", Secure: true},
+			sentTo: map[string]bool{"https://example.com/": true, "http://example.com/": false, "wss://example.com/": true},
+		},
+		{
+			name:   "path",
+			setBy:  "https://example.com/api/users",
+			cookie: &http.Cookie{Name: "c", Value: "1"},
+			sentTo: map[string]bool{
+				"https://example.com/api":       true,
+				"https://example.com/api/items": true,
+				"https://example.com/apis":      false,
+				"https://example.com/":          false,
+			},
+		},
+		{
+			name:   "expired",
+			setBy:  "https://example.com/",
+			cookie: &http.Cookie{Name: "c", Value: "1", MaxAge: -1},
+			sentTo: map[string]bool{"https://example.com/": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jar := NewCookieJar()
+			jar.SetCookies(mustParseURL(t, tt.setBy), []*http.Cookie{tt.cookie})
+			for rawURL, want := range tt.sentTo {
+				if got := len(cookieNames(t, jar, rawURL)) == 1; got != want {
+					t.Errorf("sent to %s = %v, want %v", rawURL, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCookieJarReplacesAndOrders(t *testing.T) {
+	jar := NewCookieJar()
+	u := mustParseURL(t, "https://example.com/a/b/c")
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "root", Value: "1", Path: "/"},
+		{Name: "deep", Value: "1", Path: "/a/b"},
+		{Name: "root", Value: "2", Path: "/"},
+	})
+
+	cookies := jar.Cookies(u)
+	if len(cookies) != 2 || cookies[0].Name != "deep" || cookies[1].Value != "2" {
+		t.Errorf("cookies = %v, want deep then root=2", cookies)
+	}
+}
+
+func TestCookieJarRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".quest-cookies")
+	expires := time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+
+	staging := NewCookieJar()
+	staging.Set(Cookie{Name: "session", Value: "s", Domain: "example.com", Path: "/", HostOnly: true, Secure: true, HTTPOnly: true, SameSite: "Lax"})
+	staging.Set(Cookie{Name: "pref", Value: "dark", Domain: "example.com", Path: "/app", Expires: expires})
+	if err := SaveCookieJar(path, "staging", staging); err != nil {
+		t.Fatal(err)
+	}
+
+	production := NewCookieJar()
+	production.Set(Cookie{Name: "session", Value: "p", Domain: "example.com", Path: "/"})
+	if err := SaveCookieJar(path, "production", production); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("cookie file mode = %o, want 600", mode)
+	}
+
+	for env, want := range map[string]*CookieJar{"staging": staging, "production": production} {
+		loaded, err := LoadCookieJar(path, env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(loaded.All(), want.All()) {
+			t.Errorf("%s: loaded %+v, want %+v", env, loaded.All(), want.All())
+		}
+	}
+
+	// Saving an empty jar removes the environment and keeps the others
+	if err := SaveCookieJar(path, "staging", NewCookieJar()); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, _ := LoadCookieJar(path, "staging"); len(loaded.All()) != 0 {
+		t.Errorf("staging still has %d cookies", len(loaded.All()))
+	}
+	if loaded, _ := LoadCookieJar(path, "production"); len(loaded.All()) != 1 {
+		t.Errorf("production has %d cookies, want 1", len(loaded.All()))
+	}
+}
+
+func TestLoadCookieJar(t *testing.T) {
+	dir := t.TempDir()
+
+	jar, err := LoadCookieJar(filepath.Join(dir, "missing"), "default")
+	if err != nil || len(jar.All()) != 0 {
+		t.Errorf("missing file: %d cookies, %v", len(jar.All()), err)
+	}
+
+	damaged := filepath.Join(dir, "damaged")
+	os.WriteFile(damaged, []byte("{"), 0600)
+	if _, err := LoadCookieJar(damaged, "default"); err == nil {
+		t.Error("damaged file loaded without an error")
+	}
+
+	saved := filepath.Join(dir, "saved")
+	os.WriteFile(saved, []byte(`{"default": [
+		{"name": "a", "value": "1", "domain": "com", "path": "/"},
+		{"name": "b", "value": "1", "domain": "localhost", "path": "/", "host_only": true},
+		{"name": "c", "value": "1", "domain": "example.com", "path": "/"}
+	]}`), 0600)
+	jar, err = LoadCookieJar(saved, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, c := range jar.All() {
+		names = append(names, c.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"c", "b"}) {
+		t.Errorf("loaded %v, want the public suffix cookie dropped", names)
+	}
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// extensions maps common media types to the file extension used when a
+// response is saved without a filename of its own
+var extensions = map[string]string{
+	"application/json":         ".json",
+	"application/xml":          ".xml",
+	"application/yaml":         ".yaml",
+	"application/javascript":   ".js",
+	"application/pdf":          ".pdf",
+	"application/zip":          ".zip",
+	"application/gzip":         ".gz",
+	"application/octet-stream": ".bin",
+	"application/x-ndjson":     ".ndjson",
+	"application/protobuf":     ".pb",
+	"application/x-protobuf":   ".pb",
+	"text/plain":               ".txt",
+	"text/html":                ".html",
+	"text/css":                 ".css",
+	"text/csv":                 ".csv",
+	"text/xml":                 ".xml",
+	"text/yaml":                ".yaml",
+	"text/markdown":            ".md",
+	"text/javascript":          ".js",
+	"text/event-stream":        ".txt",
+	"image/png":                ".png",
+	"image/jpeg":               ".jpg",
+	"image/gif":                ".gif",
+	"image/webp":               ".webp",
+	"image/svg+xml":            ".svg",
+}
+
+// SuggestFilename picks a name for saving a response body: the filename
+// given by Content-Disposition, else the last segment of the URL path, else
+// "response". An extension is added from contentType when the name has none.
+func SuggestFilename(headers map[string]string, rawURL, contentType string) string {
+	name := dispositionFilename(headers["Content-Disposition"])
+
+	if name == "" {
+		if u, err := url.Parse(rawURL); err == nil {
+			name = sanitizeFilename(path.Base(u.Path))
+		}
+	}
+	if name == "" {
+		name = "response"
+	}
+
+	if path.Ext(name) == "" {
+		name += extensionFor(contentType)
+	}
+
+	return name
+}
+
+// dispositionFilename extracts the filename parameter of a
+// Content-Disposition header, preferring the RFC 5987 filename* form
+func dispositionFilename(disposition string) string {
+	if disposition == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(disposition)
+	if err != nil {
+		return ""
+	}
+	// mime.ParseMediaType decodes filename* into filename
+	return sanitizeFilename(params["filename"])
+}
+
+// sanitizeFilename strips any directory components so a server can't pick
+// where the file is written
+func sanitizeFilename(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = path.Base(name)
+	if name == "." || name == ".." || name == "/" {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
+
+func extensionFor(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	if ext, ok := extensions[mediaType]; ok {
+		return ext
+	}
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return ".json"
+	case strings.HasSuffix(mediaType, "+xml"):
+		return ".xml"
+	}
+
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// FormatHead renders the status line and headers of a response the way they
+// appear on the wire, ending with the blank line that precedes the body
+func FormatHead(proto string, statusCode int, headers map[string]string) string {
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %d %s\r\n", proto, statusCode, http.StatusText(statusCode))
+
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s: %s\r\n", key, headers[key])
+	}
+
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package http
+
+import "testing"
+
+func TestSuggestFilename(t *testing.T) {
+	tests := []struct {
+		name        string
+		disposition string
+		url         string
+		contentType string
+		want        string
+	}{
+		{"disposition", `attachment; filename="report.csv"`, "https://example.com/export", "text/csv", "report.csv"},
+		{"RFC 5987 filename", `attachment; filename*=UTF-8''r%C3%A9sum%C3%A9.pdf`, "https://example.com/x", "", "résumé.pdf"},
+		{"disposition path is stripped", `attachment; filename="../../etc/passwd"`, "https://example.com/x", "", "passwd"},
+		{"windows path is stripped", `attachment; filename="C:\\Temp\\a.txt"`, "https://example.com/x", "", "a.txt"},
+		{"malformed disposition falls back to the URL", `attachment; filename="`, "https://example.com/files/data.json", "", "data.json"},
+		{"URL path", "", "https://example.com/files/data.json?x=1", "application/json", "data.json"},
+		{"extension from content type", "", "https://example.com/users/42", "application/json; charset=utf-8", "42.json"},
+		{"structured suffix", "", "https://example.com/problem", "application/problem+json", "problem.json"},
+		{"xml suffix", "", "https://example.com/feed", "application/atom+xml", "feed.xml"},
+		{"no path", "", "https://example.com/", "text/html", "response.html"},
+		{"unknown type", "", "https://example.com/", "application/x-unknown-thing", "response"},
+		{"no content type", "", "https://example.com/blob", "", "blob"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{}
+			if tt.disposition != "" {
+				headers["Content-Disposition"] = tt.disposition
+			}
+			if got := SuggestFilename(headers, tt.url, tt.contentType); got != tt.want {
+				t.Errorf("SuggestFilename(%q, %q, %q) = %q, want %q", tt.disposition, tt.url, tt.contentType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatHead(t *testing.T) {
+	got := FormatHead("", 404, map[string]string{"X-B": "2", "Content-Type": "text/plain"})
+	want := "HTTP/1.1 404 Not Found\r\nContent-Type: text/plain\r\nX-B: 2\r\n\r\n"
+	if got != want {
+		t.Errorf("FormatHead = %q, want %q", got, want)
+	}
+}
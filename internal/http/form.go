@@ -0,0 +1,177 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BodyMode is how a request body is put together
+type BodyMode int
+
+const (
+	// RawBody sends Request.Body as is
+	RawBody BodyMode = iota
+	// MultipartBody sends Request.Form as multipart/form-data
+	MultipartBody
+	// URLEncodedBody sends Request.Form as application/x-www-form-urlencoded
+	URLEncodedBody
+)
+
+var bodyModeNames = map[BodyMode]string{
+	RawBody:        "raw",
+	MultipartBody:  "multipart",
+	URLEncodedBody: "urlencoded",
+}
+
+func (m BodyMode) String() string {
+	return bodyModeNames[m]
+}
+
+// ParseBodyMode is the inverse of BodyMode.String; unknown names are raw
+func ParseBodyMode(name string) BodyMode {
+	for mode, modeName := range bodyModeNames {
+		if modeName == name {
+			return mode
+		}
+	}
+	return RawBody
+}
+
+// FormField is a field of a form body. For file fields Value is the path of
+// the file to upload; URL-encoded bodies send the file's contents instead.
+type FormField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	File  bool   `json:"file,omitempty"`
+	// ContentType overrides the part's content type in multipart bodies
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// body returns the request body and the Content-Type to send it with, or
+// nil and "" when there is nothing to send
+func (req Request) body() (io.Reader, string, error) {
+	if req.BodyMode != RawBody && len(req.Form) == 0 {
+		return nil, "", nil
+	}
+
+	switch req.BodyMode {
+	case URLEncodedBody:
+		values := url.Values{}
+		for _, field := range req.Form {
+			value := field.Value
+			if field.File {
+				data, err := os.ReadFile(field.Value)
+				if err != nil {
+					return nil, "", fmt.Errorf("form field %q: %w", field.Name, err)
+				}
+				value = string(data)
+			}
+			values.Add(field.Name, value)
+		}
+		return strings.NewReader(values.Encode()), "application/x-www-form-urlencoded", nil
+
+	case MultipartBody:
+		return multipartBody(req.Form)
+	}
+
+	if req.Body == "" {
+		return nil, "", nil
+	}
+	contentType := req.ContentType
+	if contentType == "" {
+		contentType = InferContentType(req.Body)
+	}
+	return strings.NewReader(req.Body), contentType, nil
+}
+
+// multipartBody streams the fields as multipart/form-data, reading file
+// parts from disk as the request is sent rather than up front
+func multipartBody(fields []FormField) (io.Reader, string, error) {
+	for _, field := range fields {
+		if !field.File {
+			continue
+		}
+		info, err := os.Stat(field.Value)
+		if err != nil {
+			return nil, "", fmt.Errorf("form field %q: %w", field.Name, err)
+		}
+		if info.IsDir() {
+			return nil, "", fmt.Errorf("form field %q: %s is a directory", field.Name, field.Value)
+		}
+	}
+
+	reader, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		writer.CloseWithError(writeMultipart(form, fields))
+	}()
+	return reader, form.FormDataContentType(), nil
+}
+
+func writeMultipart(form *multipart.Writer, fields []FormField) error {
+	for _, field := range fields {
+		header := make(textproto.MIMEHeader)
+
+		if !field.File {
+			header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(field.Name)))
+			if field.ContentType != "" {
+				header.Set("Content-Type", field.ContentType)
+			}
+			part, err := form.CreatePart(header)
+			if err != nil {
+				return err
+			}
+			if _, err := io.WriteString(part, field.Value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(field.Name), escapeQuotes(filepath.Base(field.Value))))
+		header.Set("Content-Type", FileContentType(field))
+		part, err := form.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		if err := copyFile(part, field.Value); err != nil {
+			return err
+		}
+	}
+	return form.Close()
+}
+
+func copyFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// FileContentType is the content type a file field is uploaded with: its
+// override, or a guess from the file extension
+func FileContentType(field FormField) string {
+	if field.ContentType != "" {
+		return field.ContentType
+	}
+	if guessed := mime.TypeByExtension(filepath.Ext(field.Value)); guessed != "" {
+		return guessed
+	}
+	return "application/octet-stream"
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
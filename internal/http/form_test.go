@@ -0,0 +1,162 @@
+package http
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMultipartBody(t *testing.T) {
+	dir := t.TempDir()
+	upload := filepath.Join(dir, `re"port.json`)
+	if err := os.WriteFile(upload, []byte(`{"ok":true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	blob := filepath.Join(dir, "blob")
+	if err := os.WriteFile(blob, []byte{0, 1, 2}, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	req := Request{BodyMode: MultipartBody, Form: []FormField{
+		{Name: "title", Value: "hello world"},
+		{Name: "meta", Value: "<a/>", ContentType: "application/xml"},
+		{Name: "doc", Value: upload, File: true},
+		{Name: "raw", Value: blob, File: true},
+		{Name: "typed", Value: blob, File: true, ContentType: "image/png"},
+	}}
+
+	body, contentType, err := req.body()
+	if err != nil {
+		t.Fatalf("body: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		t.Fatalf("Content-Type = %q, want multipart/form-data with a boundary", contentType)
+	}
+
+	type part struct {
+		name, filename, contentType, data string
+	}
+	want := []part{
+		{"title", "", "", "hello world"},
+		{"meta", "", "application/xml", "<a/>"},
+		{"doc", `re"port.json`, "application/json", `{"ok":true}`},
+		{"raw", "blob", "application/octet-stream", "\x00\x01\x02"},
+		{"typed", "blob", "image/png", "\x00\x01\x02"},
+	}
+
+	reader := multipart.NewReader(body, params["boundary"])
+	for i, w := range want {
+		p, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("part %d: %v", i, err)
+		}
+		data, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatalf("part %d: %v", i, err)
+		}
+		got := part{p.FormName(), p.FileName(), p.Header.Get("Content-Type"), string(data)}
+		if got != w {
+			t.Errorf("part %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("after the last part: %v, want EOF", err)
+	}
+}
+
+func TestRequestBody(t *testing.T) {
+	dir := t.TempDir()
+	upload := filepath.Join(dir, "note.txt")
+	if err := os.WriteFile(upload, []byte("a&b"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		req         Request
+		body        string
+		contentType string
+		err         string
+	}{
+		{
+			name:        "url-encoded fields and files",
+			req:         Request{BodyMode: URLEncodedBody, Form: []FormField{{Name: "q", Value: "a b"}, {Name: "f", Value: upload, File: true}}},
+			body:        "f=a%26b&q=a+b",
+			contentType: "application/x-www-form-urlencoded",
+		},
+		{
+			name: "empty form sends nothing",
+			req:  Request{BodyMode: MultipartBody, Body: "ignored"},
+		},
+		{
+			name:        "raw body with an inferred type",
+			req:         Request{Body: `{"a":1}`},
+			body:        `{"a":1}`,
+			contentType: "application/json",
+		},
+		{
+			name:        "raw body with an explicit type",
+			req:         Request{Body: "a=1", ContentType: "text/plain"},
+			body:        "a=1",
+			contentType: "text/plain",
+		},
+		{
+			name: "missing url-encoded file",
+			req:  Request{BodyMode: URLEncodedBody, Form: []FormField{{Name: "f", Value: filepath.Join(dir, "missing"), File: true}}},
+			err:  `form field "f"`,
+		},
+		{
+			name: "missing multipart file",
+			req:  Request{BodyMode: MultipartBody, Form: []FormField{{Name: "f", Value: filepath.Join(dir, "missing"), File: true}}},
+			err:  `form field "f"`,
+		},
+		{
+			name: "directory upload",
+			req:  Request{BodyMode: MultipartBody, Form: []FormField{{Name: "f", Value: dir, File: true}}},
+			err:  "is a directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType, err := tt.req.body()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("body() error = %v, want one mentioning %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("body: %v", err)
+			}
+
+			var got string
+			if body != nil {
+				data, err := io.ReadAll(body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = string(data)
+			}
+			if got != tt.body || contentType != tt.contentType {
+				t.Errorf("body() = %q, %q, want %q, %q", got, contentType, tt.body, tt.contentType)
+			}
+		})
+	}
+}
+
+func TestParseBodyMode(t *testing.T) {
+	for _, mode := range []BodyMode{RawBody, MultipartBody, URLEncodedBody} {
+		if got := ParseBodyMode(mode.String()); got != mode {
+			t.Errorf("ParseBodyMode(%q) = %v, want %v", mode.String(), got, mode)
+		}
+	}
+	if got := ParseBodyMode("bogus"); got != RawBody {
+		t.Errorf("ParseBodyMode(bogus) = %v, want raw", got)
+	}
+}
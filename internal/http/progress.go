@@ -0,0 +1,80 @@
+package http
+
+import (
+	"io"
+	"time"
+)
+
+// progressInterval throttles how often download progress is reported
+const progressInterval = 100 * time.Millisecond
+
+// Progress describes how much of a response body has been received
+type Progress struct {
+	BytesRead int64
+	// Total is the Content-Length, or -1 when the server didn't send one
+	Total   int64
+	Elapsed time.Duration
+
+	// Attempt is the attempt the body belongs to, counting from 1. Retry
+	// is set instead while waiting to retry a failed attempt.
+	Attempt int
+	Retry   *Attempt
+}
+
+// Rate returns the average download rate in bytes per second
+func (p Progress) Rate() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.BytesRead) / p.Elapsed.Seconds()
+}
+
+// Fraction returns how much of the body has arrived, or -1 if unknown
+func (p Progress) Fraction() float64 {
+	if p.Total <= 0 {
+		return -1
+	}
+	return float64(p.BytesRead) / float64(p.Total)
+}
+
+// progressReader counts bytes read and periodically reports them
+type progressReader struct {
+	r          io.Reader
+	total      int64
+	read       int64
+	start      time.Time
+	lastReport time.Time
+	onProgress func(Progress)
+}
+
+func newProgressReader(r io.Reader, total int64, onProgress func(Progress)) *progressReader {
+	now := time.Now()
+	return &progressReader{r: r, total: total, start: now, lastReport: now, onProgress: onProgress}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+
+	if p.onProgress != nil && time.Since(p.lastReport) >= progressInterval {
+		p.lastReport = time.Now()
+		p.report()
+	}
+
+	return n, err
+}
+
+// finish sends a final report so the UI sees the complete byte count
+func (p *progressReader) finish() {
+	if p.onProgress != nil {
+		p.report()
+	}
+}
+
+func (p *progressReader) report() {
+	p.onProgress(Progress{
+		BytesRead: p.read,
+		Total:     p.total,
+		Elapsed:   time.Since(p.start),
+	})
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress Progress
+		rate     float64
+		fraction float64
+	}{
+		{"known length", Progress{BytesRead: 50, Total: 200, Elapsed: 2 * time.Second}, 25, 0.25},
+		{"unknown length", Progress{BytesRead: 50, Total: -1, Elapsed: time.Second}, 50, -1},
+		{"nothing elapsed", Progress{BytesRead: 50, Total: 100}, 0, 0.5},
+	}
+
+	for _, tt := range tests {
+		if rate := tt.progress.Rate(); rate != tt.rate {
+			t.Errorf("%s: Rate() = %v, want %v", tt.name, rate, tt.rate)
+		}
+		if fraction := tt.progress.Fraction(); fraction != tt.fraction {
+			t.Errorf("%s: Fraction() = %v, want %v", tt.name, fraction, tt.fraction)
+		}
+	}
+}
+
+func TestSendRequestSpillsLargeBodies(t *testing.T) {
+	body := strings.Repeat("0123456789", 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		limit  int64
+		body   string
+		size   int64
+		spills bool
+	}{
+		{"under the limit", 200, body, 100, false},
+		{"at the limit", 100, body, 100, false},
+		{"over the limit", 30, body[:30], 100, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient()
+			client.MaxBodyInMemory = tt.limit
+			resp := client.SendRequest(Request{Method: "GET", URL: server.URL})
+			if resp.Error != nil {
+				t.Fatalf("SendRequest: %v", resp.Error)
+			}
+			if resp.BodyFile != "" {
+				defer os.Remove(resp.BodyFile)
+			}
+
+			if resp.Body != tt.body || resp.BodySize != tt.size {
+				t.Errorf("Body = %q (size %d), want %q (size %d)", resp.Body, resp.BodySize, tt.body, tt.size)
+			}
+			if (resp.BodyFile != "") != tt.spills {
+				t.Fatalf("BodyFile = %q, want a file: %v", resp.BodyFile, tt.spills)
+			}
+			if tt.spills {
+				data, err := os.ReadFile(resp.BodyFile)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != body {
+					t.Errorf("BodyFile holds %d bytes, want the full %d", len(data), tt.size)
+				}
+			}
+		})
+	}
+}
+
+func TestSendRequestIdleTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pause, _ := time.ParseDuration(r.URL.Query().Get("pause"))
+		for i := 0; i < 4; i++ {
+			w.Write([]byte("chunk "))
+			w.(http.Flusher).Flush()
+			select {
+			case <-time.After(pause):
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		pause   string
+		timeout bool
+	}{
+		{"steady trickle outlasts the timeout", "60ms", false},
+		{"stall", "500ms", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient()
+			client.Timeout = 150 * time.Millisecond
+			resp := client.SendRequest(Request{Method: "GET", URL: server.URL + "?pause=" + tt.pause})
+
+			if !tt.timeout {
+				if resp.Error != nil || resp.Body != strings.Repeat("chunk ", 4) {
+					t.Errorf("SendRequest = %q, %v, want the full body", resp.Body, resp.Error)
+				}
+				return
+			}
+			if !errors.Is(resp.Error, ErrTimeout) || !strings.Contains(resp.Error.Error(), "no data") {
+				t.Errorf("SendRequest error = %v, want an idle timeout", resp.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxySchemes are the kinds of proxy a ProxyConfig can use. HTTPS
+// requests go through http and https proxies with CONNECT.
+var ProxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+// ProxyConfig routes requests through a proxy. The zero value follows the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+type ProxyConfig struct {
+	// URL is the proxy, with any credentials as user:password@
+	URL string `json:"url,omitempty"`
+	// NoProxy lists hosts, domains and CIDR ranges that are reached
+	// directly, comma separated as in NO_PROXY
+	NoProxy string `json:"no_proxy,omitempty"`
+	// Direct ignores the environment variables and connects directly
+	Direct bool `json:"direct,omitempty"`
+}
+
+// IsZero reports whether c leaves the proxy to the environment
+func (c ProxyConfig) IsZero() bool {
+	return c == ProxyConfig{}
+}
+
+// Merge returns override where it picks a proxy, keeping c's no-proxy list
+// unless override has its own
+func (c ProxyConfig) Merge(override ProxyConfig) ProxyConfig {
+	merged := c
+	if override.URL != "" || override.Direct {
+		merged.URL, merged.Direct = override.URL, override.Direct
+	}
+	if override.NoProxy != "" {
+		merged.NoProxy = override.NoProxy
+	}
+	return merged
+}
+
+// ParseProxyURL checks a proxy URL, defaulting to http:// when it has no
+// scheme
+func ParseProxyURL(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if !isProxyScheme(u.Scheme) {
+		return nil, fmt.Errorf("unsupported proxy scheme %q, use one of %s", u.Scheme, strings.Join(ProxySchemes, ", "))
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", raw)
+	}
+	return u, nil
+}
+
+func isProxyScheme(scheme string) bool {
+	for _, s := range ProxySchemes {
+		if scheme == s {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyFunc is the Transport.Proxy function for c. Requests to localhost
+// are never proxied.
+func (c ProxyConfig) proxyFunc() (func(*url.URL) (*url.URL, error), error) {
+	if c.Direct {
+		return nil, nil
+	}
+
+	config := httpproxy.FromEnvironment()
+	if c.URL != "" {
+		proxyURL, err := ParseProxyURL(c.URL)
+		if err != nil {
+			return nil, err
+		}
+		config.HTTPProxy, config.HTTPSProxy = proxyURL.String(), proxyURL.String()
+	}
+	if c.NoProxy != "" {
+		config.NoProxy = c.NoProxy
+	}
+
+	return config.ProxyFunc(), nil
+}
+
+// ProxyFor reports the proxy a request to rawURL would go through, with
+// any password redacted, or "" when it goes direct
+func (c ProxyConfig) ProxyFor(rawURL string) (string, error) {
+	proxy, err := c.proxyFunc()
+	if proxy == nil || err != nil {
+		return "", err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", err
+	}
+	proxyURL, err := proxy(u)
+	if proxyURL == nil || err != nil {
+		return "", err
+	}
+	return proxyURL.Redacted(), nil
+}
+
+// transportKey identifies the transports a client keeps
+type transportKey struct {
+	tls    TLSConfig
+	proxy  ProxyConfig
+	target string
+}
+
+// transport returns the transport for a TLS and proxy config and target.
+// Transports are kept per config so that connections are reused between
+// requests.
+func (c *Client) transport(tlsConfig TLSConfig, proxy ProxyConfig, target Target) (http.RoundTripper, error) {
+	if tlsConfig.IsZero() && proxy.IsZero() && target.IsZero() {
+		return c.httpClient.Transport, nil
+	}
+	if err := target.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid target: %w", err)
+	}
+
+	key := transportKey{tls: tlsConfig, proxy: proxy, target: target.key()}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if transport, ok := c.transports[key]; ok {
+		return transport, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !tlsConfig.IsZero() {
+		config, err := tlsConfig.Build()
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS settings: %w", err)
+		}
+		transport.TLSClientConfig = config
+	}
+	proxyFunc, err := proxy.proxyFunc()
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy settings: %w", err)
+	}
+	transport.Proxy = nil
+	if proxyFunc != nil && target.UnixSocket == "" {
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	if !target.IsZero() {
+		transport.DialContext = target.dialContext(&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second})
+	}
+
+	if c.transports == nil {
+		c.transports = make(map[transportKey]*http.Transport)
+	}
+	c.transports[key] = transport
+	return transport, nil
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultMaxRedirects is how many redirects are followed when the policy
+// doesn't set a limit
+const DefaultMaxRedirects = 10
+
+// RedirectPolicy controls how a request follows redirects. The zero value
+// follows up to DefaultMaxRedirects hops the way browsers do.
+type RedirectPolicy struct {
+	// NoFollow returns the first redirect response as is
+	NoFollow bool `json:"no_follow,omitempty"`
+	// MaxHops limits how many redirects are followed; zero means
+	// DefaultMaxRedirects
+	MaxHops int `json:"max_hops,omitempty"`
+	// KeepMethod resends the original method and body after a 303 See
+	// Other instead of switching to GET
+	KeepMethod bool `json:"keep_method,omitempty"`
+}
+
+// Limit is the number of redirects the policy follows
+func (p RedirectPolicy) Limit() int {
+	if p.MaxHops > 0 {
+		return p.MaxHops
+	}
+	return DefaultMaxRedirects
+}
+
+// Hop is a redirect response that was followed
+type Hop struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Proto      string
+	Headers    map[string]string
+	// Location is the resolved URL the redirect led to
+	Location string
+	// Duration is the time from sending the hop's request to its response
+	Duration time.Duration
+}
+
+// redirectTracker applies a policy to one request, recording every hop it
+// follows and whether it stopped at a redirect
+type redirectTracker struct {
+	policy  RedirectPolicy
+	hops    []Hop
+	stopped bool
+	sent    time.Time
+	cookies []SetCookie
+}
+
+func newRedirectTracker(policy RedirectPolicy, start time.Time) *redirectTracker {
+	return &redirectTracker{policy: policy, sent: start}
+}
+
+// check is an http.Client CheckRedirect function
+func (t *redirectTracker) check(req *http.Request, via []*http.Request) error {
+	if t.policy.NoFollow || len(via) > t.policy.Limit() {
+		t.stopped = true
+		return http.ErrUseLastResponse
+	}
+
+	previous := via[len(via)-1]
+	now := time.Now()
+	hop := Hop{
+		Method:   previous.Method,
+		URL:      previous.URL.String(),
+		Location: req.URL.String(),
+		Duration: now.Sub(t.sent),
+	}
+	if resp := req.Response; resp != nil {
+		hop.StatusCode = resp.StatusCode
+		hop.Proto = resp.Proto
+		hop.Headers = make(map[string]string, len(resp.Header))
+		for key, values := range resp.Header {
+			hop.Headers[key] = strings.Join(values, ", ")
+		}
+		for _, cookie := range resp.Cookies() {
+			t.cookies = append(t.cookies, SetCookie{URL: hop.URL, Cookie: cookie})
+		}
+	}
+	t.hops = append(t.hops, hop)
+	t.sent = now
+
+	if t.policy.KeepMethod && req.Response != nil && req.Response.StatusCode == http.StatusSeeOther {
+		keepMethod(req, via[0])
+	}
+	return nil
+}
+
+// keepMethod turns the GET that a 303 redirect would make back into the
+// original request's method, resending its body when it can be replayed
+func keepMethod(req, original *http.Request) {
+	if original.Method == http.MethodGet || original.Method == http.MethodHead {
+		return
+	}
+	req.Method = original.Method
+	if original.GetBody == nil {
+		return
+	}
+	body, err := original.GetBody()
+	if err != nil {
+		return
+	}
+	req.Body = body
+	req.GetBody = original.GetBody
+	req.ContentLength = original.ContentLength
+	if contentType := original.Header.Get("Content-Type"); contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+}
+
+// finalCookies adds the cookies set by the final response to those set by
+// the redirects before it
+func (t *redirectTracker) finalCookies(resp *http.Response) []SetCookie {
+	cookies := t.cookies
+	for _, cookie := range resp.Cookies() {
+		cookies = append(cookies, SetCookie{URL: resp.Request.URL.String(), Cookie: cookie})
+	}
+	return cookies
+}
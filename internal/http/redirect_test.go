@@ -0,0 +1,97 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSendRequestRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "from", Value: "a"})
+		http.Redirect(w, r, "/b", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/c", http.StatusSeeOther)
+	})
+	mux.HandleFunc("/c", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		io.WriteString(w, r.Method+" "+r.Header.Get("Content-Type")+" "+string(body))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		policy  RedirectPolicy
+		status  int
+		body    string
+		hops    []string
+		stopped bool
+	}{
+		{
+			name:   "browser behaviour",
+			status: http.StatusOK,
+			body:   "GET  ",
+			hops:   []string{"POST /a 307", "POST /b 303"},
+		},
+		{
+			name:   "keep method after 303",
+			policy: RedirectPolicy{KeepMethod: true},
+			status: http.StatusOK,
+			body:   "POST text/plain payload",
+			hops:   []string{"POST /a 307", "POST /b 303"},
+		},
+		{
+			name:    "no follow",
+			policy:  RedirectPolicy{NoFollow: true},
+			status:  http.StatusTemporaryRedirect,
+			stopped: true,
+		},
+		{
+			name:    "hop limit",
+			policy:  RedirectPolicy{MaxHops: 1},
+			status:  http.StatusSeeOther,
+			hops:    []string{"POST /a 307"},
+			stopped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := NewClient().SendRequest(Request{
+				Method:      "POST",
+				URL:         server.URL + "/a",
+				Body:        "payload",
+				ContentType: "text/plain",
+				Redirects:   tt.policy,
+			})
+			if resp.Error != nil {
+				t.Fatalf("SendRequest: %v", resp.Error)
+			}
+
+			var hops []string
+			for _, hop := range resp.Redirects {
+				hops = append(hops, fmt.Sprintf("%s %s %d", hop.Method, strings.TrimPrefix(hop.URL, server.URL), hop.StatusCode))
+			}
+			if resp.StatusCode != tt.status || resp.RedirectStopped != tt.stopped || len(hops) != len(tt.hops) {
+				t.Fatalf("status %d, stopped %v, hops %q; want %d, %v, %q", resp.StatusCode, resp.RedirectStopped, hops, tt.status, tt.stopped, tt.hops)
+			}
+			for i := range hops {
+				if hops[i] != tt.hops[i] {
+					t.Errorf("hop %d = %q, want %q", i, hops[i], tt.hops[i])
+				}
+			}
+			if len(resp.SetCookies) != 1 || resp.SetCookies[0].Cookie.Name != "from" || resp.SetCookies[0].URL != server.URL+"/a" {
+				t.Errorf("SetCookies = %+v, want the cookie set by /a", resp.SetCookies)
+			}
+			if tt.status == http.StatusOK && resp.Body != tt.body {
+				t.Errorf("final request = %q, want %q", resp.Body, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package http
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRetryStatuses are the statuses retried when the policy doesn't
+	// list its own
+	DefaultRetryStatuses = "408,429,500,502,503,504"
+	// DefaultRetryBackoff is the delay before the first retry when the
+	// policy doesn't set one
+	DefaultRetryBackoff = 500 * time.Millisecond
+	// MaxRetryBackoff caps the delay between attempts as it doubles
+	MaxRetryBackoff = 30 * time.Second
+	// MaxRetryAttempts is the most attempts a policy sends
+	MaxRetryAttempts = 10
+	// MaxRetryAfter is the longest Retry-After that is waited out; a longer
+	// one ends the retries
+	MaxRetryAfter = time.Minute
+)
+
+// RetryPolicy controls how a failed request is sent again. The zero value
+// sends it once.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt; zero or one sends it once
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// Statuses are the status codes that are retried, comma separated,
+	// as codes (503), ranges (500-504) or classes (5xx); empty means
+	// DefaultRetryStatuses
+	Statuses string `json:"statuses,omitempty"`
+	// NoNetworkErrors stops failed connections and timeouts being retried
+	NoNetworkErrors bool `json:"no_network_errors,omitempty"`
+	// BackoffMS is the delay before the first retry in milliseconds, doubled
+	// for each retry after it; zero means DefaultRetryBackoff
+	BackoffMS int `json:"backoff_ms,omitempty"`
+	// NonIdempotent also retries POST, PATCH and other methods that may not
+	// be safe to send twice
+	NonIdempotent bool `json:"non_idempotent,omitempty"`
+}
+
+// idempotentMethods are retried without NonIdempotent: sending them again
+// has the same effect as sending them once
+var idempotentMethods = map[string]bool{
+	"GET": true, "HEAD": true, "OPTIONS": true, "TRACE": true, "PUT": true, "DELETE": true,
+	"PROPFIND": true, "REPORT": true, "SEARCH": true,
+}
+
+// Attempts is the number of times the policy sends a request at most
+func (p RetryPolicy) Attempts() int {
+	return min(max(p.MaxAttempts, 1), MaxRetryAttempts)
+}
+
+// Backoff is the delay before the first retry
+func (p RetryPolicy) Backoff() time.Duration {
+	if p.BackoffMS > 0 {
+		return min(time.Duration(p.BackoffMS)*time.Millisecond, MaxRetryBackoff)
+	}
+	return DefaultRetryBackoff
+}
+
+// statusRange is an inclusive range of status codes
+type statusRange struct{ from, to int }
+
+// Validate checks the policy's limits and list of statuses
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 0 || p.MaxAttempts > MaxRetryAttempts {
+		return fmt.Errorf("max attempts must be between 0 (send once) and %d, not %d", MaxRetryAttempts, p.MaxAttempts)
+	}
+	if p.BackoffMS < 0 || p.BackoffMS > int(MaxRetryBackoff/time.Millisecond) {
+		return fmt.Errorf("backoff must be between 0 and %d ms, not %d", MaxRetryBackoff/time.Millisecond, p.BackoffMS)
+	}
+	_, err := parseRetryStatuses(p.Statuses)
+	return err
+}
+
+// parseRetryStatuses reads a list of statuses to retry
+func parseRetryStatuses(spec string) ([]statusRange, error) {
+	var ranges []statusRange
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		var r statusRange
+		var err error
+		switch from, to, isRange := strings.Cut(part, "-"); {
+		case len(part) == 3 && strings.HasSuffix(part, "xx"):
+			var class int
+			class, err = strconv.Atoi(part[:1])
+			r = statusRange{class * 100, class*100 + 99}
+		case isRange:
+			r.from, err = strconv.Atoi(from)
+			if err == nil {
+				r.to, err = strconv.Atoi(to)
+			}
+		default:
+			r.from, err = strconv.Atoi(part)
+			r.to = r.from
+		}
+		if err != nil || r.from < 100 || r.to > 599 || r.from > r.to {
+			return nil, fmt.Errorf("%q isn't a status code, range (500-504) or class (5xx)", part)
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// retries reports whether the policy retries a response with status
+func (p RetryPolicy) retries(status int) bool {
+	spec := p.Statuses
+	if spec == "" {
+		spec = DefaultRetryStatuses
+	}
+	ranges, _ := parseRetryStatuses(spec)
+	for _, r := range ranges {
+		if status >= r.from && status <= r.to {
+			return true
+		}
+	}
+	return false
+}
+
+// Attempt is one try at sending a request, as listed in the response's
+// timeline
+type Attempt struct {
+	StatusCode int
+	Err        error
+	// Start is when the attempt was sent, counted from the first attempt
+	Start    time.Duration
+	Duration time.Duration
+	// Wait is the delay before the next attempt, zero for the last one;
+	// RetryAfter is set when the server chose it
+	Wait       time.Duration
+	RetryAfter bool
+	// GaveUp says why a failed last attempt wasn't retried
+	GaveUp string
+}
+
+// next decides whether the policy sends another attempt at a request with
+// method after attempt n, and how long to wait first. retryable is false
+// for responses and errors that would fail the same way again.
+func (p RetryPolicy) next(n int, method string, resp Response, retryable bool) (Attempt, bool) {
+	attempt := Attempt{StatusCode: resp.StatusCode, Err: resp.Error, Duration: resp.ResponseTime}
+	switch {
+	case resp.Error != nil && (!retryable || p.NoNetworkErrors):
+		return attempt, false
+	case resp.Error == nil && (resp.Stream != nil || !p.retries(resp.StatusCode)):
+		return attempt, false
+	case !p.NonIdempotent && !idempotentMethods[method]:
+		if p.Attempts() > 1 {
+			attempt.GaveUp = method + " may not be safe to send twice, so it isn't retried"
+		}
+		return attempt, false
+	case n >= p.Attempts():
+		if p.Attempts() > 1 {
+			attempt.GaveUp = fmt.Sprintf("gave up after %d attempts", n)
+		}
+		return attempt, false
+	}
+
+	if wait, ok := parseRetryAfter(resp.Headers["Retry-After"], time.Now()); ok {
+		if wait > MaxRetryAfter {
+			attempt.GaveUp = fmt.Sprintf("Retry-After of %s is over the %s limit", wait.Round(time.Second), MaxRetryAfter)
+			return attempt, false
+		}
+		attempt.Wait, attempt.RetryAfter = wait, true
+		return attempt, true
+	}
+
+	// Exponential backoff with equal jitter: half the delay is fixed and
+	// half random, so clients retrying together spread out. The delay stops
+	// doubling at the cap rather than shifting past it and overflowing.
+	backoff := p.Backoff()
+	for i := 1; i < n && backoff < MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, MaxRetryBackoff)
+	attempt.Wait = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+	return attempt, true
+}
+
+// parseRetryAfter reads a Retry-After header, given in seconds or as an
+// HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
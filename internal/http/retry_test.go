@@ -0,0 +1,209 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyNext(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		minWait time.Duration
+		maxWait time.Duration
+	}{
+		{"default backoff", RetryPolicy{MaxAttempts: 3}, 250 * time.Millisecond, 500 * time.Millisecond},
+		{"many attempts", RetryPolicy{MaxAttempts: 100, BackoffMS: 500}, 0, MaxRetryBackoff},
+		{"huge backoff", RetryPolicy{MaxAttempts: 100, BackoffMS: 1 << 40}, 0, MaxRetryBackoff},
+		{"negative values", RetryPolicy{MaxAttempts: -5, BackoffMS: -1}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for n := 1; n <= 100; n++ {
+				attempt, retry := tt.policy.next(n, "GET", Response{StatusCode: 503}, true)
+				if retry != (n < tt.policy.Attempts()) {
+					t.Fatalf("attempt %d: retry = %v with %d attempts", n, retry, tt.policy.Attempts())
+				}
+				if !retry {
+					continue
+				}
+				if attempt.Wait < 0 || attempt.Wait > MaxRetryBackoff {
+					t.Fatalf("attempt %d: wait %s is outside 0 to %s", n, attempt.Wait, MaxRetryBackoff)
+				}
+				if n == 1 && (attempt.Wait < tt.minWait || attempt.Wait > tt.maxWait) {
+					t.Errorf("first wait %s is outside %s to %s", attempt.Wait, tt.minWait, tt.maxWait)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicyNextStops(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+	tests := []struct {
+		name      string
+		resp      Response
+		retryable bool
+		gaveUp    bool
+	}{
+		{"success", Response{StatusCode: 200}, true, false},
+		{"unlisted status", Response{StatusCode: 404}, true, false},
+		{"unretryable error", Response{Error: errors.New("bad URL")}, false, false},
+		{"long Retry-After", Response{StatusCode: 429, Headers: map[string]string{"Retry-After": "3600"}}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempt, retry := policy.next(1, "GET", tt.resp, tt.retryable)
+			if retry {
+				t.Fatal("retried")
+			}
+			if (attempt.GaveUp != "") != tt.gaveUp {
+				t.Errorf("GaveUp = %q", attempt.GaveUp)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyNextMethods(t *testing.T) {
+	tests := []struct {
+		method        string
+		nonIdempotent bool
+		retry         bool
+	}{
+		{"GET", false, true},
+		{"HEAD", false, true},
+		{"OPTIONS", false, true},
+		{"TRACE", false, true},
+		{"PUT", false, true},
+		{"DELETE", false, true},
+		{"PROPFIND", false, true},
+		{"POST", false, false},
+		{"PATCH", false, false},
+		{"MKCOL", false, false},
+		{"PURGE", false, false},
+		{"get", false, false},
+		{"POST", true, true},
+		{"PURGE", true, true},
+	}
+
+	for _, tt := range tests {
+		policy := RetryPolicy{MaxAttempts: 3, NonIdempotent: tt.nonIdempotent}
+		for _, resp := range []Response{{StatusCode: 503}, {Error: errors.New("connection reset")}} {
+			attempt, retry := policy.next(1, tt.method, resp, true)
+			if retry != tt.retry {
+				t.Errorf("%s (non-idempotent %v) after %v: retry = %v, want %v", tt.method, tt.nonIdempotent, attempt.Err, retry, tt.retry)
+			}
+			if !retry && attempt.GaveUp == "" {
+				t.Errorf("%s: no reason given for not retrying", tt.method)
+			}
+		}
+	}
+}
+
+func TestSendRequestContextCancelsWait(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(100*time.Millisecond, func() { cancel(ErrCancelled) })
+
+	start := time.Now()
+	resp := NewClient().SendRequestContext(ctx, Request{
+		Method: "GET",
+		URL:    server.URL,
+		Retry:  RetryPolicy{MaxAttempts: 3},
+	}, nil)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("cancelling took %s", elapsed)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable || len(resp.Attempts) != 1 {
+		t.Fatalf("got status %d after %d attempts", resp.StatusCode, len(resp.Attempts))
+	}
+	if last := resp.Attempts[0]; last.Wait != 0 || last.GaveUp == "" {
+		t.Errorf("last attempt = %+v, want no wait and a reason", last)
+	}
+}
+
+func TestSendRequestContextCancelsRequest(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(100*time.Millisecond, func() { cancel(ErrCancelled) })
+
+	resp := NewClient().SendRequestContext(ctx, Request{
+		Method: "GET",
+		URL:    server.URL,
+		Retry:  RetryPolicy{MaxAttempts: 3},
+	}, nil)
+	if !errors.Is(resp.Error, ErrCancelled) {
+		t.Errorf("error = %v, want %v", resp.Error, ErrCancelled)
+	}
+	if len(resp.Attempts) != 1 {
+		t.Errorf("%d attempts, want 1", len(resp.Attempts))
+	}
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		ok     bool
+	}{
+		{"zero", RetryPolicy{}, true},
+		{"limits", RetryPolicy{MaxAttempts: MaxRetryAttempts, BackoffMS: 30000}, true},
+		{"statuses", RetryPolicy{Statuses: "429, 500-504,5xx"}, true},
+		{"too many attempts", RetryPolicy{MaxAttempts: 100}, false},
+		{"negative attempts", RetryPolicy{MaxAttempts: -1}, false},
+		{"backoff too long", RetryPolicy{BackoffMS: 30001}, false},
+		{"negative backoff", RetryPolicy{BackoffMS: -1}, false},
+		{"bad status", RetryPolicy{Statuses: "abc"}, false},
+		{"status out of range", RetryPolicy{Statuses: "600"}, false},
+		{"reversed range", RetryPolicy{Statuses: "504-500"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err == nil) != tt.ok {
+				t.Errorf("Validate() = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		wait  time.Duration
+		ok    bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{"0", 0, true},
+		{"Mon, 01 Jan 2024 00:00:30 GMT", 30 * time.Second, true},
+		{"Sun, 31 Dec 2023 00:00:00 GMT", 0, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		wait, ok := parseRetryAfter(tt.value, now)
+		if wait != tt.wait || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %v, want %s, %v", tt.value, wait, ok, tt.wait, tt.ok)
+		}
+	}
+}
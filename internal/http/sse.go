@@ -0,0 +1,181 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is a single dispatched server-sent event
+type Event struct {
+	// ID is the last event ID in effect when the event was dispatched, which
+	// is what a client sends back as Last-Event-ID when reconnecting
+	ID   string
+	Type string
+	Data string
+	// Retry is the reconnection delay most recently requested by the server
+	Retry    time.Duration
+	Received time.Time
+}
+
+// IsEventStream reports whether contentType is text/event-stream
+func IsEventStream(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/event-stream"
+}
+
+// ReadEvents parses an event stream from r as described by the HTML
+// specification, calling onEvent for each dispatched event. It stops when r
+// is exhausted or onEvent returns false.
+func ReadEvents(r io.Reader, onEvent func(Event) bool) error {
+	br := bufio.NewReader(r)
+
+	var (
+		data      strings.Builder
+		eventType string
+		lastID    string
+		retry     time.Duration
+		first     = true
+	)
+
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && line == "" {
+			if errors.Is(err, io.EOF) {
+				// An incomplete event at the end of the stream is discarded
+				return nil
+			}
+			return err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if first {
+			line = strings.TrimPrefix(line, "\ufeff")
+			first = false
+		}
+
+		switch {
+		case line == "":
+			if data.Len() > 0 {
+				event := Event{
+					ID:       lastID,
+					Type:     eventType,
+					Data:     strings.TrimSuffix(data.String(), "\n"),
+					Retry:    retry,
+					Received: time.Now(),
+				}
+				if event.Type == "" {
+					event.Type = "message"
+				}
+				if !onEvent(event) {
+					return nil
+				}
+			}
+			data.Reset()
+			eventType = ""
+
+		case strings.HasPrefix(line, ":"):
+			// Comment, often used as a keep-alive
+
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+
+			switch field {
+			case "data":
+				data.WriteString(value)
+				data.WriteByte('\n')
+			case "event":
+				eventType = value
+			case "id":
+				if !strings.ContainsRune(value, 0) {
+					lastID = value
+				}
+			case "retry":
+				if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+					retry = time.Duration(ms) * time.Millisecond
+				}
+			}
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// EventStream delivers the events of an open text/event-stream response
+// until the server closes it or Close is called
+type EventStream struct {
+	// Events is closed when the stream ends
+	Events <-chan Event
+
+	cancel context.CancelFunc
+
+	mu          sync.Mutex
+	err         error
+	closed      bool
+	lastEventID string
+}
+
+func newEventStream(ctx context.Context, cancel context.CancelFunc, body io.ReadCloser) *EventStream {
+	events := make(chan Event, 64)
+	s := &EventStream{Events: events, cancel: cancel}
+
+	go func() {
+		defer close(events)
+		defer body.Close()
+
+		err := ReadEvents(body, func(event Event) bool {
+			s.mu.Lock()
+			s.lastEventID = event.ID
+			s.mu.Unlock()
+
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+
+		s.mu.Lock()
+		if !s.closed {
+			s.err = err
+		}
+		s.mu.Unlock()
+	}()
+
+	return s
+}
+
+// Close stops the stream and releases the connection
+func (s *EventStream) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.cancel()
+}
+
+// Err returns the error that ended the stream, if any. A stream closed by
+// the server or by Close has no error.
+func (s *EventStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// LastEventID returns the ID to send as Last-Event-ID when reconnecting
+func (s *EventStream) LastEventID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastEventID
+}
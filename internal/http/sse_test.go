@@ -0,0 +1,156 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   []Event
+	}{
+		{
+			name:   "default type",
+			stream: "data: hello\n\n",
+			want:   []Event{{Type: "message", Data: "hello"}},
+		},
+		{
+			name:   "multi-line data",
+			stream: "data: one\ndata:two\ndata\n\n",
+			want:   []Event{{Type: "message", Data: "one\ntwo\n"}},
+		},
+		{
+			name:   "named event with id",
+			stream: "event: update\nid: 7\ndata: {}\n\n",
+			want:   []Event{{ID: "7", Type: "update", Data: "{}"}},
+		},
+		{
+			name:   "id and retry carry over, type does not",
+			stream: "event: a\nid: 1\nretry: 1500\ndata: x\n\ndata: y\n\n",
+			want: []Event{
+				{ID: "1", Type: "a", Data: "x", Retry: 1500 * time.Millisecond},
+				{ID: "1", Type: "message", Data: "y", Retry: 1500 * time.Millisecond},
+			},
+		},
+		{
+			name:   "invalid retry and id with NUL are ignored",
+			stream: "id: 1\ndata: x\n\nretry: soon\nid: a\x00b\ndata: y\n\n",
+			want: []Event{
+				{ID: "1", Type: "message", Data: "x"},
+				{ID: "1", Type: "message", Data: "y"},
+			},
+		},
+		{
+			name:   "CRLF line endings and a BOM",
+			stream: "\ufeffdata: x\r\n\r\n",
+			want:   []Event{{Type: "message", Data: "x"}},
+		},
+		{
+			name:   "comments and unknown fields",
+			stream: ": keep-alive\nfoo: bar\ndata: x\n\n",
+			want:   []Event{{Type: "message", Data: "x"}},
+		},
+		{
+			name:   "only the first space is stripped",
+			stream: "data:  padded\n\n",
+			want:   []Event{{Type: "message", Data: " padded"}},
+		},
+		{
+			name:   "events without data are not dispatched",
+			stream: "event: ping\n\ndata: x\n\n",
+			want:   []Event{{Type: "message", Data: "x"}},
+		},
+		{
+			name:   "incomplete event at the end is dropped",
+			stream: "data: x\n\ndata: partial",
+			want:   []Event{{Type: "message", Data: "x"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Event
+			err := ReadEvents(strings.NewReader(tt.stream), func(event Event) bool {
+				event.Received = time.Time{}
+				got = append(got, event)
+				return true
+			})
+			if err != nil {
+				t.Fatalf("ReadEvents: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadEvents(%q) =\n%+v\nwant\n%+v", tt.stream, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadEventsStops(t *testing.T) {
+	count := 0
+	err := ReadEvents(strings.NewReader("data: 1\n\ndata: 2\n\n"), func(Event) bool {
+		count++
+		return false
+	})
+	if err != nil || count != 1 {
+		t.Errorf("ReadEvents after onEvent returned false: %d events, %v; want 1 event, nil", count, err)
+	}
+}
+
+type failingReader struct{ err error }
+
+func (r failingReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestReadEventsReadError(t *testing.T) {
+	broken := errors.New("connection reset")
+	err := ReadEvents(failingReader{broken}, func(Event) bool { return true })
+	if !errors.Is(err, broken) {
+		t.Errorf("ReadEvents on a failing reader = %v, want %v", err, broken)
+	}
+}
+
+func TestIsEventStream(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"text/event-stream", true},
+		{"text/event-stream; charset=utf-8", true},
+		{"Text/Event-Stream", true},
+		{"text/plain", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsEventStream(tt.contentType); got != tt.want {
+			t.Errorf("IsEventStream(%q) = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	body := io.NopCloser(strings.NewReader("id: 1\ndata: a\n\nid: 2\ndata: b\n\n"))
+	stream := newEventStream(ctx, cancel, body)
+
+	var data []string
+	for event := range stream.Events {
+		data = append(data, event.Data)
+	}
+
+	if !reflect.DeepEqual(data, []string{"a", "b"}) {
+		t.Errorf("Events delivered %q, want [a b]", data)
+	}
+	if id := stream.LastEventID(); id != "2" {
+		t.Errorf("LastEventID = %q, want 2", id)
+	}
+	if err := stream.Err(); err != nil {
+		t.Errorf("Err after the server closed the stream = %v, want nil", err)
+	}
+}
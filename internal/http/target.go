@@ -0,0 +1,217 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Target overrides where a request connects, leaving its URL and Host
+// header as they are
+type Target struct {
+	// UnixSocket is the path of a socket to send the request over
+	UnixSocket string `json:"unix_socket,omitempty"`
+	// Resolve pins host names to addresses, each entry as curl's
+	// --resolve: host:port:addr[,addr...]
+	Resolve []string `json:"resolve,omitempty"`
+	// ConnectTo sends connections for one host and port to another, each
+	// entry as curl's --connect-to: host1:port1:host2:port2, where an
+	// empty field matches anything or keeps what was asked for
+	ConnectTo []string `json:"connect_to,omitempty"`
+}
+
+// IsZero reports whether t leaves connections alone
+func (t Target) IsZero() bool {
+	return t.UnixSocket == "" && len(t.Resolve) == 0 && len(t.ConnectTo) == 0
+}
+
+// key identifies t among a client's transports
+func (t Target) key() string {
+	return t.UnixSocket + "\x00" + strings.Join(t.Resolve, "\x00") + "\x00\x00" + strings.Join(t.ConnectTo, "\x00")
+}
+
+type resolveRule struct {
+	host  string
+	port  string
+	addrs []string
+}
+
+type connectRule struct {
+	fromHost, fromPort string
+	toHost, toPort     string
+}
+
+// parseResolve parses a --resolve entry
+func parseResolve(entry string) (resolveRule, error) {
+	fields := splitHostFields(entry, 3)
+	if len(fields) != 3 || fields[0] == "" || fields[1] == "" || fields[2] == "" {
+		return resolveRule{}, fmt.Errorf("resolve %q: want host:port:addr", entry)
+	}
+
+	rule := resolveRule{host: strings.ToLower(fields[0]), port: fields[1]}
+	for _, addr := range strings.Split(fields[2], ",") {
+		addr = strings.Trim(strings.TrimSpace(addr), "[]")
+		if net.ParseIP(addr) == nil {
+			return resolveRule{}, fmt.Errorf("resolve %q: %q isn't an IP address", entry, addr)
+		}
+		rule.addrs = append(rule.addrs, addr)
+	}
+	return rule, nil
+}
+
+// parseConnectTo parses a --connect-to entry
+func parseConnectTo(entry string) (connectRule, error) {
+	fields := splitHostFields(entry, 4)
+	if len(fields) != 4 {
+		return connectRule{}, fmt.Errorf("connect-to %q: want host1:port1:host2:port2", entry)
+	}
+	if fields[2] == "" && fields[3] == "" {
+		return connectRule{}, fmt.Errorf("connect-to %q: no host or port to connect to", entry)
+	}
+	return connectRule{
+		fromHost: strings.ToLower(fields[0]), fromPort: fields[1],
+		toHost: fields[2], toPort: fields[3],
+	}, nil
+}
+
+// splitHostFields splits s on colons outside of [brackets], so IPv6
+// addresses can be written as in URLs
+func splitHostFields(s string, n int) []string {
+	var fields []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch {
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == ':' && depth == 0 && len(fields) < n-1:
+			fields = append(fields, strings.Trim(s[start:i], "[]"))
+			start = i + 1
+		}
+	}
+	return append(fields, strings.Trim(s[start:], "[]"))
+}
+
+// Validate checks every rule in t
+func (t Target) Validate() error {
+	_, _, err := t.rules()
+	return err
+}
+
+func (t Target) rules() ([]resolveRule, []connectRule, error) {
+	var resolves []resolveRule
+	for _, entry := range t.Resolve {
+		rule, err := parseResolve(entry)
+		if err != nil {
+			return nil, nil, err
+		}
+		resolves = append(resolves, rule)
+	}
+
+	var connects []connectRule
+	for _, entry := range t.ConnectTo {
+		rule, err := parseConnectTo(entry)
+		if err != nil {
+			return nil, nil, err
+		}
+		connects = append(connects, rule)
+	}
+	return resolves, connects, nil
+}
+
+// addresses lists where a connection to addr (host:port) goes instead:
+// the unix socket, the addresses --connect-to and --resolve lead to, or
+// just addr when no rule applies
+func (t Target) addresses(addr string) ([]string, error) {
+	if t.UnixSocket != "" {
+		return []string{t.UnixSocket}, nil
+	}
+	resolves, connects, err := t.rules()
+	if err != nil {
+		return nil, err
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range connects {
+		if (rule.fromHost == "" || rule.fromHost == strings.ToLower(host)) && (rule.fromPort == "" || rule.fromPort == port) {
+			if rule.toHost != "" {
+				host = rule.toHost
+			}
+			if rule.toPort != "" {
+				port = rule.toPort
+			}
+			break
+		}
+	}
+	for _, rule := range resolves {
+		if rule.host == strings.ToLower(host) && rule.port == port {
+			addrs := make([]string, len(rule.addrs))
+			for i, ip := range rule.addrs {
+				addrs[i] = net.JoinHostPort(ip, port)
+			}
+			return addrs, nil
+		}
+	}
+	return []string{net.JoinHostPort(host, port)}, nil
+}
+
+// ConnectAddr describes where a request to rawURL would connect, and
+// whether t changed that
+func (t Target) ConnectAddr(rawURL string) (string, bool, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", false, err
+	}
+	addr := canonicalAddr(u)
+	if t.UnixSocket != "" {
+		return "unix:" + t.UnixSocket, true, nil
+	}
+	addrs, err := t.addresses(addr)
+	if err != nil {
+		return "", false, err
+	}
+	target := strings.Join(addrs, ", ")
+	return target, target != addr, nil
+}
+
+// canonicalAddr is the host:port a URL connects to
+func canonicalAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" || u.Scheme == "wss" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// dialContext dials the addresses t leads to in turn, returning the first
+// connection made
+func (t Target) dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if t.UnixSocket != "" {
+			return dialer.DialContext(ctx, "unix", t.UnixSocket)
+		}
+		addrs, err := t.addresses(addr)
+		if err != nil {
+			return nil, err
+		}
+		var errs []error
+		for _, target := range addrs {
+			conn, err := dialer.DialContext(ctx, network, target)
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, errors.Join(errs...)
+	}
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTargetValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		target Target
+		err    string
+	}{
+		{"empty", Target{}, ""},
+		{"resolve", Target{Resolve: []string{"example.com:443:127.0.0.1"}}, ""},
+		{"resolve several addresses", Target{Resolve: []string{"example.com:443:127.0.0.1, [::1]"}}, ""},
+		{"resolve IPv6 host", Target{Resolve: []string{"[::1]:80:127.0.0.1"}}, ""},
+		{"connect-to", Target{ConnectTo: []string{"example.com:443:backend:8443"}}, ""},
+		{"connect-to any host", Target{ConnectTo: []string{"::localhost:"}}, ""},
+		{"resolve missing address", Target{Resolve: []string{"example.com:443"}}, "want host:port:addr"},
+		{"resolve empty port", Target{Resolve: []string{"example.com::127.0.0.1"}}, "want host:port:addr"},
+		{"resolve host name", Target{Resolve: []string{"example.com:443:backend"}}, "isn't an IP address"},
+		{"connect-to too short", Target{ConnectTo: []string{"example.com:443:backend"}}, "want host1:port1:host2:port2"},
+		{"connect-to nowhere", Target{ConnectTo: []string{"example.com:443::"}}, "no host or port"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.target.Validate()
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Validate() = %v, want an error mentioning %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestTargetConnectAddr(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  Target
+		url     string
+		want    string
+		changed bool
+	}{
+		{"no rules", Target{}, "https://example.com/x", "example.com:443", false},
+		{"default http port", Target{}, "http://example.com", "example.com:80", false},
+		{"websocket port", Target{}, "wss://example.com", "example.com:443", false},
+		{"unix socket", Target{UnixSocket: "/run/app.sock"}, "http://example.com", "unix:/run/app.sock", true},
+		{
+			name:    "resolve",
+			target:  Target{Resolve: []string{"Example.com:443:10.0.0.1,10.0.0.2"}},
+			url:     "https://example.com",
+			want:    "10.0.0.1:443, 10.0.0.2:443",
+			changed: true,
+		},
+		{
+			name:   "resolve for another port",
+			target: Target{Resolve: []string{"example.com:80:10.0.0.1"}},
+			url:    "https://example.com",
+			want:   "example.com:443",
+		},
+		{
+			name:    "resolve IPv6 address",
+			target:  Target{Resolve: []string{"example.com:443:[::1]"}},
+			url:     "https://example.com",
+			want:    "[::1]:443",
+			changed: true,
+		},
+		{
+			name:    "connect-to",
+			target:  Target{ConnectTo: []string{"example.com:443:backend:8443"}},
+			url:     "https://example.com/",
+			want:    "backend:8443",
+			changed: true,
+		},
+		{
+			name:    "connect-to keeps the port",
+			target:  Target{ConnectTo: []string{"example.com::backend:"}},
+			url:     "http://example.com:8080",
+			want:    "backend:8080",
+			changed: true,
+		},
+		{
+			name:    "connect-to then resolve",
+			target:  Target{ConnectTo: []string{"example.com:443:backend:443"}, Resolve: []string{"backend:443:10.0.0.9"}},
+			url:     "https://example.com",
+			want:    "10.0.0.9:443",
+			changed: true,
+		},
+		{
+			name:   "connect-to for another host",
+			target: Target{ConnectTo: []string{"other.com:443:backend:443"}},
+			url:    "https://example.com",
+			want:   "example.com:443",
+		},
+		{"no host", Target{}, "/relative", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, err := tt.target.ConnectAddr(tt.url)
+			if err != nil {
+				t.Fatalf("ConnectAddr(%q): %v", tt.url, err)
+			}
+			if got != tt.want || changed != tt.changed {
+				t.Errorf("ConnectAddr(%q) = %q, %v, want %q, %v", tt.url, got, changed, tt.want, tt.changed)
+			}
+		})
+	}
+}
+
+func TestTargetConnectAddrInvalid(t *testing.T) {
+	target := Target{Resolve: []string{"example.com"}}
+	if _, _, err := target.ConnectAddr("https://example.com"); err == nil {
+		t.Error("ConnectAddr with an invalid rule succeeded, want an error")
+	}
+}
+
+func TestSendRequestTarget(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Host)
+	})
+
+	socket := filepath.Join(t.TempDir(), "app.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	unixServer := httptest.NewUnstartedServer(handler)
+	unixServer.Listener = listener
+	unixServer.Start()
+	defer unixServer.Close()
+
+	tcpServer := httptest.NewServer(handler)
+	defer tcpServer.Close()
+	tcpAddr := tcpServer.Listener.Addr().String()
+	_, port, _ := net.SplitHostPort(tcpAddr)
+
+	tests := []struct {
+		name   string
+		target Target
+		host   string
+	}{
+		{"unix socket", Target{UnixSocket: socket}, "api.example.test"},
+		{"connect-to", Target{ConnectTo: []string{"api.example.test:80:" + tcpAddr}}, "api.example.test"},
+		{"resolve", Target{Resolve: []string{"api.example.test:" + port + ":127.0.0.1"}}, "api.example.test:" + port},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := NewClient().SendRequest(Request{Method: "GET", URL: "http://" + tt.host + "/", Proxy: ProxyConfig{Direct: true}, Target: tt.target})
+			if resp.Error != nil {
+				t.Fatalf("SendRequest: %v", resp.Error)
+			}
+			if resp.Body != tt.host {
+				t.Errorf("server saw Host %q, want %q", resp.Body, tt.host)
+			}
+		})
+	}
+}
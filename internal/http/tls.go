@@ -0,0 +1,149 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// TLSVersions are the minimum TLS versions a TLSConfig can ask for
+var TLSVersions = []string{"1.0", "1.1", "1.2", "1.3"}
+
+var tlsVersionIDs = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSConfig holds the TLS settings for HTTPS requests. The zero value
+// trusts the system roots and uses Go's defaults.
+type TLSConfig struct {
+	// CAFile is a PEM bundle of roots trusted alongside the system ones
+	CAFile string `json:"ca_file,omitempty"`
+	// CertFile and KeyFile are a PEM client certificate and its key.
+	// KeyFile may be left empty when CertFile holds both.
+	CertFile string `json:"cert_file,omitempty"`
+	KeyFile  string `json:"key_file,omitempty"`
+	// PKCS12File is a client certificate and key in a .p12 or .pfx bundle,
+	// used instead of CertFile
+	PKCS12File     string `json:"pkcs12_file,omitempty"`
+	PKCS12Password string `json:"pkcs12_password,omitempty"`
+	// ServerName is sent as SNI and checked against the server's
+	// certificate instead of the URL's host
+	ServerName string `json:"server_name,omitempty"`
+	// MinVersion is one of TLSVersions; empty means Go's default
+	MinVersion string `json:"min_version,omitempty"`
+	// Insecure skips verifying the server's certificate chain and name
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// IsZero reports whether c leaves every setting at its default
+func (c TLSConfig) IsZero() bool {
+	return c == TLSConfig{}
+}
+
+// Merge returns c with the settings override sets replacing its own.
+// Verification is skipped if either skips it.
+func (c TLSConfig) Merge(override TLSConfig) TLSConfig {
+	merged := c
+	if override.CAFile != "" {
+		merged.CAFile = override.CAFile
+	}
+	if override.CertFile != "" || override.PKCS12File != "" {
+		merged.CertFile, merged.KeyFile = override.CertFile, override.KeyFile
+		merged.PKCS12File, merged.PKCS12Password = override.PKCS12File, override.PKCS12Password
+	}
+	if override.ServerName != "" {
+		merged.ServerName = override.ServerName
+	}
+	if override.MinVersion != "" {
+		merged.MinVersion = override.MinVersion
+	}
+	merged.Insecure = c.Insecure || override.Insecure
+	return merged
+}
+
+// Build loads the files c names into a tls.Config
+func (c TLSConfig) Build() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.Insecure,
+	}
+
+	if c.MinVersion != "" {
+		version, ok := tlsVersionIDs[c.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unknown TLS version %q", c.MinVersion)
+		}
+		config.MinVersion = version
+	}
+
+	if c.CAFile != "" {
+		pool, err := loadCAFile(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+
+	switch {
+	case c.PKCS12File != "":
+		cert, err := loadPKCS12(c.PKCS12File, c.PKCS12Password)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	case c.CertFile != "":
+		keyFile := c.KeyFile
+		if keyFile == "" {
+			keyFile = c.CertFile
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// loadCAFile adds a PEM bundle to the system roots
+func loadCAFile(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("CA bundle: no PEM certificates in %s", path)
+	}
+	return pool, nil
+}
+
+// loadPKCS12 reads a client certificate, its key and any intermediates
+// from a PKCS#12 bundle
+func loadPKCS12(path, password string) (tls.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("PKCS#12 bundle: %w", err)
+	}
+	key, leaf, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("PKCS#12 bundle: %w", err)
+	}
+
+	cert := tls.Certificate{PrivateKey: key, Leaf: leaf}
+	cert.Certificate = append(cert.Certificate, leaf.Raw)
+	for _, intermediate := range chain {
+		cert.Certificate = append(cert.Certificate, intermediate.Raw)
+	}
+	return cert, nil
+}
@@ -0,0 +1,240 @@
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// testCert is a certificate and key made for a test
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// issue creates a certificate for name signed by parent, or a self-signed
+// CA when parent is nil
+func issue(t *testing.T, name string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{name},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+// writePEM writes the certificate, and the key unless it's left out, to a
+// PEM file in dir
+func (c *testCert) writePEM(t *testing.T, dir, name string, withKey bool) string {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw})
+	if withKey {
+		der, err := x509.MarshalECPrivateKey(c.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})...)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// mutualTLSServer serves HTTPS with a certificate from ca, answering with
+// the common name of the client certificate when one is presented
+func mutualTLSServer(t *testing.T, ca *testCert) *httptest.Server {
+	t.Helper()
+	serverCert := issue(t, "quest.test", ca, x509.ExtKeyUsageServerAuth)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.Write([]byte("anonymous"))
+			return
+		}
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	// Failed handshakes are expected; keep them out of the test output
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.cert.Raw}, PrivateKey: serverCert.key}},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSendRequestTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := issue(t, "Quest Test CA", nil, x509.ExtKeyUsageAny)
+	client := issue(t, "quest-client", ca, x509.ExtKeyUsageClientAuth)
+	server := mutualTLSServer(t, ca)
+
+	caFile := ca.writePEM(t, dir, "ca.pem", false)
+	combined := client.writePEM(t, dir, "client.pem", true)
+	certOnly := client.writePEM(t, dir, "client.crt", false)
+	keyDER, err := x509.MarshalECPrivateKey(client.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, "client.key")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p12, err := pkcs12.Encode(rand.Reader, client.key, client.cert, []*x509.Certificate{ca.cert}, "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p12File := filepath.Join(dir, "client.p12")
+	if err := os.WriteFile(p12File, p12, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	url := "https://quest.test:" + port
+	target := Target{Resolve: []string{"quest.test:" + port + ":127.0.0.1"}}
+
+	tests := []struct {
+		name string
+		tls  TLSConfig
+		body string
+		err  string
+	}{
+		{"untrusted server", TLSConfig{}, "", "certificate"},
+		{"insecure", TLSConfig{Insecure: true}, "anonymous", ""},
+		{"trusted CA", TLSConfig{CAFile: caFile}, "anonymous", ""},
+		{"client certificate and key in one file", TLSConfig{CAFile: caFile, CertFile: combined}, "quest-client", ""},
+		{"separate key file", TLSConfig{CAFile: caFile, CertFile: certOnly, KeyFile: keyFile}, "quest-client", ""},
+		{"PKCS#12 bundle", TLSConfig{CAFile: caFile, PKCS12File: p12File, PKCS12Password: "s3cret"}, "quest-client", ""},
+		{"wrong PKCS#12 password", TLSConfig{CAFile: caFile, PKCS12File: p12File, PKCS12Password: "nope"}, "", "PKCS#12 bundle"},
+		{"server name mismatch", TLSConfig{CAFile: caFile, ServerName: "other.test"}, "", "other.test"},
+		{"minimum version", TLSConfig{CAFile: caFile, MinVersion: "1.3"}, "anonymous", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := NewClient().SendRequest(Request{Method: "GET", URL: url, TLS: tt.tls, Target: target})
+			if tt.err != "" {
+				if resp.Error == nil || !strings.Contains(resp.Error.Error(), tt.err) {
+					t.Fatalf("SendRequest error = %v, want one mentioning %q", resp.Error, tt.err)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("SendRequest: %v", resp.Error)
+			}
+			if resp.Body != tt.body {
+				t.Errorf("server saw %q, want %q", resp.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestTLSConfigBuildErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		config TLSConfig
+		err    string
+	}{
+		{"unknown version", TLSConfig{MinVersion: "1.4"}, `unknown TLS version "1.4"`},
+		{"missing CA bundle", TLSConfig{CAFile: filepath.Join(dir, "missing.pem")}, "CA bundle"},
+		{"CA bundle without certificates", TLSConfig{CAFile: notPEM}, "no PEM certificates"},
+		{"missing client certificate", TLSConfig{CertFile: filepath.Join(dir, "missing.pem")}, "client certificate"},
+		{"missing PKCS#12 bundle", TLSConfig{PKCS12File: filepath.Join(dir, "missing.p12")}, "PKCS#12 bundle"},
+	}
+
+	for _, tt := range tests {
+		_, err := tt.config.Build()
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: Build error = %v, want one mentioning %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestTLSConfigMerge(t *testing.T) {
+	base := TLSConfig{CAFile: "ca.pem", CertFile: "base.pem", KeyFile: "base.key", MinVersion: "1.2"}
+
+	tests := []struct {
+		name     string
+		base     TLSConfig
+		override TLSConfig
+		want     TLSConfig
+	}{
+		{"nothing", base, TLSConfig{}, base},
+		{
+			name:     "PKCS#12 replaces the PEM pair",
+			base:     base,
+			override: TLSConfig{PKCS12File: "client.p12", PKCS12Password: "pw"},
+			want:     TLSConfig{CAFile: "ca.pem", PKCS12File: "client.p12", PKCS12Password: "pw", MinVersion: "1.2"},
+		},
+		{
+			name:     "settings override",
+			base:     base,
+			override: TLSConfig{CAFile: "other.pem", ServerName: "api.test", MinVersion: "1.3"},
+			want:     TLSConfig{CAFile: "other.pem", CertFile: "base.pem", KeyFile: "base.key", ServerName: "api.test", MinVersion: "1.3"},
+		},
+		{"insecure from the base", TLSConfig{Insecure: true}, TLSConfig{CAFile: "ca.pem"}, TLSConfig{CAFile: "ca.pem", Insecure: true}},
+		{"insecure from the override", TLSConfig{}, TLSConfig{Insecure: true}, TLSConfig{Insecure: true}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.base.Merge(tt.override); got != tt.want {
+			t.Errorf("%s: Merge = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
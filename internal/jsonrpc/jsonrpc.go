@@ -0,0 +1,187 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is the protocol version sent in every envelope
+const Version = "2.0"
+
+// Call is a method invocation to send
+type Call struct {
+	Method string
+	// Params is a JSON array or object, or empty to omit params
+	Params string
+}
+
+// Request is the envelope of a single call
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int             `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Validate checks that the call can be sent: it needs a method name, and
+// params must be structured (an array or an object) if given
+func (c Call) Validate() error {
+	if strings.TrimSpace(c.Method) == "" {
+		return errors.New("method name is required")
+	}
+
+	params := strings.TrimSpace(c.Params)
+	if params == "" {
+		return nil
+	}
+	if !json.Valid([]byte(params)) {
+		return fmt.Errorf("params of %s are not valid JSON", c.Method)
+	}
+	if params[0] != '[' && params[0] != '{' {
+		return fmt.Errorf("params of %s must be an array or an object", c.Method)
+	}
+	return nil
+}
+
+// Envelope builds the request body for calls, numbering them from firstID.
+// A single call is sent as an object and several as a batch array.
+func Envelope(calls []Call, firstID int) ([]byte, error) {
+	if len(calls) == 0 {
+		return nil, errors.New("no calls to send")
+	}
+
+	requests := make([]Request, len(calls))
+	for i, call := range calls {
+		if err := call.Validate(); err != nil {
+			return nil, err
+		}
+		requests[i] = Request{JSONRPC: Version, ID: firstID + i, Method: strings.TrimSpace(call.Method)}
+
+		if params := strings.TrimSpace(call.Params); params != "" {
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, []byte(params)); err != nil {
+				return nil, err
+			}
+			requests[i].Params = compact.Bytes()
+		}
+	}
+
+	if len(requests) == 1 {
+		return json.MarshalIndent(requests[0], "", "  ")
+	}
+	return json.MarshalIndent(requests, "", "  ")
+}
+
+// ParseEnvelope reads a request body back into its calls
+func ParseEnvelope(body string) ([]Call, error) {
+	body = strings.TrimSpace(body)
+
+	var requests []Request
+	if strings.HasPrefix(body, "[") {
+		if err := json.Unmarshal([]byte(body), &requests); err != nil {
+			return nil, err
+		}
+	} else {
+		var request Request
+		if err := json.Unmarshal([]byte(body), &request); err != nil {
+			return nil, err
+		}
+		requests = []Request{request}
+	}
+
+	calls := make([]Call, 0, len(requests))
+	for _, request := range requests {
+		if request.JSONRPC != Version || request.Method == "" {
+			return nil, errors.New("body is not a JSON-RPC 2.0 request")
+		}
+		call := Call{Method: request.Method}
+		if len(request.Params) > 0 {
+			var indented bytes.Buffer
+			if err := json.Indent(&indented, request.Params, "", "  "); err == nil {
+				call.Params = indented.String()
+			}
+		}
+		calls = append(calls, call)
+	}
+	return calls, nil
+}
+
+// Response is a single response object: either Result or Error is set
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// IDString is the response id as written, or "null" for responses to
+// requests the server couldn't read an id from
+func (r Response) IDString() string {
+	id := strings.TrimSpace(string(r.ID))
+	if id == "" {
+		return "null"
+	}
+	if unquoted, err := strconv.Unquote(id); err == nil {
+		return unquoted
+	}
+	return id
+}
+
+// Error is a response error object
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// CodeName describes the predefined error codes
+func CodeName(code int) string {
+	switch {
+	case code == -32700:
+		return "Parse error"
+	case code == -32600:
+		return "Invalid Request"
+	case code == -32601:
+		return "Method not found"
+	case code == -32602:
+		return "Invalid params"
+	case code == -32603:
+		return "Internal error"
+	case code <= -32000 && code >= -32099:
+		return "Server error"
+	}
+	return "Application error"
+}
+
+// ParseResponse reads a single or batch response. It reports false unless
+// every object is a JSON-RPC 2.0 response.
+func ParseResponse(body string) ([]Response, bool) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, false
+	}
+
+	var responses []Response
+	if strings.HasPrefix(body, "[") {
+		if err := json.Unmarshal([]byte(body), &responses); err != nil || len(responses) == 0 {
+			return nil, false
+		}
+	} else {
+		var response Response
+		if err := json.Unmarshal([]byte(body), &response); err != nil {
+			return nil, false
+		}
+		responses = []Response{response}
+	}
+
+	for _, response := range responses {
+		if response.JSONRPC != Version || (response.Result == nil && response.Error == nil) {
+			return nil, false
+		}
+	}
+	return responses, true
+}
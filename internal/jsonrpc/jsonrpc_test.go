@@ -0,0 +1,148 @@
+package jsonrpc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCallValidate(t *testing.T) {
+	tests := []struct {
+		call Call
+		err  string
+	}{
+		{Call{Method: "sum", Params: "[1, 2]"}, ""},
+		{Call{Method: "get", Params: `{"id": 1}`}, ""},
+		{Call{Method: "ping"}, ""},
+		{Call{Method: "  "}, "method name is required"},
+		{Call{Method: "sum", Params: "[1,"}, "not valid JSON"},
+		{Call{Method: "sum", Params: "42"}, "must be an array or an object"},
+	}
+
+	for _, tt := range tests {
+		err := tt.call.Validate()
+		if tt.err == "" {
+			if err != nil {
+				t.Errorf("Validate(%+v) = %v, want nil", tt.call, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Validate(%+v) = %v, want an error mentioning %q", tt.call, err, tt.err)
+		}
+	}
+}
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		calls []Call
+		want  string
+	}{
+		{
+			name:  "single call",
+			calls: []Call{{Method: " sum ", Params: "[1,\n 2]"}},
+			want:  "{\n  \"jsonrpc\": \"2.0\",\n  \"id\": 7,\n  \"method\": \"sum\",\n  \"params\": [\n    1,\n    2\n  ]\n}",
+		},
+		{
+			name:  "batch",
+			calls: []Call{{Method: "ping"}, {Method: "get", Params: `{"id":1}`}},
+			want: "[\n  {\n    \"jsonrpc\": \"2.0\",\n    \"id\": 7,\n    \"method\": \"ping\"\n  },\n" +
+				"  {\n    \"jsonrpc\": \"2.0\",\n    \"id\": 8,\n    \"method\": \"get\",\n    \"params\": {\n      \"id\": 1\n    }\n  }\n]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := Envelope(tt.calls, 7)
+			if err != nil {
+				t.Fatalf("Envelope: %v", err)
+			}
+			if string(body) != tt.want {
+				t.Errorf("Envelope =\n%s\nwant\n%s", body, tt.want)
+			}
+
+			calls, err := ParseEnvelope(string(body))
+			if err != nil {
+				t.Fatalf("ParseEnvelope: %v", err)
+			}
+			for i := range calls {
+				if calls[i].Method != strings.TrimSpace(tt.calls[i].Method) {
+					t.Errorf("call %d method = %q, want %q", i, calls[i].Method, tt.calls[i].Method)
+				}
+				if (calls[i].Params == "") != (tt.calls[i].Params == "") {
+					t.Errorf("call %d params = %q, want %q", i, calls[i].Params, tt.calls[i].Params)
+				}
+			}
+		})
+	}
+}
+
+func TestEnvelopeErrors(t *testing.T) {
+	if _, err := Envelope(nil, 1); err == nil {
+		t.Error("Envelope with no calls succeeded")
+	}
+	if _, err := Envelope([]Call{{Method: "ok"}, {Method: ""}}, 1); err == nil {
+		t.Error("Envelope with an invalid call succeeded")
+	}
+
+	for _, body := range []string{`{"method":"x"}`, `[{"jsonrpc":"2.0"}]`, `not json`} {
+		if _, err := ParseEnvelope(body); err == nil {
+			t.Errorf("ParseEnvelope(%s) succeeded, want an error", body)
+		}
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		ids  []string
+		ok   bool
+	}{
+		{"result", `{"jsonrpc":"2.0","id":1,"result":3}`, []string{"1"}, true},
+		{"null result", `{"jsonrpc":"2.0","id":"a","result":null}`, []string{"a"}, true},
+		{"error without id", `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`, []string{"null"}, true},
+		{"batch", `[{"jsonrpc":"2.0","id":1,"result":1},{"jsonrpc":"2.0","id":2,"error":{"code":1,"message":"x"}}]`, []string{"1", "2"}, true},
+		{"empty batch", `[]`, nil, false},
+		{"wrong version", `{"jsonrpc":"1.0","id":1,"result":1}`, nil, false},
+		{"neither result nor error", `{"jsonrpc":"2.0","id":1}`, nil, false},
+		{"one bad entry in a batch", `[{"jsonrpc":"2.0","id":1,"result":1},{"id":2}]`, nil, false},
+		{"empty", ``, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses, ok := ParseResponse(tt.body)
+			if ok != tt.ok {
+				t.Fatalf("ParseResponse(%s) ok = %v, want %v", tt.body, ok, tt.ok)
+			}
+			var ids []string
+			for _, response := range responses {
+				ids = append(ids, response.IDString())
+			}
+			if !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("response ids = %q, want %q", ids, tt.ids)
+			}
+		})
+	}
+}
+
+func TestCodeName(t *testing.T) {
+	tests := []struct {
+		code int
+		want string
+	}{
+		{-32700, "Parse error"},
+		{-32601, "Method not found"},
+		{-32050, "Server error"},
+		{-32100, "Application error"},
+		{42, "Application error"},
+	}
+
+	for _, tt := range tests {
+		if got := CodeName(tt.code); got != tt.want {
+			t.Errorf("CodeName(%d) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
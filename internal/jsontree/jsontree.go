@@ -0,0 +1,244 @@
+package jsontree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind identifies the JSON type of a node
+type Kind int
+
+const (
+	Object Kind = iota
+	Array
+	String
+	Number
+	Bool
+	Null
+)
+
+// Node is a single value in a parsed JSON document. Object keys keep the
+// order they had in the source document.
+type Node struct {
+	Kind     Kind
+	Key      string
+	Index    int
+	Value    string
+	Children []*Node
+	Parent   *Node
+	Depth    int
+	Expanded bool
+}
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Parse builds a tree from a JSON document
+func Parse(data []byte) (*Node, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	root, err := parseValue(dec, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+
+	return root, nil
+}
+
+func parseValue(dec *json.Decoder, parent *Node) (*Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &Node{Parent: parent, Index: -1}
+	if parent != nil {
+		node.Depth = parent.Depth + 1
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			node.Kind = Object
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				child, err := parseValue(dec, node)
+				if err != nil {
+					return nil, err
+				}
+				child.Key = keyTok.(string)
+				node.Children = append(node.Children, child)
+			}
+		case '[':
+			node.Kind = Array
+			for i := 0; dec.More(); i++ {
+				child, err := parseValue(dec, node)
+				if err != nil {
+					return nil, err
+				}
+				child.Index = i
+				node.Children = append(node.Children, child)
+			}
+		default:
+			return nil, fmt.Errorf("unexpected delimiter %q", v)
+		}
+		// Consume the closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		node.Kind = String
+		quoted, _ := json.Marshal(v)
+		node.Value = string(quoted)
+	case json.Number:
+		node.Kind = Number
+		node.Value = v.String()
+	case bool:
+		node.Kind = Bool
+		node.Value = strconv.FormatBool(v)
+	case nil:
+		node.Kind = Null
+		node.Value = "null"
+	}
+
+	return node, nil
+}
+
+// IsContainer reports whether the node is an object or array
+func (n *Node) IsContainer() bool {
+	return n.Kind == Object || n.Kind == Array
+}
+
+// Label returns how the node is addressed by its parent
+func (n *Node) Label() string {
+	switch {
+	case n.Parent == nil:
+		return "$"
+	case n.Parent.Kind == Array:
+		return fmt.Sprintf("[%d]", n.Index)
+	default:
+		return n.Key
+	}
+}
+
+// Summary describes a container's size, e.g. "{3 keys}" or "[12 items]"
+func (n *Node) Summary() string {
+	switch n.Kind {
+	case Object:
+		return fmt.Sprintf("{%d %s}", len(n.Children), plural(len(n.Children), "key", "keys"))
+	case Array:
+		return fmt.Sprintf("[%d %s]", len(n.Children), plural(len(n.Children), "item", "items"))
+	default:
+		return n.Value
+	}
+}
+
+// Path returns the JSONPath expression that selects the node
+func (n *Node) Path() string {
+	if n.Parent == nil {
+		return "$"
+	}
+
+	parent := n.Parent.Path()
+	if n.Parent.Kind == Array {
+		return fmt.Sprintf("%s[%d]", parent, n.Index)
+	}
+	if identifierRegex.MatchString(n.Key) {
+		return parent + "." + n.Key
+	}
+	return parent + "[" + strconv.Quote(n.Key) + "]"
+}
+
+// JSON re-encodes the node's value as indented JSON
+func (n *Node) JSON() string {
+	var b strings.Builder
+	n.writeJSON(&b, "")
+	return b.String()
+}
+
+func (n *Node) writeJSON(b *strings.Builder, indent string) {
+	if !n.IsContainer() {
+		b.WriteString(n.Value)
+		return
+	}
+
+	openDelim, closeDelim := "{", "}"
+	if n.Kind == Array {
+		openDelim, closeDelim = "[", "]"
+	}
+
+	if len(n.Children) == 0 {
+		b.WriteString(openDelim + closeDelim)
+		return
+	}
+
+	b.WriteString(openDelim + "\n")
+	for i, child := range n.Children {
+		b.WriteString(indent + "  ")
+		if n.Kind == Object {
+			key, _ := json.Marshal(child.Key)
+			b.Write(key)
+			b.WriteString(": ")
+		}
+		child.writeJSON(b, indent+"  ")
+		if i < len(n.Children)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(indent + closeDelim)
+}
+
+// ExpandToDepth expands every container shallower than depth and collapses
+// the rest
+func (n *Node) ExpandToDepth(depth int) {
+	n.Expanded = n.IsContainer() && n.Depth < depth
+	for _, child := range n.Children {
+		child.ExpandToDepth(depth)
+	}
+}
+
+// SetExpanded expands or collapses the node and all of its descendants
+func (n *Node) SetExpanded(expanded bool) {
+	n.Expanded = expanded && n.IsContainer()
+	for _, child := range n.Children {
+		child.SetExpanded(expanded)
+	}
+}
+
+// Visible flattens the tree into the nodes currently shown, in display order
+func (n *Node) Visible() []*Node {
+	var nodes []*Node
+	n.collectVisible(&nodes)
+	return nodes
+}
+
+func (n *Node) collectVisible(nodes *[]*Node) {
+	*nodes = append(*nodes, n)
+	if !n.Expanded {
+		return
+	}
+	for _, child := range n.Children {
+		child.collectVisible(nodes)
+	}
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,121 @@
+package jsontree
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testDocument = `{"name": "quest", "tags": ["a", 1.50, true, null], "weird key": {"x-y": {}}, "e": []}`
+
+func mustParse(t *testing.T, doc string) *Node {
+	t.Helper()
+	root, err := Parse([]byte(doc))
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", doc, err)
+	}
+	return root
+}
+
+func TestParse(t *testing.T) {
+	root := mustParse(t, testDocument)
+
+	type row struct {
+		path    string
+		kind    Kind
+		summary string
+		depth   int
+	}
+	var got []row
+	root.SetExpanded(true)
+	for _, n := range root.Visible() {
+		got = append(got, row{n.Path(), n.Kind, n.Summary(), n.Depth})
+	}
+
+	want := []row{
+		{"$", Object, "{4 keys}", 0},
+		{"$.name", String, `"quest"`, 1},
+		{"$.tags", Array, "[4 items]", 1},
+		{"$.tags[0]", String, `"a"`, 2},
+		{"$.tags[1]", Number, "1.50", 2},
+		{"$.tags[2]", Bool, "true", 2},
+		{"$.tags[3]", Null, "null", 2},
+		{`$["weird key"]`, Object, "{1 key}", 1},
+		{`$["weird key"]["x-y"]`, Object, "{0 keys}", 2},
+		{"$.e", Array, "[0 items]", 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsed tree =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, doc := range []string{``, `{"a":`, `[1,]`, `{} {}`, `1 2`} {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", doc)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	root := mustParse(t, `{"list": [{"k": 1}]}`)
+	list := root.Children[0]
+	item := list.Children[0]
+
+	for _, tt := range []struct {
+		node *Node
+		want string
+	}{
+		{root, "$"},
+		{list, "list"},
+		{item, "[0]"},
+		{item.Children[0], "k"},
+	} {
+		if got := tt.node.Label(); got != tt.want {
+			t.Errorf("Label of %s = %q, want %q", tt.node.Path(), got, tt.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	root := mustParse(t, `{"b": [1, {"q\"": "é"}], "a": {}, "n": 1e3}`)
+
+	want := "{\n  \"b\": [\n    1,\n    {\n      \"q\\\"\": \"é\"\n    }\n  ],\n  \"a\": {},\n  \"n\": 1e3\n}"
+	if got := root.JSON(); got != want {
+		t.Errorf("JSON =\n%s\nwant\n%s", got, want)
+	}
+	if got := root.Children[0].Children[0].JSON(); got != "1" {
+		t.Errorf("JSON of a scalar = %q, want 1", got)
+	}
+}
+
+func TestExpandToDepth(t *testing.T) {
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{0, []string{"$"}},
+		{1, []string{"$", "$.a", "$.d"}},
+		{2, []string{"$", "$.a", "$.a.b", "$.d"}},
+		{3, []string{"$", "$.a", "$.a.b", "$.a.b.c", "$.d"}},
+	}
+
+	root := mustParse(t, `{"a": {"b": {"c": 1}}, "d": 2}`)
+	for _, tt := range tests {
+		root.ExpandToDepth(tt.depth)
+		var got []string
+		for _, n := range root.Visible() {
+			got = append(got, n.Path())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExpandToDepth(%d) shows %q, want %q", tt.depth, got, tt.want)
+		}
+	}
+
+	root.SetExpanded(false)
+	if got := root.Visible(); len(got) != 1 {
+		t.Errorf("collapsed tree shows %d nodes, want 1", len(got))
+	}
+	if leaf := root.Children[1]; leaf.Expanded {
+		t.Error("a scalar was marked expanded")
+	}
+}
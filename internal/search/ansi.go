@@ -0,0 +1,79 @@
+package search
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ansiPattern matches CSI escape sequences such as the SGR codes lipgloss emits
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;:?]*[ -/]*[@-~]`)
+
+// Strip removes ANSI escape sequences from s
+func Strip(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// Span is a highlighted region of a line in plain-text byte offsets
+type Span struct {
+	Start int
+	End   int
+	On    string
+	Off   string
+}
+
+// Mark wraps the given plain-text spans of a styled line in their On/Off
+// sequences while keeping the line's existing escape codes intact. Spans must
+// be sorted and non-overlapping. Because existing styling may reset all
+// attributes inside a span, On is re-emitted after every escape sequence that
+// falls within one.
+func Mark(styled string, spans []Span) string {
+	if len(spans) == 0 {
+		return styled
+	}
+
+	escapes := ansiPattern.FindAllStringIndex(styled, -1)
+
+	var b strings.Builder
+	b.Grow(len(styled) + len(spans)*16)
+
+	plain := 0
+	span := 0
+	inside := false
+	esc := 0
+
+	for i := 0; i < len(styled); {
+		if esc < len(escapes) && escapes[esc][0] == i {
+			b.WriteString(styled[i:escapes[esc][1]])
+			if inside {
+				b.WriteString(spans[span].On)
+			}
+			i = escapes[esc][1]
+			esc++
+			continue
+		}
+
+		if span < len(spans) && !inside && plain == spans[span].Start {
+			b.WriteString(spans[span].On)
+			inside = true
+		}
+
+		b.WriteByte(styled[i])
+		i++
+		plain++
+
+		if inside && plain == spans[span].End {
+			b.WriteString(spans[span].Off)
+			inside = false
+			span++
+		}
+	}
+
+	if inside {
+		b.WriteString(spans[span].Off)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package search
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Options controls how a query is matched against text
+type Options struct {
+	CaseSensitive bool
+	Regex         bool
+}
+
+// Match is a single occurrence of a query. Start and End are byte offsets
+// into the plain (ANSI-stripped) line.
+type Match struct {
+	Line  int
+	Start int
+	End   int
+}
+
+// Compile turns a query into a regular expression honouring the options
+func Compile(query string, opts Options) (*regexp.Regexp, error) {
+	pattern := query
+	if !opts.Regex {
+		pattern = regexp.QuoteMeta(query)
+	}
+	if !opts.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// Find returns every match of query in the given plain-text lines
+func Find(lines []string, query string, opts Options) ([]Match, error) {
+	if query == "" {
+		return nil, nil
+	}
+
+	re, err := Compile(query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for i, line := range lines {
+		for _, loc := range re.FindAllStringIndex(line, -1) {
+			// Empty matches (e.g. "a*") can't be highlighted or navigated to
+			if loc[0] == loc[1] {
+				continue
+			}
+			matches = append(matches, Match{Line: i, Start: loc[0], End: loc[1]})
+		}
+	}
+
+	return matches, nil
+}
+
+// Lines splits styled content into its ANSI-stripped lines
+func Lines(content string) []string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = Strip(line)
+	}
+	return lines
+}
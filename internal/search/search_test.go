@@ -0,0 +1,95 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	lines := []string{"Hello hello", "a.b axb", "naïve"}
+
+	tests := []struct {
+		name  string
+		query string
+		opts  Options
+		want  []Match
+	}{
+		{"empty query", "", Options{}, nil},
+		{"case insensitive", "hello", Options{}, []Match{{0, 0, 5}, {0, 6, 11}}},
+		{"case sensitive", "hello", Options{CaseSensitive: true}, []Match{{0, 6, 11}}},
+		{"literal metacharacters", "a.b", Options{}, []Match{{1, 0, 3}}},
+		{"regex", "a.b", Options{Regex: true}, []Match{{1, 0, 3}, {1, 4, 7}}},
+		{"empty regex matches are skipped", "z*", Options{Regex: true}, nil},
+		{"byte offsets", "ve", Options{}, []Match{{2, 4, 6}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Find(lines, tt.query, tt.opts)
+			if err != nil {
+				t.Fatalf("Find(%q): %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindInvalidRegex(t *testing.T) {
+	if _, err := Find([]string{"x"}, "(", Options{Regex: true}); err == nil {
+		t.Error("Find with an invalid regex succeeded, want an error")
+	}
+	if _, err := Find([]string{"("}, "(", Options{}); err != nil {
+		t.Errorf("Find with a literal ( = %v, want nil", err)
+	}
+}
+
+func TestLines(t *testing.T) {
+	got := Lines("\x1b[1mbold\x1b[0m\nplain\n\x1b[38;5;10mgreen\x1b[0m")
+	want := []string{"bold", "plain", "green"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines = %q, want %q", got, want)
+	}
+}
+
+func TestMark(t *testing.T) {
+	tests := []struct {
+		name   string
+		styled string
+		spans  []Span
+		want   string
+	}{
+		{
+			name:   "no spans",
+			styled: "abc",
+			want:   "abc",
+		},
+		{
+			name:   "plain text",
+			styled: "abcdef",
+			spans:  []Span{{1, 3, "[", "]"}, {4, 5, "<", ">"}},
+			want:   "a[bc]d<e>f",
+		},
+		{
+			name:   "existing styling is kept and the mark re-applied",
+			styled: "a\x1b[1mbc\x1b[0md",
+			spans:  []Span{{0, 3, "[", "]"}},
+			want:   "[a\x1b[1m[bc]\x1b[0md",
+		},
+		{
+			name:   "span running to the end",
+			styled: "ab\x1b[0m",
+			spans:  []Span{{1, 2, "[", "]"}},
+			want:   "a[b]\x1b[0m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Mark(tt.styled, tt.spans); got != tt.want {
+				t.Errorf("Mark(%q) = %q, want %q", tt.styled, got, tt.want)
+			}
+		})
+	}
+}
@@ -6,114 +6,211 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-const (
-	HotPink   = lipgloss.Color("#FF06B7")
-	DarkGray  = lipgloss.Color("#767676")
-	LightGray = lipgloss.Color("#C4C4C4")
-	Green     = lipgloss.Color("#04B575")
-	Red       = lipgloss.Color("#FF4757")
-	Blue      = lipgloss.Color("#3742FA")
-	Yellow    = lipgloss.Color("#FFA502")
-	Purple    = lipgloss.Color("#9C88FF")
-	Orange    = lipgloss.Color("#FF7675")
-	White     = lipgloss.Color("#FFFFFF")
-	Black     = lipgloss.Color("#000000")
+// The palette's roles, set from the current theme by Apply
+var (
+	// Accent marks titles, focus and selection
+	Accent lipgloss.Color
+	// Muted is for help text, metadata and unfocused borders
+	Muted lipgloss.Color
+	// Text is for plain content such as header values and table cells
+	Text    lipgloss.Color
+	Success lipgloss.Color
+	Danger  lipgloss.Color
+	Info    lipgloss.Color
+	Warning lipgloss.Color
+	// Special and Caution tell apart token kinds and methods that the
+	// other roles don't cover, such as numbers and booleans
+	Special lipgloss.Color
+	Caution lipgloss.Color
+	// OnAccent is text drawn on an Accent or Danger background
+	OnAccent lipgloss.Color
+)
+
+var (
+	TitleStyle    lipgloss.Style
+	SubtitleStyle lipgloss.Style
+	StatusStyle   lipgloss.Style
+	ErrorStyle    lipgloss.Style
+	InfoStyle     lipgloss.Style
+	JsonStyle     lipgloss.Style
+	HelpStyle     lipgloss.Style
+	AlertStyle    lipgloss.Style
 )
 
 var (
+	HeaderStyle   lipgloss.Style
+	FocusedStyle  lipgloss.Style
+	BlurredStyle  lipgloss.Style
+	ResponseStyle lipgloss.Style
+)
+
+var (
+	TabStyle       lipgloss.Style
+	ActiveTabStyle lipgloss.Style
+)
+
+var MethodColors map[string]lipgloss.Color
+
+// current is the theme last applied
+var current Theme
+
+// restylers rebuild the styles other packages derive from the palette
+var restylers []func()
+
+func init() {
+	Apply(Dark)
+}
+
+// Current returns the theme in use
+func Current() Theme {
+	return current
+}
+
+// Apply makes t the theme every style is drawn from. Styles built from the
+// palette outside this package pick it up the next time they are built.
+func Apply(t Theme) {
+	current = t
+	Accent, Muted, Text = t.Accent, t.Muted, t.Text
+	Success, Danger, Info, Warning = t.Success, t.Danger, t.Info, t.Warning
+	Special, Caution, OnAccent = t.Special, t.Caution, t.OnAccent
+
 	TitleStyle = lipgloss.NewStyle().
-			Foreground(HotPink).
-			Bold(true).
-			Padding(0, 1)
+		Foreground(Accent).
+		Bold(true).
+		Padding(0, 1)
 
 	SubtitleStyle = lipgloss.NewStyle().
-			Foreground(DarkGray).
-			Italic(true)
+		Foreground(Muted).
+		Italic(true)
 
 	StatusStyle = lipgloss.NewStyle().
-			Foreground(Green).
-			Bold(true)
+		Foreground(Success).
+		Bold(true)
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(Red).
-			Bold(true)
+		Foreground(Danger).
+		Bold(true)
 
 	InfoStyle = lipgloss.NewStyle().
-			Foreground(Blue)
+		Foreground(Info)
 
 	JsonStyle = lipgloss.NewStyle().
-			Foreground(LightGray)
+		Foreground(Text)
 
 	HelpStyle = lipgloss.NewStyle().
-			Foreground(DarkGray).
-			Italic(true)
-)
+		Foreground(Muted).
+		Italic(true)
+
+	AlertStyle = lipgloss.NewStyle().
+		Foreground(OnAccent).
+		Background(Danger).
+		Reverse(Danger == "").
+		Bold(true).
+		Padding(0, 1)
 
-var (
 	HeaderStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(HotPink).
-			Padding(0, 1)
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(Accent).
+		Padding(0, 1)
 
 	FocusedStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(HotPink).
-			Padding(0, 1)
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(Accent).
+		Padding(0, 1)
+	if Accent == "" {
+		// Without colour, focus shows as a heavier border
+		FocusedStyle = FocusedStyle.BorderStyle(lipgloss.ThickBorder())
+	}
 
 	BlurredStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(DarkGray).
-			Padding(0, 1)
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(Muted).
+		Padding(0, 1)
 
 	ResponseStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(Blue).
-			Padding(1, 2)
-)
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(Info).
+		Padding(1, 2)
 
-var (
 	TabStyle = lipgloss.NewStyle().
-			Padding(0, 1).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(DarkGray)
-
-	ActiveTabStyle = lipgloss.NewStyle().
-			Padding(0, 1).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(HotPink).
-			Background(HotPink).
-			Foreground(White).
-			Bold(true)
-)
+		Padding(0, 1).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(Muted)
+
+	ActiveTabStyle = SelectedStyle().
+		Padding(0, 1).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(Accent).
+		Bold(true)
+
+	MethodColors = map[string]lipgloss.Color{
+		"GET":     Success,
+		"POST":    Info,
+		"PUT":     Warning,
+		"DELETE":  Danger,
+		"PATCH":   Special,
+		"HEAD":    Text,
+		"OPTIONS": Text,
+		"TRACE":   Text,
+		// WebDAV verbs follow the standard method they most resemble
+		"PROPFIND":  Success,
+		"REPORT":    Success,
+		"SEARCH":    Success,
+		"PROPPATCH": Special,
+		"MKCOL":     Info,
+		"COPY":      Info,
+		"MOVE":      Warning,
+		"LOCK":      Text,
+		"UNLOCK":    Text,
+		"WS":        Caution,
+		"GRAPHQL":   Accent,
+		"GRPC":      Special,
+		"JSONRPC":   Warning,
+	}
+	for method, color := range t.Methods {
+		MethodColors[method] = color
+	}
+
+	for _, restyle := range restylers {
+		restyle()
+	}
+}
+
+// OnApply registers restyle to rebuild styles derived from the palette. It
+// runs now and again whenever a theme is applied.
+func OnApply(restyle func()) {
+	restylers = append(restylers, restyle)
+	restyle()
+}
 
-var MethodColors = map[string]lipgloss.Color{
-	"GET":    Green,
-	"POST":   Blue,
-	"PUT":    Yellow,
-	"DELETE": Red,
-	"PATCH":  Purple,
-	"HEAD":   LightGray,
+// SelectedStyle draws the selected item of a list or the active tab: text
+// on the accent, or reversed when the theme has no accent colour
+func SelectedStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(OnAccent).
+		Background(Accent).
+		Reverse(Accent == "")
 }
 
 func StatusCodeColor(code int) lipgloss.Color {
 	switch {
 	case code >= 200 && code < 300:
-		return Green
+		return Success
 	case code >= 300 && code < 400:
-		return Yellow
+		return Warning
 	case code >= 400 && code < 500:
-		return Orange
+		return Caution
 	case code >= 500:
-		return Red
+		return Danger
 	default:
-		return DarkGray
+		return Muted
 	}
 }
 
 func StyledMethod(method string) string {
 	color, exists := MethodColors[method]
 	if !exists {
-		color = DarkGray
+		color = Muted
 	}
 	return lipgloss.NewStyle().Foreground(color).Bold(true).Render(method)
 }
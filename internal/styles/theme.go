@@ -0,0 +1,242 @@
+package styles
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme assigns a colour to each of the palette's roles. Colours are
+// "#RRGGBB" or "#RGB", or an ANSI colour number from 0 to 255; an empty
+// colour leaves the terminal's own.
+type Theme struct {
+	Name     string         `json:"-"`
+	Accent   lipgloss.Color `json:"accent"`
+	Muted    lipgloss.Color `json:"muted"`
+	Text     lipgloss.Color `json:"text"`
+	Success  lipgloss.Color `json:"success"`
+	Danger   lipgloss.Color `json:"danger"`
+	Info     lipgloss.Color `json:"info"`
+	Warning  lipgloss.Color `json:"warning"`
+	Special  lipgloss.Color `json:"special"`
+	Caution  lipgloss.Color `json:"caution"`
+	OnAccent lipgloss.Color `json:"on_accent"`
+	// Methods overrides the colour of individual methods, by name
+	Methods map[string]lipgloss.Color `json:"methods,omitempty"`
+}
+
+var (
+	// Dark suits dark terminal backgrounds
+	Dark = Theme{
+		Name:     "dark",
+		Accent:   "#FF06B7",
+		Muted:    "#767676",
+		Text:     "#C4C4C4",
+		Success:  "#04B575",
+		Danger:   "#FF4757",
+		Info:     "#3742FA",
+		Warning:  "#FFA502",
+		Special:  "#9C88FF",
+		Caution:  "#FF7675",
+		OnAccent: "#FFFFFF",
+	}
+
+	// Light suits light terminal backgrounds
+	Light = Theme{
+		Name:     "light",
+		Accent:   "#C2187A",
+		Muted:    "#6B6B6B",
+		Text:     "#303030",
+		Success:  "#007A4D",
+		Danger:   "#C8102E",
+		Info:     "#1F4FD1",
+		Warning:  "#8F5B00",
+		Special:  "#6A3FC8",
+		Caution:  "#B84A00",
+		OnAccent: "#FFFFFF",
+	}
+
+	// HighContrast uses bright, saturated colours on dark backgrounds
+	HighContrast = Theme{
+		Name:     "high-contrast",
+		Accent:   "#FF5FFF",
+		Muted:    "#BCBCBC",
+		Text:     "#FFFFFF",
+		Success:  "#00FF5F",
+		Danger:   "#FF3030",
+		Info:     "#5FD7FF",
+		Warning:  "#FFFF00",
+		Special:  "#D7AFFF",
+		Caution:  "#FF8700",
+		OnAccent: "#000000",
+	}
+
+	// Mono sets no colours, leaving bold, italic, underline and reverse
+	// video to tell things apart
+	Mono = Theme{Name: "mono"}
+)
+
+// BuiltinThemes lists the themes that need no file, in the order they are
+// cycled through
+var BuiltinThemes = []Theme{Dark, Light, HighContrast, Mono}
+
+// NoColor reports whether the NO_COLOR environment variable asks for output
+// without colour
+func NoColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// LoadTheme finds a theme by name: a built-in one, dir/<name>.json, or,
+// when name is a path to a .json file, that file
+func LoadTheme(name, dir string) (Theme, error) {
+	for _, theme := range BuiltinThemes {
+		if theme.Name == name {
+			return theme, nil
+		}
+	}
+
+	path := filepath.Join(dir, name+".json")
+	if strings.HasSuffix(name, ".json") || strings.ContainsRune(name, os.PathSeparator) {
+		path = name
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Theme{}, fmt.Errorf("theme %q: not built in (%s) and there is no %s", name, strings.Join(builtinNames(), ", "), path)
+	}
+	if err != nil {
+		return Theme{}, fmt.Errorf("theme %q: %w", name, err)
+	}
+	theme, err := parseTheme(data)
+	if err != nil {
+		return Theme{}, fmt.Errorf("theme %q (%s): %w", name, path, err)
+	}
+	theme.Name = name
+	return theme, nil
+}
+
+// parseTheme reads a theme file. A file may name a built-in theme as its
+// "base", which supplies every colour the file leaves out; the default
+// base is dark.
+func parseTheme(data []byte) (Theme, error) {
+	var header struct {
+		Base string `json:"base"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return Theme{}, err
+	}
+
+	var file struct {
+		Base string `json:"base"`
+		Theme
+	}
+	file.Theme = Dark
+	if header.Base != "" {
+		found := false
+		for _, theme := range BuiltinThemes {
+			if theme.Name == header.Base {
+				file.Theme, found = theme, true
+			}
+		}
+		if !found {
+			return Theme{}, fmt.Errorf("base %q isn't a built-in theme (%s)", header.Base, strings.Join(builtinNames(), ", "))
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return Theme{}, err
+	}
+	if file.Methods != nil {
+		methods := make(map[string]lipgloss.Color, len(file.Methods))
+		for method, color := range file.Methods {
+			methods[strings.ToUpper(method)] = color
+		}
+		file.Methods = methods
+	}
+	if err := file.Theme.Validate(); err != nil {
+		return Theme{}, err
+	}
+	return file.Theme, nil
+}
+
+// hexColor matches the "#RGB" and "#RRGGBB" forms
+var hexColor = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// Validate checks that every colour is in a form the terminal understands
+func (t Theme) Validate() error {
+	type role struct {
+		name  string
+		color lipgloss.Color
+	}
+	roles := []role{
+		{"accent", t.Accent}, {"muted", t.Muted}, {"text", t.Text},
+		{"success", t.Success}, {"danger", t.Danger}, {"info", t.Info},
+		{"warning", t.Warning}, {"special", t.Special}, {"caution", t.Caution},
+		{"on_accent", t.OnAccent},
+	}
+	for _, method := range sortedMethods(t.Methods) {
+		roles = append(roles, role{"methods." + method, t.Methods[method]})
+	}
+
+	for _, role := range roles {
+		color := string(role.color)
+		if color == "" || hexColor.MatchString(color) {
+			continue
+		}
+		if n, err := strconv.Atoi(color); err == nil && n >= 0 && n <= 255 {
+			continue
+		}
+		return fmt.Errorf("%s: %q isn't #RRGGBB, #RGB or an ANSI colour from 0 to 255", role.name, color)
+	}
+	return nil
+}
+
+// ThemeNames lists the built-in themes followed by the theme files in dir
+func ThemeNames(dir string) []string {
+	names := builtinNames()
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	sort.Strings(paths)
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		if !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func builtinNames() []string {
+	names := make([]string, len(BuiltinThemes))
+	for i, theme := range BuiltinThemes {
+		names[i] = theme.Name
+	}
+	return names
+}
+
+func sortedMethods(methods map[string]lipgloss.Color) []string {
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
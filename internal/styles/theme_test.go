@@ -0,0 +1,125 @@
+package styles
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestLoadTheme(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"ocean.json":      `{"accent": "#0af", "methods": {"get": "33"}}`,
+		"paper.json":      `{"base": "light", "text": "#000000"}`,
+		"bad-base.json":   `{"base": "neon"}`,
+		"bad-color.json":  `{"accent": "pink"}`,
+		"bad-method.json": `{"methods": {"POST": "256"}}`,
+		"unknown.json":    `{"acent": "#fff"}`,
+		"broken.json":     `{"accent": `,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ocean := Dark
+	ocean.Name = "ocean"
+	ocean.Accent = "#0af"
+	ocean.Methods = map[string]lipgloss.Color{"GET": "33"}
+
+	paper := Light
+	paper.Name = "paper"
+	paper.Text = "#000000"
+
+	byPath := paper
+	byPath.Name = filepath.Join(dir, "paper.json")
+
+	tests := []struct {
+		name string
+		want Theme
+		err  string
+	}{
+		{name: "light", want: Light},
+		{name: "mono", want: Mono},
+		{name: "ocean", want: ocean},
+		{name: "paper", want: paper},
+		{name: filepath.Join(dir, "paper.json"), want: byPath},
+		{name: "missing", err: "not built in (dark, light, high-contrast, mono) and there is no"},
+		{name: "bad-base", err: `base "neon" isn't a built-in theme`},
+		{name: "bad-color", err: `accent: "pink" isn't #RRGGBB`},
+		{name: "bad-method", err: `methods.POST: "256"`},
+		{name: "unknown", err: `unknown field "acent"`},
+		{name: "broken", err: "broken.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(filepath.Base(tt.name), func(t *testing.T) {
+			theme, err := LoadTheme(tt.name, dir)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("LoadTheme(%q) error = %v, want one mentioning %q", tt.name, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadTheme(%q): %v", tt.name, err)
+			}
+			if !reflect.DeepEqual(theme, tt.want) {
+				t.Errorf("LoadTheme(%q) =\n%+v\nwant\n%+v", tt.name, theme, tt.want)
+			}
+		})
+	}
+}
+
+func TestThemeNames(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"zebra.json", "dark.json", "alpha.json", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"dark", "light", "high-contrast", "mono", "alpha", "zebra"}
+	if got := ThemeNames(dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("ThemeNames = %q, want %q", got, want)
+	}
+}
+
+func TestBuiltinThemesValidate(t *testing.T) {
+	for _, theme := range BuiltinThemes {
+		if err := theme.Validate(); err != nil {
+			t.Errorf("%s: %v", theme.Name, err)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Cleanup(func() { Apply(Dark) })
+
+	var seen []lipgloss.Color
+	OnApply(func() { seen = append(seen, Accent) })
+
+	custom := Light
+	custom.Methods = map[string]lipgloss.Color{"GET": "#123456"}
+	Apply(custom)
+
+	if !reflect.DeepEqual(seen, []lipgloss.Color{Dark.Accent, Light.Accent}) {
+		t.Errorf("restyled with accents %q, want the dark then light accent", seen)
+	}
+	if Current().Name != "light" || Accent != Light.Accent {
+		t.Errorf("Current = %q with accent %q after applying light", Current().Name, Accent)
+	}
+	if MethodColors["GET"] != "#123456" {
+		t.Errorf("GET colour = %q, want the theme's override", MethodColors["GET"])
+	}
+
+	Apply(Dark)
+	if MethodColors["GET"] == "#123456" {
+		t.Error("a method override outlived its theme")
+	}
+}
@@ -0,0 +1,163 @@
+package syntax
+
+import "strings"
+
+// highlightCSS colours selectors, at-rules, properties and values. It tracks
+// brace depth to tell selectors from declarations, which also covers rules
+// nested inside @media blocks.
+func (h *Highlighter) highlightCSS(content string) string {
+	p := h.palette
+
+	var b strings.Builder
+	b.Grow(len(content) * 2)
+
+	depth := 0
+	inValue := false
+
+	n := len(content)
+	for i := 0; i < n; {
+		c := content[i]
+
+		switch {
+		case strings.HasPrefix(content[i:], "/*"):
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				end = n
+			} else {
+				end += i + 4
+			}
+			p.comment.write(&b, content[i:end])
+			i = end
+
+		case c == '"' || c == '\'':
+			end := scanQuoted(content, i, c)
+			p.str.write(&b, content[i:end])
+			i = end
+
+		case c == '{':
+			p.punct.write(&b, "{")
+			depth++
+			inValue = false
+			i++
+
+		case c == '}':
+			p.punct.write(&b, "}")
+			if depth > 0 {
+				depth--
+			}
+			inValue = false
+			i++
+
+		case c == ';':
+			p.punct.write(&b, ";")
+			inValue = false
+			i++
+
+		case c == ':' && depth > 0 && !inValue && !cssOpensBlock(content, i):
+			p.punct.write(&b, ":")
+			inValue = true
+			i++
+
+		case c == '@':
+			end := scanIdent(content, i+1)
+			p.keyword.write(&b, content[i:end])
+			i = end
+
+		case c == '!' && inValue:
+			end := scanIdent(content, i+1)
+			p.keyword.write(&b, content[i:end])
+			i = end
+
+		case c == '#' && inValue:
+			end := i + 1
+			for end < n && isHexDigit(content[end]) {
+				end++
+			}
+			p.number.write(&b, content[i:end])
+			i = end
+
+		case inValue && (isDigit(c) || ((c == '.' || c == '-') && i+1 < n && isDigit(content[i+1]))):
+			// Numbers with their unit, e.g. 1.5rem or 100%
+			end := i + 1
+			for end < n && (isDigit(content[end]) || content[end] == '.') {
+				end++
+			}
+			for end < n && (isLetter(content[end]) || content[end] == '%') {
+				end++
+			}
+			p.number.write(&b, content[i:end])
+			i = end
+
+		case isIdentStart(c):
+			end := max(scanIdent(content, i), i+1)
+			word := content[i:end]
+			switch {
+			case inValue && end < n && content[end] == '(':
+				p.attr.write(&b, word)
+			case inValue:
+				p.str.write(&b, word)
+			case depth > 0 && !cssOpensBlock(content, i):
+				p.key.write(&b, word)
+			default:
+				p.tag.write(&b, word)
+			}
+			i = end
+
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// cssOpensBlock reports whether the text from i reaches a '{' before the
+// end of the current declaration, meaning it is a nested selector
+func cssOpensBlock(s string, i int) bool {
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			return true
+		case ';', '}':
+			return false
+		}
+	}
+	return false
+}
+
+func scanIdent(s string, i int) int {
+	for i < len(s) && (isLetter(s[i]) || isDigit(s[i]) || s[i] == '-' || s[i] == '_') {
+		i++
+	}
+	return i
+}
+
+// scanQuoted returns the index just past the quote-delimited string at i
+func scanQuoted(s string, i int, quote byte) int {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+func isIdentStart(c byte) bool {
+	return isLetter(c) || c == '_' || c == '-' || c == '*'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,63 @@
+package syntax
+
+import "strings"
+
+// highlightCSV gives each column its own colour and makes the header bold.
+// Quoted fields may contain commas, escaped quotes and line breaks.
+func (h *Highlighter) highlightCSV(content string) string {
+	p := h.palette
+
+	var b strings.Builder
+	b.Grow(len(content) * 3)
+
+	column := 0
+	header := true
+	inQuote := false
+	start := 0
+
+	flush := func(end int) {
+		field := content[start:end]
+		color := p.columns[column%len(p.columns)]
+		if header && field != "" {
+			b.WriteString(p.emphasis.on)
+		}
+		// Colour each physical line separately so multi-line fields survive
+		// being split into viewport lines
+		for j, part := range strings.Split(field, "\n") {
+			if j > 0 {
+				b.WriteByte('\n')
+			}
+			color.write(&b, part)
+		}
+		if header && field != "" {
+			b.WriteString(p.emphasis.off)
+		}
+	}
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case c == ',':
+			flush(i)
+			p.punct.write(&b, ",")
+			column++
+			start = i + 1
+		case c == '\n':
+			end := i
+			if end > start && content[end-1] == '\r' {
+				end--
+			}
+			flush(end)
+			b.WriteString(content[end : i+1])
+			column = 0
+			header = false
+			start = i + 1
+		}
+	}
+	flush(len(content))
+
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package syntax
+
+import (
+	"net/url"
+	"strings"
+)
+
+// highlightForm lays out an application/x-www-form-urlencoded body as one
+// decoded "key = value" pair per line
+func (h *Highlighter) highlightForm(content string) string {
+	p := h.palette
+
+	var lines []string
+	for _, pair := range strings.Split(strings.TrimSpace(content), "&") {
+		if pair == "" {
+			continue
+		}
+
+		key, value, hasValue := strings.Cut(pair, "=")
+		line := p.key.render(unescapeForm(key))
+		if hasValue {
+			line += p.punct.render(" = ") + p.str.render(unescapeForm(value))
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func unescapeForm(s string) string {
+	if decoded, err := url.QueryUnescape(s); err == nil {
+		return decoded
+	}
+	return s
+}
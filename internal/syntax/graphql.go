@@ -0,0 +1,105 @@
+package syntax
+
+import "strings"
+
+var graphQLKeywords = map[string]bool{
+	"query": true, "mutation": true, "subscription": true, "fragment": true,
+	"on": true, "type": true, "input": true, "enum": true, "interface": true,
+	"union": true, "scalar": true, "schema": true, "extend": true,
+	"directive": true, "implements": true, "repeatable": true,
+}
+
+// highlightGraphQL colours GraphQL documents: operations, variables,
+// directives, arguments, type names and literals
+func (h *Highlighter) highlightGraphQL(content string) string {
+	p := h.palette
+
+	var b strings.Builder
+	b.Grow(len(content) * 2)
+
+	n := len(content)
+	for i := 0; i < n; {
+		c := content[i]
+
+		switch {
+		case c == '#':
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				end = n
+			} else {
+				end += i
+			}
+			p.comment.write(&b, content[i:end])
+			i = end
+
+		case strings.HasPrefix(content[i:], `"""`):
+			end := strings.Index(content[i+3:], `"""`)
+			if end < 0 {
+				end = n
+			} else {
+				end += i + 6
+			}
+			p.str.write(&b, content[i:end])
+			i = end
+
+		case c == '"':
+			end := scanQuoted(content, i, '"')
+			p.str.write(&b, content[i:end])
+			i = end
+
+		case isDigit(c) || (c == '-' && i+1 < n && isDigit(content[i+1])):
+			end := scanNumber(content, i)
+			p.number.write(&b, content[i:end])
+			i = end
+
+		case (c == '$' || c == '@') && i+1 < n && isNameByte(content[i+1]):
+			end := i + 1
+			for end < n && isNameByte(content[end]) {
+				end++
+			}
+			if c == '$' {
+				p.variable.write(&b, content[i:end])
+			} else {
+				p.attr.write(&b, content[i:end])
+			}
+			i = end
+
+		case strings.HasPrefix(content[i:], "..."):
+			p.punct.write(&b, "...")
+			i += 3
+
+		case isLetter(c) || c == '_':
+			end := i + 1
+			for end < n && isNameByte(content[end]) {
+				end++
+			}
+			word := content[i:end]
+			switch {
+			case graphQLKeywords[word]:
+				p.keyword.write(&b, word)
+			case word == "true" || word == "false":
+				p.boolean.write(&b, word)
+			case word == "null":
+				p.null.write(&b, word)
+			case skipSpace(content, end) < n && content[skipSpace(content, end)] == ':':
+				// Aliases and arguments
+				p.key.write(&b, word)
+			case isUpper(word[0]):
+				p.typeName.write(&b, word)
+			default:
+				b.WriteString(word)
+			}
+			i = end
+
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+func isNameByte(c byte) bool {
+	return isLetter(c) || isDigit(c) || c == '_'
+}
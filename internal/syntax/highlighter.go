@@ -19,20 +19,117 @@ type Highlighter struct {
 	htmlTagStyle    lipgloss.Style
 	htmlAttrStyle   lipgloss.Style
 	xmlTagStyle     lipgloss.Style
+	commentStyle    lipgloss.Style
+	keywordStyle    lipgloss.Style
+	typeStyle       lipgloss.Style
+	variableStyle   lipgloss.Style
+	punctStyle      lipgloss.Style
+	headingStyle    lipgloss.Style
+	emphasisStyle   lipgloss.Style
+	codeStyle       lipgloss.Style
+	linkStyle       lipgloss.Style
+
+	palette  palette
+	registry *Registry
 }
 
-// NewHighlighter creates a new syntax highlighter
+// NewHighlighter creates a new syntax highlighter with the built-in
+// languages registered, drawing in the current theme's colours
 func NewHighlighter() *Highlighter {
-	return &Highlighter{
-		jsonKeyStyle:    lipgloss.NewStyle().Foreground(styles.Blue).Bold(true),
-		jsonStringStyle: lipgloss.NewStyle().Foreground(styles.Green),
-		jsonNumberStyle: lipgloss.NewStyle().Foreground(styles.Purple),
-		jsonBoolStyle:   lipgloss.NewStyle().Foreground(styles.Orange),
-		jsonNullStyle:   lipgloss.NewStyle().Foreground(styles.DarkGray),
-		htmlTagStyle:    lipgloss.NewStyle().Foreground(styles.HotPink),
-		htmlAttrStyle:   lipgloss.NewStyle().Foreground(styles.Blue),
-		xmlTagStyle:     lipgloss.NewStyle().Foreground(styles.Purple),
+	h := &Highlighter{
+		jsonKeyStyle:    lipgloss.NewStyle().Foreground(styles.Info).Bold(true),
+		jsonStringStyle: lipgloss.NewStyle().Foreground(styles.Success),
+		jsonNumberStyle: lipgloss.NewStyle().Foreground(styles.Special),
+		jsonBoolStyle:   lipgloss.NewStyle().Foreground(styles.Caution),
+		jsonNullStyle:   lipgloss.NewStyle().Foreground(styles.Muted),
+		htmlTagStyle:    lipgloss.NewStyle().Foreground(styles.Accent),
+		htmlAttrStyle:   lipgloss.NewStyle().Foreground(styles.Info),
+		xmlTagStyle:     lipgloss.NewStyle().Foreground(styles.Special),
+		commentStyle:    lipgloss.NewStyle().Foreground(styles.Muted).Italic(true),
+		keywordStyle:    lipgloss.NewStyle().Foreground(styles.Accent).Bold(true),
+		typeStyle:       lipgloss.NewStyle().Foreground(styles.Warning),
+		variableStyle:   lipgloss.NewStyle().Foreground(styles.Caution),
+		punctStyle:      lipgloss.NewStyle().Foreground(styles.Muted),
+		headingStyle:    lipgloss.NewStyle().Foreground(styles.Accent).Bold(true),
+		emphasisStyle:   lipgloss.NewStyle().Bold(true),
+		codeStyle:       lipgloss.NewStyle().Foreground(styles.Warning),
+		linkStyle:       lipgloss.NewStyle().Foreground(styles.Info).Underline(true),
 	}
+
+	h.palette = palette{
+		key:      newSGR(h.jsonKeyStyle),
+		str:      newSGR(h.jsonStringStyle),
+		number:   newSGR(h.jsonNumberStyle),
+		boolean:  newSGR(h.jsonBoolStyle),
+		null:     newSGR(h.jsonNullStyle),
+		comment:  newSGR(h.commentStyle),
+		keyword:  newSGR(h.keywordStyle),
+		typeName: newSGR(h.typeStyle),
+		variable: newSGR(h.variableStyle),
+		tag:      newSGR(h.htmlTagStyle),
+		attr:     newSGR(h.htmlAttrStyle),
+		punct:    newSGR(h.punctStyle),
+		heading:  newSGR(h.headingStyle),
+		emphasis: newSGR(h.emphasisStyle),
+		code:     newSGR(h.codeStyle),
+		link:     newSGR(h.linkStyle),
+	}
+	for _, color := range []lipgloss.Color{styles.Info, styles.Success, styles.Special, styles.Caution, styles.Warning, styles.Accent} {
+		h.palette.columns = append(h.palette.columns, newSGR(lipgloss.NewStyle().Foreground(color)))
+	}
+
+	h.registry = NewRegistry()
+	h.registerBuiltins()
+
+	return h
+}
+
+// registerBuiltins wires up the languages Quest understands out of the box
+func (h *Highlighter) registerBuiltins() {
+	for _, mediaType := range []string{"application/json", "text/json", "application/x-ndjson", "application/ndjson"} {
+		h.Register(mediaType, h.highlightJSON)
+	}
+	h.RegisterSuffix("json", h.highlightJSON)
+
+	h.Register("text/html", h.highlightHTML)
+	h.Register("application/xhtml+xml", h.highlightHTML)
+
+	for _, mediaType := range []string{"application/xml", "text/xml"} {
+		h.Register(mediaType, h.highlightXML)
+	}
+	h.RegisterSuffix("xml", h.highlightXML)
+
+	for _, mediaType := range []string{"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml"} {
+		h.Register(mediaType, h.highlightYAML)
+	}
+	h.RegisterSuffix("yaml", h.highlightYAML)
+
+	h.Register("text/css", h.highlightCSS)
+
+	for _, mediaType := range []string{"application/javascript", "text/javascript", "application/x-javascript", "application/ecmascript", "text/ecmascript"} {
+		h.Register(mediaType, h.highlightJavaScript)
+	}
+
+	h.Register("application/graphql", h.highlightGraphQL)
+
+	for _, mediaType := range []string{"text/markdown", "text/x-markdown"} {
+		h.Register(mediaType, h.highlightMarkdown)
+	}
+
+	h.Register("text/csv", h.highlightCSV)
+	h.Register("application/x-www-form-urlencoded", h.highlightForm)
+	h.Register("text/event-stream", h.highlightEventStream)
+}
+
+// Register adds or replaces the highlighter for a media type
+func (h *Highlighter) Register(mediaType string, fn Func) {
+	h.registry.Register(mediaType, fn)
+}
+
+// RegisterSuffix adds or replaces the highlighter for a structured syntax
+// suffix such as "json" or "xml"
+func (h *Highlighter) RegisterSuffix(suffix string, fn Func) {
+	h.registry.RegisterSuffix(suffix, fn)
 }
 
 // Highlight applies syntax highlighting based on content type
@@ -46,16 +143,12 @@ func (h *Highlighter) Highlight(content, contentType string) string {
 		contentType = h.detectContentType(content)
 	}
 
-	switch {
-	case strings.Contains(contentType, "json"):
-		return h.highlightJSON(content)
-	case strings.Contains(contentType, "html"):
-		return h.highlightHTML(content)
-	case strings.Contains(contentType, "xml"):
-		return h.highlightXML(content)
-	default:
+	fn, ok := h.registry.Lookup(contentType)
+	if !ok {
 		return content
 	}
+
+	return fn(content)
 }
 
 // detectContentType attempts to detect the content type
@@ -81,54 +174,6 @@ func (h *Highlighter) detectContentType(content string) string {
 	return "text/plain"
 }
 
-// highlightJSON applies JSON syntax highlighting
-func (h *Highlighter) highlightJSON(content string) string {
-	// First, try to format the JSON
-	var obj interface{}
-	if err := json.Unmarshal([]byte(content), &obj); err == nil {
-		if formatted, err := json.MarshalIndent(obj, "", "  "); err == nil {
-			content = string(formatted)
-		}
-	}
-
-	// Apply syntax highlighting with regex patterns
-	patterns := []struct {
-		regex *regexp.Regexp
-		style lipgloss.Style
-	}{
-		// JSON keys (quoted strings followed by colon)
-		{regexp.MustCompile(`"([^"\\]|\\.)*":`), h.jsonKeyStyle},
-		// JSON string values
-		{regexp.MustCompile(`:\s*"([^"\\]|\\.)*"`), h.jsonStringStyle},
-		// JSON numbers
-		{regexp.MustCompile(`:\s*-?\d+\.?\d*([eE][+-]?\d+)?`), h.jsonNumberStyle},
-		// JSON booleans
-		{regexp.MustCompile(`:\s*(true|false)`), h.jsonBoolStyle},
-		// JSON null
-		{regexp.MustCompile(`:\s*null`), h.jsonNullStyle},
-	}
-
-	result := content
-	for _, pattern := range patterns {
-		result = pattern.regex.ReplaceAllStringFunc(result, func(match string) string {
-			if strings.Contains(match, ":") {
-				// For key-value pairs, only style the value part
-				parts := strings.SplitN(match, ":", 2)
-				if len(parts) == 2 {
-					if strings.Contains(match, `"`) && !strings.Contains(parts[1], `"`) {
-						// This is a key, style it differently
-						return h.jsonKeyStyle.Render(parts[0]) + ":" + parts[1]
-					}
-					return parts[0] + ":" + pattern.style.Render(parts[1])
-				}
-			}
-			return pattern.style.Render(match)
-		})
-	}
-
-	return result
-}
-
 // highlightHTML applies basic HTML syntax highlighting
 func (h *Highlighter) highlightHTML(content string) string {
 	// HTML tags
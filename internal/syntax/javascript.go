@@ -0,0 +1,95 @@
+package syntax
+
+import "strings"
+
+var javaScriptKeywords = map[string]bool{
+	"async": true, "await": true, "break": true, "case": true, "catch": true,
+	"class": true, "const": true, "continue": true, "debugger": true,
+	"default": true, "delete": true, "do": true, "else": true, "export": true,
+	"extends": true, "finally": true, "for": true, "function": true, "if": true,
+	"import": true, "in": true, "instanceof": true, "let": true, "new": true,
+	"of": true, "return": true, "static": true, "super": true, "switch": true,
+	"this": true, "throw": true, "try": true, "typeof": true, "var": true,
+	"void": true, "while": true, "with": true, "yield": true, "from": true,
+}
+
+// highlightJavaScript colours comments, strings, numbers, keywords, literals
+// and function calls
+func (h *Highlighter) highlightJavaScript(content string) string {
+	p := h.palette
+
+	var b strings.Builder
+	b.Grow(len(content) * 2)
+
+	n := len(content)
+	for i := 0; i < n; {
+		c := content[i]
+
+		switch {
+		case strings.HasPrefix(content[i:], "//"):
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				end = n
+			} else {
+				end += i
+			}
+			p.comment.write(&b, content[i:end])
+			i = end
+
+		case strings.HasPrefix(content[i:], "/*"):
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				end = n
+			} else {
+				end += i + 4
+			}
+			p.comment.write(&b, content[i:end])
+			i = end
+
+		case c == '"' || c == '\'' || c == '`':
+			end := scanQuoted(content, i, c)
+			p.str.write(&b, content[i:end])
+			i = end
+
+		case isDigit(c) || (c == '.' && i+1 < n && isDigit(content[i+1])):
+			end := i + 1
+			for end < n && (isLetter(content[end]) || isDigit(content[end]) || content[end] == '.' || content[end] == '_') {
+				end++
+			}
+			p.number.write(&b, content[i:end])
+			i = end
+
+		case isLetter(c) || c == '_' || c == '$':
+			end := i + 1
+			for end < n && (isLetter(content[end]) || isDigit(content[end]) || content[end] == '_' || content[end] == '$') {
+				end++
+			}
+			word := content[i:end]
+			switch {
+			case javaScriptKeywords[word]:
+				p.keyword.write(&b, word)
+			case word == "true" || word == "false":
+				p.boolean.write(&b, word)
+			case word == "null" || word == "undefined" || word == "NaN" || word == "Infinity":
+				p.null.write(&b, word)
+			case end < n && content[end] == '(':
+				p.attr.write(&b, word)
+			case isUpper(word[0]):
+				p.typeName.write(&b, word)
+			default:
+				b.WriteString(word)
+			}
+			i = end
+
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
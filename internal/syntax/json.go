@@ -0,0 +1,216 @@
+package syntax
+
+import "strings"
+
+// highlightJSON pretty-prints and colours a JSON body
+func (h *Highlighter) highlightJSON(content string) string {
+	return h.colorJSON(content, true)
+}
+
+// colorJSON lexes content in a single pass, colouring each token and, when
+// indent is set, re-indenting as it goes; otherwise it is laid out on one
+// line. Object key order and number formatting are kept as they appear in
+// the source. Invalid or truncated input never fails: unknown bytes are
+// passed through and unclosed containers simply end.
+func (h *Highlighter) colorJSON(content string, indent bool) string {
+	p := h.palette
+
+	var b strings.Builder
+	b.Grow(len(content) + len(content)/2)
+
+	depth := 0
+	// topLevelDone marks that a complete value has been written at depth 0,
+	// so a following value (e.g. newline-delimited JSON) starts a new line
+	topLevelDone := false
+
+	newline := func() {
+		if !indent {
+			return
+		}
+		b.WriteByte('\n')
+		for i := 0; i < depth; i++ {
+			b.WriteString("  ")
+		}
+	}
+
+	startValue := func() {
+		if depth == 0 && topLevelDone {
+			if indent {
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		topLevelDone = false
+	}
+
+	endValue := func() {
+		if depth == 0 {
+			topLevelDone = true
+		}
+	}
+
+	// Words that aren't JSON literals keep a single separating space so
+	// plain-text bodies mislabelled as JSON stay readable
+	invalidRun := false
+	writeInvalid := func(i int, text string) {
+		if invalidRun && i > 0 && isSpace(content[i-1]) {
+			b.WriteByte(' ')
+		}
+		p.invalid.write(&b, text)
+		invalidRun = true
+	}
+
+	n := len(content)
+	for i := 0; i < n; {
+		c := content[i]
+		if !isSpace(c) && c != '"' && !isWordByte(c) {
+			invalidRun = false
+		}
+
+		switch {
+		case isSpace(c):
+			i++
+
+		case c == '{' || c == '[':
+			startValue()
+			b.WriteByte(c)
+			i++
+
+			closer := byte('}')
+			if c == '[' {
+				closer = ']'
+			}
+			if j := skipSpace(content, i); j < n && content[j] == closer {
+				// Keep empty containers on one line
+				b.WriteByte(closer)
+				i = j + 1
+				endValue()
+				continue
+			}
+
+			depth++
+			if skipSpace(content, i) < n {
+				newline()
+			}
+
+		case c == '}' || c == ']':
+			if depth > 0 {
+				depth--
+			}
+			newline()
+			b.WriteByte(c)
+			i++
+			endValue()
+
+		case c == ',':
+			b.WriteByte(',')
+			i++
+			if !indent {
+				b.WriteByte(' ')
+			} else if skipSpace(content, i) < n {
+				newline()
+			}
+
+		case c == ':':
+			b.WriteString(": ")
+			i++
+
+		case c == '"':
+			invalidRun = false
+			end := scanString(content, i)
+			if j := skipSpace(content, end); j < n && content[j] == ':' {
+				p.key.write(&b, content[i:end])
+			} else {
+				startValue()
+				p.str.write(&b, content[i:end])
+				endValue()
+			}
+			i = end
+
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := scanNumber(content, i)
+			startValue()
+			p.number.write(&b, content[i:end])
+			endValue()
+			i = end
+
+		case isWordByte(c):
+			end := i
+			for end < n && isWordByte(content[end]) {
+				end++
+			}
+			word := content[i:end]
+			switch word {
+			case "true", "false":
+				startValue()
+				p.boolean.write(&b, word)
+				endValue()
+				invalidRun = false
+			case "null":
+				startValue()
+				p.null.write(&b, word)
+				endValue()
+				invalidRun = false
+			default:
+				writeInvalid(i, word)
+			}
+			i = end
+
+		default:
+			// Pass anything else through untouched, keeping multi-byte runes whole
+			end := i + 1
+			for end < n && content[end]&0xC0 == 0x80 {
+				end++
+			}
+			writeInvalid(i, content[i:end])
+			i = end
+		}
+	}
+
+	return b.String()
+}
+
+// scanString returns the index just past the string starting at i. An
+// unterminated string ends at the next line break or the end of input.
+func scanString(s string, i int) int {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		case '\n':
+			return j
+		}
+	}
+	return len(s)
+}
+
+func scanNumber(s string, i int) int {
+	j := i
+	for j < len(s) {
+		c := s[j]
+		if (c >= '0' && c <= '9') || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E' {
+			j++
+			continue
+		}
+		break
+	}
+	return j
+}
+
+func skipSpace(s string, i int) int {
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isWordByte(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
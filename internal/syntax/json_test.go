@@ -0,0 +1,96 @@
+package syntax
+
+import "testing"
+
+// markedHighlighter wraps each token role in a readable tag instead of an
+// escape sequence, so tests can see how the input was split up
+func markedHighlighter() *Highlighter {
+	mark := func(role string) sgr {
+		return sgr{on: "<" + role + ">", off: "</" + role + ">"}
+	}
+	return &Highlighter{palette: palette{
+		key:     mark("k"),
+		str:     mark("s"),
+		number:  mark("n"),
+		boolean: mark("b"),
+		null:    mark("z"),
+		invalid: mark("x"),
+	}}
+}
+
+func TestColorJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		indent  bool
+		want    string
+	}{
+		{
+			name:    "escaped quote and backslash",
+			content: `{"a\"b":"c\\"}`,
+			indent:  true,
+			want:    "{\n  <k>\"a\\\"b\"</k>: <s>\"c\\\\\"</s>\n}",
+		},
+		{
+			name:    "escaped quote before a colon stays in the string",
+			content: `["x\":y"]`,
+			want:    `[<s>"x\":y"</s>]`,
+		},
+		{
+			name:    "unicode escapes are kept as written",
+			content: `{"\u00e9":"\ud83d\ude00"}`,
+			want:    `{<k>"\u00e9"</k>: <s>"\ud83d\ude00"</s>}`,
+		},
+		{
+			name:    "multi-byte runes inside strings",
+			content: `{"naïve":"日本語 ✓"}`,
+			want:    `{<k>"naïve"</k>: <s>"日本語 ✓"</s>}`,
+		},
+		{
+			name:    "multi-byte runes outside strings pass through whole",
+			content: `[1, é]`,
+			want:    `[<n>1</n>, <x>é</x>]`,
+		},
+		{
+			name:    "numbers keep their source form",
+			content: `[-0.50, 1e+10, 2E-3]`,
+			want:    `[<n>-0.50</n>, <n>1e+10</n>, <n>2E-3</n>]`,
+		},
+		{
+			name:    "literals",
+			content: `[true,false,null]`,
+			want:    `[<b>true</b>, <b>false</b>, <z>null</z>]`,
+		},
+		{
+			name:    "empty containers stay on one line",
+			content: "{\"a\": [ ], \"b\": {}}",
+			indent:  true,
+			want:    "{\n  <k>\"a\"</k>: [],\n  <k>\"b\"</k>: {}\n}",
+		},
+		{
+			name:    "unterminated string ends at the line break",
+			content: "[\"abc\n1]",
+			want:    `[<s>"abc</s><n>1</n>]`,
+		},
+		{
+			name:    "newline-delimited values",
+			content: "{\"a\":1}\n{\"a\":2}",
+			indent:  true,
+			want:    "{\n  <k>\"a\"</k>: <n>1</n>\n}\n{\n  <k>\"a\"</k>: <n>2</n>\n}",
+		},
+		{
+			name:    "plain words keep their spacing",
+			content: "not found",
+			want:    "<x>not</x> <x>found</x>",
+		},
+	}
+
+	h := markedHighlighter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.colorJSON(tt.content, tt.indent); got != tt.want {
+				t.Errorf("colorJSON(%q) =\n%s\nwant\n%s", tt.content, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package syntax
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeadingRegex = regexp.MustCompile(`^\s{0,3}#{1,6}(\s|$)`)
+	markdownRuleRegex    = regexp.MustCompile(`^\s{0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+	markdownListRegex    = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])(\s+)(.*)$`)
+	markdownInlineRegex  = regexp.MustCompile("`[^`]+`|\\*\\*[^*]+\\*\\*|__[^_]+__|!?\\[[^\\]]*\\]\\([^)]*\\)|<https?://[^>]+>")
+)
+
+// highlightMarkdown colours headings, code, quotes, lists, emphasis and links
+func (h *Highlighter) highlightMarkdown(content string) string {
+	p := h.palette
+	lines := strings.Split(content, "\n")
+
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			lines[i] = p.code.render(line)
+			continue
+		}
+
+		switch {
+		case inFence:
+			lines[i] = p.code.render(line)
+		case markdownHeadingRegex.MatchString(line):
+			lines[i] = p.heading.render(line)
+		case strings.HasPrefix(trimmed, ">"):
+			lines[i] = p.comment.render(line)
+		case markdownRuleRegex.MatchString(line):
+			lines[i] = p.punct.render(line)
+		default:
+			if m := markdownListRegex.FindStringSubmatch(line); m != nil {
+				lines[i] = m[1] + p.keyword.render(m[2]) + m[3] + h.markdownInline(m[4])
+			} else {
+				lines[i] = h.markdownInline(line)
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// markdownInline colours code spans, strong emphasis and links in a line
+func (h *Highlighter) markdownInline(line string) string {
+	p := h.palette
+	return markdownInlineRegex.ReplaceAllStringFunc(line, func(match string) string {
+		switch match[0] {
+		case '`':
+			return p.code.render(match)
+		case '*', '_':
+			return p.emphasis.render(match)
+		default:
+			return p.link.render(match)
+		}
+	})
+}
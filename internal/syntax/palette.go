@@ -0,0 +1,63 @@
+package syntax
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sgr holds the escape sequences lipgloss wraps around text for a style, so
+// hot loops can emit them directly instead of calling Render per token
+type sgr struct {
+	on  string
+	off string
+}
+
+func newSGR(style lipgloss.Style) sgr {
+	rendered := style.Render("\x00")
+	on, off, found := strings.Cut(rendered, "\x00")
+	if !found {
+		return sgr{}
+	}
+	return sgr{on: on, off: off}
+}
+
+func (s sgr) write(b *strings.Builder, text string) {
+	if text == "" {
+		return
+	}
+	b.WriteString(s.on)
+	b.WriteString(text)
+	b.WriteString(s.off)
+}
+
+func (s sgr) render(text string) string {
+	if text == "" {
+		return ""
+	}
+	return s.on + text + s.off
+}
+
+// palette is the set of token roles shared by every highlighter
+type palette struct {
+	key      sgr
+	str      sgr
+	number   sgr
+	boolean  sgr
+	null     sgr
+	invalid  sgr
+	comment  sgr
+	keyword  sgr
+	typeName sgr
+	variable sgr
+	tag      sgr
+	attr     sgr
+	punct    sgr
+	heading  sgr
+	emphasis sgr
+	code     sgr
+	link     sgr
+
+	// columns colours successive CSV columns
+	columns []sgr
+}
@@ -0,0 +1,62 @@
+package syntax
+
+import (
+	"mime"
+	"strings"
+)
+
+// Func highlights a body of a particular media type
+type Func func(content string) string
+
+// Registry maps media types to highlighters. Besides exact media types it
+// understands RFC 6839 structured syntax suffixes, so a highlighter
+// registered for the "json" suffix also handles application/problem+json.
+type Registry struct {
+	exact  map[string]Func
+	suffix map[string]Func
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		exact:  make(map[string]Func),
+		suffix: make(map[string]Func),
+	}
+}
+
+// Register associates a media type such as "text/css" with fn
+func (r *Registry) Register(mediaType string, fn Func) {
+	r.exact[normalizeMediaType(mediaType)] = fn
+}
+
+// RegisterSuffix associates a structured syntax suffix such as "json" with fn
+func (r *Registry) RegisterSuffix(suffix string, fn Func) {
+	r.suffix[strings.ToLower(strings.TrimPrefix(suffix, "+"))] = fn
+}
+
+// Lookup finds the highlighter for a Content-Type header value. Exact media
+// types take precedence over suffix matches.
+func (r *Registry) Lookup(contentType string) (Func, bool) {
+	mediaType := normalizeMediaType(contentType)
+
+	if fn, ok := r.exact[mediaType]; ok {
+		return fn, true
+	}
+
+	if i := strings.LastIndex(mediaType, "+"); i >= 0 {
+		if fn, ok := r.suffix[mediaType[i+1:]]; ok {
+			return fn, true
+		}
+	}
+
+	return nil, false
+}
+
+// normalizeMediaType strips parameters and lowercases a Content-Type value
+func normalizeMediaType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
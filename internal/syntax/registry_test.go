@@ -0,0 +1,50 @@
+package syntax
+
+import "testing"
+
+func TestRegistryLookup(t *testing.T) {
+	r := NewRegistry()
+	r.Register("application/json", func(string) string { return "json" })
+	r.Register("application/vnd.custom+json", func(string) string { return "custom" })
+	r.RegisterSuffix("+json", func(string) string { return "suffix json" })
+	r.RegisterSuffix("XML", func(string) string { return "suffix xml" })
+
+	tests := []struct {
+		contentType string
+		want        string
+		found       bool
+	}{
+		{"application/json", "json", true},
+		{"Application/JSON; charset=utf-8", "json", true},
+		{"application/problem+json", "suffix json", true},
+		{"application/vnd.custom+json", "custom", true},
+		{"application/atom+xml", "suffix xml", true},
+		{"application/json; broken=\"", "json", true},
+		{"text/plain", "", false},
+		{"application/jsonx", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		fn, ok := r.Lookup(tt.contentType)
+		if ok != tt.found {
+			t.Errorf("Lookup(%q) found = %v, want %v", tt.contentType, ok, tt.found)
+			continue
+		}
+		if ok && fn("") != tt.want {
+			t.Errorf("Lookup(%q) = %q highlighter, want %q", tt.contentType, fn(""), tt.want)
+		}
+	}
+}
+
+func TestHighlightReplacesBuiltin(t *testing.T) {
+	h := NewHighlighter()
+	h.Register("application/json", func(content string) string { return "custom " + content })
+
+	if got := h.Highlight("{}", "application/json"); got != "custom {}" {
+		t.Errorf("Highlight with a replaced builtin = %q, want %q", got, "custom {}")
+	}
+	if got := h.Highlight("plain", "application/octet-stream"); got != "plain" {
+		t.Errorf("Highlight with an unknown type = %q, want the content unchanged", got)
+	}
+}
@@ -0,0 +1,61 @@
+package syntax
+
+import "strings"
+
+// highlightEventStream colours text/event-stream fields. JSON in data lines
+// is highlighted inline.
+func (h *Highlighter) highlightEventStream(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = h.highlightEventLine(strings.TrimSuffix(line, "\r"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// highlightEventLine colours a single "field: value" line of an event stream
+func (h *Highlighter) highlightEventLine(line string) string {
+	p := h.palette
+
+	if line == "" {
+		return line
+	}
+	if line[0] == ':' {
+		return p.comment.render(line)
+	}
+
+	field, value, hasValue := strings.Cut(line, ":")
+	if !hasValue {
+		return p.key.render(field)
+	}
+
+	var space string
+	if strings.HasPrefix(value, " ") {
+		space, value = " ", value[1:]
+	}
+
+	var styled string
+	switch field {
+	case "data":
+		styled = h.highlightEventData(value)
+	case "event":
+		styled = p.typeName.render(value)
+	case "id":
+		styled = p.variable.render(value)
+	case "retry":
+		styled = p.number.render(value)
+	default:
+		styled = value
+	}
+
+	return p.key.render(field) + p.punct.render(":") + space + styled
+}
+
+// highlightEventData colours the payload of an event's data field, treating
+// anything that looks like JSON as compact JSON
+func (h *Highlighter) highlightEventData(data string) string {
+	trimmed := strings.TrimSpace(data)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return h.colorJSON(data, false)
+	}
+	return h.palette.str.render(data)
+}
@@ -0,0 +1,161 @@
+package syntax
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// yamlKeyRegex splits "  - key: value" into indentation/dash, key, colon
+	// and value
+	yamlKeyRegex  = regexp.MustCompile(`^(\s*(?:-\s+)*)("(?:[^"\\]|\\.)*"|'(?:[^']|'')*'|[^\s#'"{}\[\],&*!|>%@` + "`" + `-][^#]*?|-[^\s#][^#]*?)(\s*:)(\s.*|$)`)
+	yamlListRegex = regexp.MustCompile(`^(\s*)(-)(\s.*|$)`)
+
+	yamlNumberRegex = regexp.MustCompile(`^[-+]?(\d[\d_]*(\.\d*)?([eE][-+]?\d+)?|\.\d+|0x[0-9a-fA-F]+|0o[0-7]+|\.inf|\.Inf|\.nan|\.NaN)$`)
+)
+
+// highlightYAML colours YAML line by line: keys, scalars by type, anchors,
+// tags, comments and block scalars
+func (h *Highlighter) highlightYAML(content string) string {
+	p := h.palette
+	lines := strings.Split(content, "\n")
+
+	// blockIndent is the indentation of the key that opened a | or > block
+	// scalar; deeper lines belong to the scalar. -1 means not in a block.
+	blockIndent := -1
+
+	for i, line := range lines {
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		trimmed := strings.TrimSpace(line)
+
+		if blockIndent >= 0 {
+			if trimmed == "" || indent > blockIndent {
+				lines[i] = p.str.render(line)
+				continue
+			}
+			blockIndent = -1
+		}
+
+		switch {
+		case trimmed == "":
+			continue
+		case trimmed == "---" || trimmed == "..." || strings.HasPrefix(trimmed, "--- "):
+			lines[i] = p.keyword.render(line)
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			lines[i] = p.comment.render(line)
+			continue
+		case strings.HasPrefix(trimmed, "%"):
+			lines[i] = p.keyword.render(line)
+			continue
+		}
+
+		var b strings.Builder
+		var value string
+
+		if m := yamlKeyRegex.FindStringSubmatch(line); m != nil {
+			h.writeYAMLDashes(&b, m[1])
+			p.key.write(&b, m[2])
+			p.punct.write(&b, m[3])
+			value = m[4]
+		} else if m := yamlListRegex.FindStringSubmatch(line); m != nil {
+			b.WriteString(m[1])
+			p.punct.write(&b, m[2])
+			value = m[3]
+		} else {
+			value = line
+		}
+
+		if h.writeYAMLValue(&b, value) {
+			blockIndent = indent
+		}
+		lines[i] = b.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// writeYAMLDashes writes leading indentation with any sequence dashes dimmed
+func (h *Highlighter) writeYAMLDashes(b *strings.Builder, prefix string) {
+	for _, r := range prefix {
+		if r == '-' {
+			h.palette.punct.write(b, "-")
+		} else {
+			b.WriteRune(r)
+		}
+	}
+}
+
+// writeYAMLValue colours a scalar and any trailing comment. It reports
+// whether the value opens a block scalar.
+func (h *Highlighter) writeYAMLValue(b *strings.Builder, value string) bool {
+	p := h.palette
+
+	body, comment := splitYAMLComment(value)
+	leading := body[:len(body)-len(strings.TrimLeft(body, " \t"))]
+	trailing := body[len(strings.TrimRight(body, " \t")):]
+	scalar := strings.TrimSpace(body)
+
+	b.WriteString(leading)
+
+	// Anchors, aliases and tags prefix the actual value
+	for scalar != "" && (scalar[0] == '&' || scalar[0] == '*' || scalar[0] == '!') {
+		word, rest, _ := strings.Cut(scalar, " ")
+		if scalar[0] == '!' {
+			p.typeName.write(b, word)
+		} else {
+			p.variable.write(b, word)
+		}
+		if rest != "" {
+			b.WriteByte(' ')
+		}
+		scalar = strings.TrimLeft(rest, " ")
+	}
+
+	block := false
+	switch {
+	case scalar == "":
+	case scalar[0] == '|' || scalar[0] == '>':
+		p.punct.write(b, scalar)
+		block = true
+	case scalar[0] == '"' || scalar[0] == '\'':
+		p.str.write(b, scalar)
+	case yamlNumberRegex.MatchString(scalar):
+		p.number.write(b, scalar)
+	default:
+		switch strings.ToLower(scalar) {
+		case "true", "false", "yes", "no", "on", "off":
+			p.boolean.write(b, scalar)
+		case "null", "~":
+			p.null.write(b, scalar)
+		default:
+			p.str.write(b, scalar)
+		}
+	}
+
+	b.WriteString(trailing)
+	p.comment.write(b, comment)
+
+	return block
+}
+
+// splitYAMLComment separates a trailing " # comment" that isn't inside quotes
+func splitYAMLComment(value string) (string, string) {
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || value[i-1] == ' ' || value[i-1] == '\t'):
+			quote = c
+		case c == '#' && (i == 0 || value[i-1] == ' ' || value[i-1] == '\t'):
+			return value[:i], value[i:]
+		}
+	}
+	return value, ""
+}
@@ -0,0 +1,167 @@
+package tabular
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pixperk/quest/internal/jsontree"
+)
+
+// Table is a grid of string cells with a header row
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// FromJSON builds a table from a homogeneous array: objects that all have
+// the same keys, holding only strings, numbers, booleans and nulls. Columns
+// follow the first object's key order.
+func FromJSON(root *jsontree.Node) (*Table, bool) {
+	if root == nil || root.Kind != jsontree.Array || len(root.Children) == 0 {
+		return nil, false
+	}
+
+	first := root.Children[0]
+	if first.Kind != jsontree.Object || len(first.Children) == 0 {
+		return nil, false
+	}
+	columnIndex := make(map[string]int, len(first.Children))
+	columns := make([]string, 0, len(first.Children))
+	for _, field := range first.Children {
+		if _, ok := columnIndex[field.Key]; ok {
+			return nil, false
+		}
+		columnIndex[field.Key] = len(columns)
+		columns = append(columns, field.Key)
+	}
+
+	rows := make([][]string, len(root.Children))
+	for i, element := range root.Children {
+		if element.Kind != jsontree.Object || len(element.Children) != len(columns) {
+			return nil, false
+		}
+		row := make([]string, len(columns))
+		seen := make([]bool, len(columns))
+		for _, field := range element.Children {
+			c, ok := columnIndex[field.Key]
+			if !ok || seen[c] || !isScalar(field) {
+				return nil, false
+			}
+			seen[c] = true
+			row[c] = cellValue(field)
+		}
+		rows[i] = row
+	}
+
+	return &Table{Columns: columns, Rows: rows}, true
+}
+
+// isScalar reports whether n is a flat value rather than an object or array
+func isScalar(n *jsontree.Node) bool {
+	return n.Kind != jsontree.Object && n.Kind != jsontree.Array
+}
+
+// cellValue shows a scalar as text, strings without their quotes
+func cellValue(n *jsontree.Node) string {
+	if n.Kind == jsontree.String {
+		var s string
+		if err := json.Unmarshal([]byte(n.Value), &s); err == nil {
+			return s
+		}
+	}
+	return n.Value
+}
+
+// FromCSV builds a table from CSV text, treating the first record as the
+// header row
+func FromCSV(data string) (*Table, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV has no header row")
+	}
+
+	columns := records[0]
+	rows := make([][]string, len(records)-1)
+	for i, record := range records[1:] {
+		// Pad or trim ragged records to the header width
+		row := make([]string, len(columns))
+		copy(row, record)
+		rows[i] = row
+	}
+
+	return &Table{Columns: columns, Rows: rows}, nil
+}
+
+// SortedRows returns the rows ordered by the given column. Cells that parse
+// as numbers compare numerically; everything else compares as text.
+func (t *Table) SortedRows(column int, descending bool) [][]string {
+	rows := make([][]string, len(t.Rows))
+	copy(rows, t.Rows)
+
+	if column < 0 || column >= len(t.Columns) {
+		return rows
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i][column], rows[j][column]
+		if descending {
+			a, b = b, a
+		}
+		return less(a, b)
+	})
+
+	return rows
+}
+
+func less(a, b string) bool {
+	an, aErr := strconv.ParseFloat(a, 64)
+	bn, bErr := strconv.ParseFloat(b, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return an < bn
+	case aErr == nil:
+		// Numbers sort before text
+		return true
+	case bErr == nil:
+		return false
+	default:
+		return strings.ToLower(a) < strings.ToLower(b)
+	}
+}
+
+// WriteCSV writes the given columns of rows, header first, as CSV
+func (t *Table) WriteCSV(w io.Writer, rows [][]string, columns []int) error {
+	writer := csv.NewWriter(w)
+
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = t.Columns[c]
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, c := range columns {
+			record[i] = row[c]
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
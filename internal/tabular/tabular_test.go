@@ -0,0 +1,66 @@
+package tabular
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pixperk/quest/internal/jsontree"
+)
+
+func TestFromJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		ok      bool
+		columns []string
+		rows    [][]string
+	}{
+		{
+			name:    "homogeneous",
+			json:    `[{"id": 1, "name": "a"}, {"name": "b", "id": 2}]`,
+			ok:      true,
+			columns: []string{"id", "name"},
+			rows:    [][]string{{"1", "a"}, {"2", "b"}},
+		},
+		{
+			name:    "scalars",
+			json:    `[{"s": "x", "n": 1.5, "b": true, "z": null}]`,
+			ok:      true,
+			columns: []string{"s", "n", "b", "z"},
+			rows:    [][]string{{"x", "1.5", "true", "null"}},
+		},
+		{name: "empty object", json: `[{}]`},
+		{name: "empty objects", json: `[{}, {}, {}]`},
+		{name: "empty array", json: `[]`},
+		{name: "not an array", json: `{"id": 1}`},
+		{name: "scalars in array", json: `[1, 2]`},
+		{name: "mixed elements", json: `[{"id": 1}, 2]`},
+		{name: "missing key", json: `[{"id": 1, "name": "a"}, {"id": 2}]`},
+		{name: "extra key", json: `[{"id": 1}, {"id": 2, "name": "b"}]`},
+		{name: "different keys", json: `[{"id": 1}, {"name": "b"}]`},
+		{name: "nested object", json: `[{"id": 1, "tags": {"a": 1}}]`},
+		{name: "nested array", json: `[{"id": 1, "tags": [1, 2]}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := jsontree.Parse([]byte(tt.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			table, ok := FromJSON(root)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if !reflect.DeepEqual(table.Columns, tt.columns) {
+				t.Errorf("columns = %q, want %q", table.Columns, tt.columns)
+			}
+			if !reflect.DeepEqual(table.Rows, tt.rows) {
+				t.Errorf("rows = %q, want %q", table.Rows, tt.rows)
+			}
+		})
+	}
+}
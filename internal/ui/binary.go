@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// renderBinarySummary describes a binary body for the Pretty view instead of
+// writing raw bytes to the terminal. The bytes themselves are in the Hex view.
+func (m Model) renderBinarySummary() string {
+	contentType := m.responseContentType
+	if contentType == "" {
+		contentType = "unknown"
+	}
+
+	lines := []string{
+		styles.HeaderStyle.Render("Binary response"),
+		styles.InfoStyle.Render("Type: ") + contentType,
+		styles.InfoStyle.Render("Size: ") + formatBytes(m.responseSize),
+	}
+	if m.hexFormat != "" {
+		lines = append(lines, styles.InfoStyle.Render("Detected: ")+m.hexFormat)
+	}
+	lines = append(lines, "", styles.HelpStyle.Render("Press v to inspect the bytes in the Hex view"))
+
+	return strings.Join(lines, "\n")
+}
+
+// renderProgress shows bytes received, percentage and rate while a body
+// downloads
+func (m Model) renderProgress() string {
+	p := m.progress
+
+	text := "Downloading " + formatBytes(p.BytesRead)
+	if p.Total > 0 {
+		text += " of " + formatBytes(p.Total)
+	}
+	text += fmt.Sprintf(" • %s/s", formatBytes(int64(p.Rate())))
+
+	if fraction := p.Fraction(); fraction >= 0 {
+		const width = 30
+		filled := int(min(fraction, 1) * width)
+		bar := lipgloss.NewStyle().Foreground(styles.Accent).Render(strings.Repeat("█", filled)) +
+			hexOffsetStyle.Render(strings.Repeat("░", width-filled))
+		return styles.InfoStyle.Render(text) + "\n" + bar + fmt.Sprintf(" %3.0f%%", fraction*100)
+	}
+
+	return styles.InfoStyle.Render(text)
+}
+
+// renderTruncationNotice explains when only part of a large body is shown
+func (m Model) renderTruncationNotice() string {
+	if m.responseFile == "" {
+		return ""
+	}
+	return styles.HelpStyle.Render(fmt.Sprintf(
+		"Showing the first %s of %s • full body at %s",
+		formatBytes(int64(len(m.responseBody))), formatBytes(m.responseSize), m.responseFile,
+	))
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "1.5 MB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// cycleContentType steps the raw body's Content-Type through the common
+// types and back to inferring it from the body
+func (m *Model) cycleContentType() {
+	next := 0
+	for i, contentType := range http.BodyContentTypes {
+		if contentType == m.bodyContentType {
+			next = i + 1
+		}
+	}
+	if next == len(http.BodyContentTypes) {
+		m.bodyContentType = ""
+		return
+	}
+	m.bodyContentType = http.BodyContentTypes[next]
+}
+
+// declaredContentType is the Content-Type the raw body will be sent with
+// and where it comes from: the Headers tab, a choice made on the Body tab,
+// the configured default headers or the body itself
+func (m Model) declaredContentType() (string, string) {
+	for name, value := range m.requestHeaders {
+		if strings.EqualFold(name, "Content-Type") {
+			return value, "from Headers tab"
+		}
+	}
+	if m.bodyContentType != "" {
+		return m.bodyContentType, "chosen"
+	}
+	for name, value := range m.httpClient.Headers {
+		if strings.EqualFold(name, "Content-Type") {
+			return value, "default header, " + m.configSource("headers.Content-Type")
+		}
+	}
+	return http.InferContentType(m.bodyTextarea.Value()), "detected"
+}
+
+// renderContentType shows the raw body's Content-Type, warning when the
+// body doesn't look like it
+func (m Model) renderContentType() string {
+	contentType, source := m.declaredContentType()
+	if contentType == "" {
+		return styles.HelpStyle.Render("Content-Type: none (empty body) • Ctrl+Y: Choose")
+	}
+
+	line := styles.HelpStyle.Render("Content-Type: ") + styles.InfoStyle.Render(contentType) +
+		styles.HelpStyle.Render(" ("+source+") • Ctrl+Y: Change")
+	if mismatch := http.ContentTypeMismatch(contentType, m.bodyTextarea.Value()); mismatch != "" {
+		line += "\n" + styles.ErrorStyle.Render("⚠ "+mismatch)
+	}
+	return line
+}
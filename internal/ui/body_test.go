@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeclaredContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		chosen      string
+		defaults    map[string]string
+		body        string
+		contentType string
+		source      string
+	}{
+		{"detected", nil, "", nil, `{"a": 1}`, "application/json", "detected"},
+		{"default header", nil, "", map[string]string{"Content-Type": "application/vnd.api+json"}, `{"a": 1}`, "application/vnd.api+json", "default header"},
+		{"chosen", nil, "text/plain", map[string]string{"Content-Type": "application/vnd.api+json"}, `{"a": 1}`, "text/plain", "chosen"},
+		{"headers tab", map[string]string{"content-type": "application/xml"}, "text/plain", map[string]string{"Content-Type": "application/json"}, `{}`, "application/xml", "from Headers tab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t)
+			m.requestHeaders = tt.headers
+			m.bodyContentType = tt.chosen
+			m.httpClient.Headers = tt.defaults
+			m.bodyTextarea.SetValue(tt.body)
+
+			contentType, source := m.declaredContentType()
+			if contentType != tt.contentType || !strings.HasPrefix(source, tt.source) {
+				t.Errorf("declaredContentType() = %q, %q, want %q, %q", contentType, source, tt.contentType, tt.source)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/pixperk/quest/internal/config"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// applyConfig sets up the client, key bindings and theme from the
+// effective configuration
+func (m *Model) applyConfig(cfg config.Config) error {
+	m.config = cfg
+	theme, err := loadTheme(cfg.Theme)
+	if err != nil {
+		return fmt.Errorf("%w (from %s)", err, m.configSource("theme"))
+	}
+	keys, err := DefaultKeys.Rebind(cfg.Keys)
+	if err != nil {
+		return err
+	}
+	if styles.NoColor() {
+		// lipgloss drops bold, italic and reverse along with colour under
+		// NO_COLOR, but the monochrome theme relies on them
+		lipgloss.SetColorProfile(termenv.ANSI)
+	}
+	m.applyTheme(theme)
+
+	m.environment = cfg.Environment
+	m.keys = keys
+	m.httpClient.Timeout = cfg.Timeout
+	m.httpClient.MaxBodyInMemory = cfg.MaxBodyInMemory
+	m.httpClient.UserAgent = cfg.UserAgent
+	m.httpClient.Headers = cfg.Headers
+	m.httpClient.TLS = expandTLSPaths(cfg.TLS)
+	m.httpClient.Proxy = cfg.Proxy
+	m.cookiesEnabled = cfg.Cookies
+	return nil
+}
+
+// configSource names the layer a setting came from
+func (m Model) configSource(key string) string {
+	for _, setting := range m.config.Settings {
+		if setting.Key == key {
+			return shortenHome(setting.Source)
+		}
+	}
+	return "default"
+}
+
+// updateConfig scrolls the configuration view
+func (m *Model) updateConfig(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "up", "k":
+		m.configOffset--
+	case "down", "j":
+		m.configOffset++
+	case "pgup":
+		m.configOffset -= 10
+	case "pgdown":
+		m.configOffset += 10
+	case "home", "g":
+		m.configOffset = 0
+	}
+	// The layers and settings with their headings and the blank between
+	lines := len(m.config.Layers) + len(m.config.Settings) + 3
+	m.configOffset = max(0, min(m.configOffset, lines-m.configHeight()))
+}
+
+// configHeight is how many lines of the configuration view fit
+func (m Model) configHeight() int {
+	return max(m.height-22, 5)
+}
+
+// renderConfigTab shows the layers configuration is read from and each
+// setting in effect with the layer that set it
+func (m Model) renderConfigTab() string {
+	rows := []string{
+		styles.HeaderStyle.Render("Configuration"),
+		styles.HelpStyle.Render("Environment: ") + styles.InfoStyle.Render(m.environment) +
+			styles.HelpStyle.Render(" • Theme: ") + styles.InfoStyle.Render(styles.Current().Name) +
+			styles.HelpStyle.Render(" • ↑/↓: Scroll • Later layers override earlier ones"),
+		"",
+	}
+
+	lines := []string{styles.StatusStyle.Render("Layers")}
+	for _, layer := range m.config.Layers {
+		if layer.Found {
+			lines = append(lines, "  "+styles.StatusStyle.Render("✓ ")+styles.InfoStyle.Render(shortenHome(layer.Name)))
+		} else {
+			lines = append(lines, styles.HelpStyle.Render("  · "+shortenHome(layer.Name)+" (nothing set)"))
+		}
+	}
+
+	keyWidth, valueWidth := 0, 0
+	for _, setting := range m.config.Settings {
+		keyWidth = max(keyWidth, lipgloss.Width(setting.Key))
+		valueWidth = max(valueWidth, lipgloss.Width(configValue(setting)))
+	}
+	valueWidth = min(valueWidth, max(m.width-keyWidth-40, 20))
+
+	lines = append(lines, "", styles.StatusStyle.Render("Settings"))
+	for _, setting := range m.config.Settings {
+		value := configValue(setting)
+		if lipgloss.Width(value) > valueWidth {
+			value = string([]rune(value)[:max(valueWidth-3, 0)]) + "..."
+		}
+		lines = append(lines, "  "+
+			styles.InfoStyle.Render(setting.Key+strings.Repeat(" ", keyWidth-lipgloss.Width(setting.Key)))+"  "+
+			styles.JsonStyle.Render(value+strings.Repeat(" ", valueWidth-lipgloss.Width(value)))+"  "+
+			styles.HelpStyle.Render(shortenHome(setting.Source)))
+	}
+
+	start := max(0, min(m.configOffset, len(lines)-m.configHeight()))
+	end := min(len(lines), start+m.configHeight())
+	return lipgloss.JoinVertical(lipgloss.Left, append(rows, lines[start:end]...)...)
+}
+
+// configValue is a setting as shown, with secrets hidden
+func configValue(setting config.Setting) string {
+	if setting.Secret {
+		return strings.Repeat("•", 8)
+	}
+	if setting.Value == "" {
+		return `""`
+	}
+	return setting.Value
+}
+
+// shortenHome writes a path under the home directory with ~
+func shortenHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" || !strings.HasPrefix(path, home+string(os.PathSeparator)) {
+		return path
+	}
+	return "~" + path[len(home):]
+}
@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// certExpiryWarning is how close to expiry a certificate gets flagged
+const certExpiryWarning = 30 * 24 * time.Hour
+
+// formatConnection describes the response's connection and the server's
+// certificate chain, for the Connection response sub-tab
+func (m Model) formatConnection() string {
+	conn := m.responseConnection
+	if conn == nil {
+		return styles.HelpStyle.Render("No connection details for this response")
+	}
+
+	lines := []string{styles.HeaderStyle.Render("Connection")}
+	if m.responseProto != "" {
+		lines = append(lines, field("Protocol", m.responseProto))
+	}
+	lines = append(lines, field("Remote", orNone(conn.RemoteAddr)), field("Local", orNone(conn.LocalAddr)))
+	if conn.Reused {
+		lines = append(lines, field("Reused", fmt.Sprintf("yes, idle for %s", conn.IdleTime.Round(time.Millisecond))))
+	} else {
+		lines = append(lines, field("Reused", "no, new connection"))
+	}
+
+	if conn.TLS == nil && len(conn.Certificates) == 0 {
+		lines = append(lines, "", styles.HelpStyle.Render("Plain HTTP, no TLS"))
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, "", styles.HeaderStyle.Render("TLS"))
+	if state := conn.TLS; state != nil && state.HandshakeComplete {
+		sni := state.ServerName
+		if sni == "" {
+			sni = "not sent"
+		}
+		lines = append(lines,
+			field("Version", tls.VersionName(state.Version)),
+			field("Cipher suite", tls.CipherSuiteName(state.CipherSuite)),
+			field("ALPN", orNone(state.NegotiatedProtocol)),
+			field("SNI", sni),
+			field("Resumed", yesNo(state.DidResume)))
+	} else {
+		lines = append(lines, styles.ErrorStyle.Render("Handshake failed"))
+	}
+	switch {
+	case conn.Verified():
+		lines = append(lines, field("Verified", styles.StatusStyle.Render("yes")))
+	case conn.TLS != nil && conn.TLS.HandshakeComplete:
+		lines = append(lines, field("Verified", styles.ErrorStyle.Render("no, verification is off")))
+	default:
+		lines = append(lines, field("Verified", styles.ErrorStyle.Render("no, verification failed")))
+	}
+
+	lines = append(lines, "", styles.HeaderStyle.Render(fmt.Sprintf("Certificate Chain (%d)", len(conn.Certificates))))
+	now := time.Now()
+	for i, cert := range conn.Certificates {
+		lines = append(lines, "", formatCertificate(i+1, cert, now))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatCertificate(n int, cert *x509.Certificate, now time.Time) string {
+	role := "intermediate"
+	switch {
+	case n == 1:
+		role = "leaf"
+	case cert.IsCA && cert.Subject.String() == cert.Issuer.String():
+		role = "root"
+	}
+
+	lines := []string{
+		fmt.Sprintf("%d. %s %s", n, styles.InfoStyle.Render(certName(cert)), styles.HelpStyle.Render("("+role+")")),
+		"   " + field("Subject", cert.Subject.String()),
+	}
+	if sans := certSANs(cert); len(sans) > 0 {
+		lines = append(lines, "   "+field("SANs", strings.Join(sans, ", ")))
+	}
+	lines = append(lines,
+		"   "+field("Issuer", cert.Issuer.String()),
+		"   "+field("Valid", cert.NotBefore.Format("2006-01-02")+" → "+cert.NotAfter.Format("2006-01-02")+"  "+certExpiry(cert, now)),
+		"   "+field("SHA-256", fmt.Sprintf("%X", sha256.Sum256(cert.Raw))),
+	)
+	return strings.Join(lines, "\n")
+}
+
+// certExpiry flags a certificate that has expired, expires soon or isn't
+// valid yet
+func certExpiry(cert *x509.Certificate, now time.Time) string {
+	switch left := cert.NotAfter.Sub(now); {
+	case now.Before(cert.NotBefore):
+		return styles.ErrorStyle.Render("✖ not valid until " + cert.NotBefore.Format(time.RFC1123))
+	case left <= 0:
+		return styles.ErrorStyle.Render(fmt.Sprintf("✖ expired %s ago", days(-left)))
+	case left < certExpiryWarning:
+		return lipgloss.NewStyle().Foreground(styles.Warning).Bold(true).Render(fmt.Sprintf("⚠ expires in %s", days(left)))
+	default:
+		return styles.StatusStyle.Render(fmt.Sprintf("✓ %s left", days(left)))
+	}
+}
+
+// renderCertificateWarning notes, above the response, a certificate in the
+// chain that has expired or expires soon
+func (m Model) renderCertificateWarning() string {
+	if m.responseConnection == nil {
+		return ""
+	}
+	now := time.Now()
+	for _, cert := range m.responseConnection.Certificates {
+		if now.Before(cert.NotBefore) || cert.NotAfter.Sub(now) < certExpiryWarning {
+			return certExpiry(cert, now) + styles.HelpStyle.Render(" "+certName(cert)+" • Connection tab shows the chain")
+		}
+	}
+	return ""
+}
+
+// certName is the certificate's common name, else its first SAN
+func certName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if sans := certSANs(cert); len(sans) > 0 {
+		return sans[0]
+	}
+	return cert.Subject.String()
+}
+
+func certSANs(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return append(sans, cert.EmailAddresses...)
+}
+
+func days(d time.Duration) string {
+	n := int(d.Hours() / 24)
+	if n == 1 {
+		return "1 day"
+	}
+	if n == 0 {
+		return d.Round(time.Minute).String()
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+func field(name, value string) string {
+	return styles.HelpStyle.Render(fmt.Sprintf("%-13s", name)) + styles.JsonStyle.Render(value)
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+func yesNo(yes bool) string {
+	if yes {
+		return "yes"
+	}
+	return "no"
+}
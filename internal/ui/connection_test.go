@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCertExpiry(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		notBefore time.Time
+		notAfter  time.Time
+		want      string
+	}{
+		{"valid", now.AddDate(0, -1, 0), now.AddDate(0, 0, 90), "✓ 90 days left"},
+		{"expires soon", now.AddDate(0, -1, 0), now.AddDate(0, 0, 1), "⚠ expires in 1 day"},
+		{"expires within the day", now.AddDate(0, -1, 0), now.Add(90 * time.Minute), "⚠ expires in 1h30m0s"},
+		{"expired", now.AddDate(0, -1, 0), now.AddDate(0, 0, -3), "✖ expired 3 days ago"},
+		{"not yet valid", now.AddDate(0, 0, 1), now.AddDate(0, 1, 0), "✖ not valid until Sun, 02 Jun 2024"},
+	}
+
+	for _, tt := range tests {
+		cert := &x509.Certificate{NotBefore: tt.notBefore, NotAfter: tt.notAfter}
+		if got := certExpiry(cert, now); !strings.Contains(got, tt.want) {
+			t.Errorf("%s: certExpiry = %q, want it to contain %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCertName(t *testing.T) {
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		want string
+	}{
+		{"common name", &x509.Certificate{Subject: pkix.Name{CommonName: "api.test"}, DNSNames: []string{"other.test"}}, "api.test"},
+		{"first SAN", &x509.Certificate{DNSNames: []string{"a.test", "b.test"}}, "a.test"},
+		{"IP SAN", &x509.Certificate{IPAddresses: []net.IP{net.ParseIP("10.0.0.1")}}, "10.0.0.1"},
+		{"subject", &x509.Certificate{Subject: pkix.Name{Organization: []string{"Quest"}}}, "O=Quest"},
+	}
+
+	for _, tt := range tests {
+		if got := certName(tt.cert); got != tt.want {
+			t.Errorf("%s: certName = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// cookieFile holds the cookie jar of every environment, next to the saved
+// requests in .quest
+const cookieFile = ".quest-cookies"
+
+// loadCookieJar loads the environment's jar, reporting a damaged cookie
+// file rather than overwriting it later
+func (m *Model) loadCookieJar() {
+	jar, err := http.LoadCookieJar(cookieFile, m.environment)
+	m.cookieJar = jar
+	if err != nil {
+		m.notice = styles.ErrorStyle.Render("Cookies: " + err.Error())
+	}
+	m.applyCookieJar()
+}
+
+// applyCookieJar attaches the jar to the client while cookies are on
+func (m *Model) applyCookieJar() {
+	m.httpClient.Jar = nil
+	if m.cookiesEnabled {
+		m.httpClient.Jar = m.cookieJar
+	}
+}
+
+func (m *Model) saveCookies() {
+	if err := http.SaveCookieJar(cookieFile, m.environment, m.cookieJar); err != nil {
+		m.notice = styles.ErrorStyle.Render("Cookies: " + err.Error())
+	}
+}
+
+// updateCookies handles the Cookies tab: moving through the jar, editing
+// values and deleting cookies one at a time, by domain or all at once
+func (m *Model) updateCookies(msg tea.KeyMsg) tea.Cmd {
+	cookies := m.cookieJar.All()
+
+	switch msg.String() {
+	case "up", "k":
+		m.cookieIndex = max(m.cookieIndex-1, 0)
+	case "down", "j":
+		m.cookieIndex = min(m.cookieIndex+1, max(len(cookies)-1, 0))
+	case "t":
+		m.cookiesEnabled = !m.cookiesEnabled
+		m.applyCookieJar()
+	case "C":
+		m.cookieJar.Clear()
+		m.saveCookies()
+		m.notice = styles.InfoStyle.Render("Cleared all cookies for " + m.environment)
+	}
+	if len(cookies) == 0 {
+		return nil
+	}
+
+	m.cookieIndex = min(m.cookieIndex, len(cookies)-1)
+	selected := cookies[m.cookieIndex]
+	switch msg.String() {
+	case "enter", "e":
+		m.editingCookie = true
+		m.cookieInput.SetValue(selected.Value)
+		m.cookieInput.CursorEnd()
+		return m.cookieInput.Focus()
+	case "d":
+		m.cookieJar.Delete(selected)
+		m.saveCookies()
+		m.notice = styles.InfoStyle.Render(fmt.Sprintf("Deleted cookie %s from %s", selected.Name, selected.Domain))
+	case "D":
+		m.cookieJar.DeleteDomain(selected.Domain)
+		m.saveCookies()
+		m.notice = styles.InfoStyle.Render("Deleted all cookies for " + selected.Domain)
+	}
+	return nil
+}
+
+// updateCookieEdit handles the value editor of the selected cookie
+func (m Model) updateCookieEdit(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.editingCookie = false
+		m.cookieInput.Blur()
+		return m, nil
+	case "enter":
+		cookies := m.cookieJar.All()
+		if m.cookieIndex < len(cookies) {
+			cookie := cookies[m.cookieIndex]
+			cookie.Value = m.cookieInput.Value()
+			m.cookieJar.Set(cookie)
+			m.saveCookies()
+		}
+		m.editingCookie = false
+		m.cookieInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.cookieInput, cmd = m.cookieInput.Update(msg)
+	return m, cmd
+}
+
+// renderCookiesTab lists the jar grouped by domain
+func (m Model) renderCookiesTab() string {
+	state := styles.InfoStyle.Render("on")
+	if !m.cookiesEnabled {
+		state = styles.ErrorStyle.Render("off")
+	}
+	rows := []string{
+		styles.HeaderStyle.Render("Cookies"),
+		styles.HelpStyle.Render("Environment: ") + styles.InfoStyle.Render(m.environment) +
+			styles.HelpStyle.Render(" • Jar: ") + state +
+			styles.HelpStyle.Render(" • Stored in "+cookieFile),
+		styles.HelpStyle.Render("↑/↓: Select • Enter/e: Edit value • d: Delete • D: Delete domain • C: Clear all • t: Turn jar on/off"),
+		"",
+	}
+
+	cookies := m.cookieJar.All()
+	if len(cookies) == 0 {
+		rows = append(rows, styles.HelpStyle.Render("No cookies stored yet"))
+		return lipgloss.JoinVertical(lipgloss.Left, rows...)
+	}
+
+	// Keep the selection in view, counting domain headings as lines
+	height := max(m.height-22, 5)
+	var lines []string
+	selectedLine := 0
+	domain := ""
+	for i, cookie := range cookies {
+		if cookie.Domain != domain {
+			domain = cookie.Domain
+			lines = append(lines, styles.StatusStyle.Render(domain))
+		}
+		if i == m.cookieIndex {
+			selectedLine = len(lines)
+		}
+		lines = append(lines, m.renderCookie(cookie, i == m.cookieIndex))
+	}
+	start := max(0, min(selectedLine-height/2, len(lines)-height))
+	end := min(len(lines), start+height)
+
+	rows = append(rows, lines[start:end]...)
+	if m.editingCookie {
+		rows = append(rows, "", styles.FocusedStyle.Render(m.cookieInput.View()), styles.HelpStyle.Render("Enter: Save • Esc: Cancel"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m Model) renderCookie(cookie http.Cookie, selected bool) string {
+	value := cookie.Value
+	if len(value) > 40 {
+		value = value[:37] + "..."
+	}
+
+	prefix, name := "  ", styles.InfoStyle.Render(cookie.Name)
+	if selected {
+		prefix = styles.InfoStyle.Render("▸ ")
+		name = styles.InfoStyle.Copy().Bold(true).Render(cookie.Name)
+	}
+	return prefix + name + "=" + styles.JsonStyle.Render(value) + " " + styles.HelpStyle.Render(cookieAttributes(cookie))
+}
+
+// cookieAttributes summarises a stored cookie's scope, flags and expiry
+func cookieAttributes(cookie http.Cookie) string {
+	attrs := []string{cookie.Path}
+	if !cookie.HostOnly {
+		attrs = append(attrs, "subdomains")
+	}
+	if cookie.Secure {
+		attrs = append(attrs, "Secure")
+	}
+	if cookie.HTTPOnly {
+		attrs = append(attrs, "HttpOnly")
+	}
+	if cookie.SameSite != "" {
+		attrs = append(attrs, "SameSite="+cookie.SameSite)
+	}
+	if cookie.Expires.IsZero() {
+		attrs = append(attrs, "session")
+	} else {
+		attrs = append(attrs, "expires in "+time.Until(cookie.Expires).Round(time.Minute).String())
+	}
+	return strings.Join(attrs, " • ")
+}
+
+// formatSetCookies lists the cookies the response set, for the Cookies
+// response sub-tab
+func (m Model) formatSetCookies(cookies []http.SetCookie) string {
+	if len(cookies) == 0 {
+		return styles.HelpStyle.Render("The response didn't set any cookies")
+	}
+
+	stored := "Stored in the " + m.environment + " jar"
+	if !m.cookiesEnabled {
+		stored = "Not stored: the cookie jar is off (Cookies tab, t)"
+	}
+	lines := []string{styles.HelpStyle.Render(stored)}
+
+	url := ""
+	for _, set := range cookies {
+		if set.URL != url {
+			url = set.URL
+			lines = append(lines, "", styles.HeaderStyle.Render(url))
+		}
+		c := set.Cookie
+		var attrs []string
+		if c.Domain != "" {
+			attrs = append(attrs, "Domain="+c.Domain)
+		}
+		if c.Path != "" {
+			attrs = append(attrs, "Path="+c.Path)
+		}
+		switch {
+		case c.MaxAge < 0:
+			attrs = append(attrs, "deleted")
+		case c.MaxAge > 0:
+			attrs = append(attrs, fmt.Sprintf("Max-Age=%d", c.MaxAge))
+		case !c.Expires.IsZero():
+			attrs = append(attrs, "Expires="+c.Expires.Format(time.RFC1123))
+		}
+		if c.Secure {
+			attrs = append(attrs, "Secure")
+		}
+		if c.HttpOnly {
+			attrs = append(attrs, "HttpOnly")
+		}
+		lines = append(lines, styles.InfoStyle.Render(c.Name)+"="+styles.JsonStyle.Render(c.Value)+" "+
+			styles.HelpStyle.Render(strings.Join(attrs, " • ")))
+	}
+	return strings.Join(lines, "\n")
+}
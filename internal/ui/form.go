@@ -0,0 +1,285 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// maxPathSuggestions caps how many path completions are listed
+const maxPathSuggestions = 6
+
+var bodyModeNames = []struct {
+	mode http.BodyMode
+	name string
+}{
+	{http.RawBody, "Raw"},
+	{http.MultipartBody, "Multipart"},
+	{http.URLEncodedBody, "URL-encoded"},
+}
+
+func newFormEditors() (textinput.Model, textinput.Model, textinput.Model) {
+	name := textinput.New()
+	name.Placeholder = "Field name"
+	name.Width = 20
+
+	value := textinput.New()
+	value.Placeholder = "Value"
+	value.Width = 35
+
+	contentType := textinput.New()
+	contentType.Placeholder = "Content type (optional)"
+	contentType.Width = 20
+
+	return name, value, contentType
+}
+
+// bodyModesAvailable reports whether the selected method sends a plain
+// HTTP body, which is the only kind that can be a form
+func (m Model) bodyModesAvailable() bool {
+	switch m.getSelectedMethod() {
+	case WebSocketMethod, GraphQLMethod, GRPCMethod, JSONRPCMethod:
+		return false
+	}
+	return true
+}
+
+func (m Model) formMode() bool {
+	return m.bodyMode != http.RawBody && m.bodyModesAvailable()
+}
+
+// formInputCount is how many inputs Tab cycles through; only multipart
+// parts have a content type
+func (m Model) formInputCount() int {
+	if m.bodyMode == http.MultipartBody {
+		return 3
+	}
+	return 2
+}
+
+func (m *Model) cycleBodyMode() {
+	m.bodyMode = http.BodyMode((int(m.bodyMode) + 1) % len(bodyModeNames))
+	m.focused = 0
+	m.updateFocus()
+}
+
+// toggleFormFile switches the field being edited between a text value and
+// a file to upload
+func (m *Model) toggleFormFile() {
+	m.formFile = !m.formFile
+	m.formValue.ShowSuggestions = m.formFile
+	if m.formFile {
+		m.formValue.Placeholder = "Path to file"
+		m.refreshPathSuggestions()
+	} else {
+		m.formValue.Placeholder = "Value"
+		m.formPaths = nil
+	}
+}
+
+// addFormField adds the field being edited to the form and clears the
+// inputs for the next one
+func (m *Model) addFormField() {
+	field := http.FormField{
+		Name:        strings.TrimSpace(m.formName.Value()),
+		Value:       m.formValue.Value(),
+		File:        m.formFile,
+		ContentType: strings.TrimSpace(m.formType.Value()),
+	}
+	if field.Name == "" {
+		m.notice = styles.ErrorStyle.Render("Form: field name is required")
+		return
+	}
+	if m.bodyMode != http.MultipartBody {
+		field.ContentType = ""
+	}
+
+	if field.File {
+		path, err := expandHome(strings.TrimSpace(field.Value))
+		if err == nil {
+			_, err = os.Stat(path)
+		}
+		if err != nil {
+			m.notice = styles.ErrorStyle.Render("Form: " + err.Error())
+			return
+		}
+		field.Value = path
+	}
+
+	m.formFields = append(m.formFields, field)
+	m.formName.SetValue("")
+	m.formValue.SetValue("")
+	m.formType.SetValue("")
+	m.formPaths = nil
+	m.focused = 0
+	m.updateFocus()
+}
+
+// updateFormEditors passes msg to the focused form input, keeping path
+// completions in step with what has been typed
+func (m *Model) updateFormEditors(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	switch m.focused {
+	case 0:
+		m.formName, cmd = m.formName.Update(msg)
+	case 1:
+		m.formValue, cmd = m.formValue.Update(msg)
+		if m.formFile {
+			m.refreshPathSuggestions()
+		}
+	default:
+		m.formType, cmd = m.formType.Update(msg)
+	}
+	return cmd
+}
+
+func (m *Model) refreshPathSuggestions() {
+	m.formPaths = pathSuggestions(m.formValue.Value())
+	m.formValue.SetSuggestions(m.formPaths)
+}
+
+// formPathCompletable reports whether Tab should complete the file path
+// being typed rather than move to the next input
+func (m Model) formPathCompletable() bool {
+	if !m.formMode() || !m.formFile || m.focused != 1 || len(m.formPaths) == 0 {
+		return false
+	}
+	return len(m.formPaths) > 1 || m.formPaths[0] != m.formValue.Value()
+}
+
+// pathSuggestions lists the entries of the directory being typed whose
+// names start with the rest of the path. Directories end in a slash so
+// completing one moves straight on to its contents.
+func pathSuggestions(value string) []string {
+	if value == "" {
+		return nil
+	}
+	prefix := value[:strings.LastIndex(value, "/")+1]
+	base := value[len(prefix):]
+
+	dir := prefix
+	if dir == "" {
+		dir = "."
+	}
+	dir, err := expandHome(dir)
+	if err != nil {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var suggestions []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".")) {
+			continue
+		}
+		if entry.IsDir() {
+			name += "/"
+		}
+		suggestions = append(suggestions, prefix+name)
+	}
+	return suggestions
+}
+
+// renderBodyModes renders the body mode selector
+func (m Model) renderBodyModes() string {
+	var modes []string
+	for _, mode := range bodyModeNames {
+		if mode.mode == m.bodyMode {
+			modes = append(modes, styles.InfoStyle.Render("["+mode.name+"]"))
+		} else {
+			modes = append(modes, styles.HelpStyle.Render(mode.name))
+		}
+	}
+	return styles.HelpStyle.Render("Mode: ") + strings.Join(modes, "  ") + styles.HelpStyle.Render("  (Ctrl+B to change)")
+}
+
+// renderFormBody renders the form field editor and the fields added so far
+func (m Model) renderFormBody() string {
+	inputs := []textinput.Model{m.formName, m.formValue, m.formType}[:m.formInputCount()]
+	var views []string
+	for i, input := range inputs {
+		style := styles.BlurredStyle
+		if i == m.focused {
+			style = styles.FocusedStyle
+		}
+		if i > 0 {
+			views = append(views, "  ")
+		}
+		views = append(views, style.Render(input.View()))
+	}
+
+	kind := "Text field"
+	if m.formFile {
+		kind = "File field"
+	}
+	rows := []string{
+		styles.HeaderStyle.Render("Request Body"),
+		m.renderBodyModes(),
+		"",
+		styles.InfoStyle.Render(kind) + styles.HelpStyle.Render(" (Ctrl+U to switch)"),
+		lipgloss.JoinHorizontal(lipgloss.Left, views...),
+	}
+
+	if m.formFile && m.focused == 1 && len(m.formPaths) > 0 {
+		current, start := "", 0
+		if m.formPathCompletable() {
+			current = m.formValue.CurrentSuggestion()
+			for i, path := range m.formPaths {
+				if path == current {
+					start = max(0, i-maxPathSuggestions+1)
+				}
+			}
+		}
+		end := min(len(m.formPaths), start+maxPathSuggestions)
+		for _, path := range m.formPaths[start:end] {
+			if path == current {
+				rows = append(rows, styles.InfoStyle.Render("▸ "+path))
+			} else {
+				rows = append(rows, styles.HelpStyle.Render("  "+path))
+			}
+		}
+		if hidden := len(m.formPaths) - (end - start); hidden > 0 {
+			rows = append(rows, styles.HelpStyle.Render(fmt.Sprintf("  … %d more", hidden)))
+		}
+	}
+
+	help := "Ctrl+A: Add field • Ctrl+X: Clear all • Tab: Switch fields"
+	if m.formFile {
+		help = "Ctrl+A: Add field • Ctrl+X: Clear all • Tab: Complete path or switch fields • ↑/↓: Cycle paths"
+	}
+	rows = append(rows, "", styles.HelpStyle.Render(help), "")
+
+	if len(m.formFields) == 0 {
+		rows = append(rows, styles.HelpStyle.Render("No form fields added yet"))
+		return lipgloss.JoinVertical(lipgloss.Left, rows...)
+	}
+
+	rows = append(rows, styles.HeaderStyle.Render(fmt.Sprintf("Fields (%d):", len(m.formFields))))
+	for _, field := range m.formFields {
+		line := styles.InfoStyle.Render(field.Name) + ": "
+		switch {
+		case field.File && m.bodyMode == http.MultipartBody:
+			line += "📎 " + styles.JsonStyle.Render(field.Value) + " " + styles.HelpStyle.Render("("+http.FileContentType(field)+")")
+		case field.File:
+			line += "📎 " + styles.JsonStyle.Render(field.Value) + " " + styles.HelpStyle.Render("(contents)")
+		default:
+			line += styles.JsonStyle.Render(field.Value)
+			if field.ContentType != "" && m.bodyMode == http.MultipartBody {
+				line += " " + styles.HelpStyle.Render("("+field.ContentType+")")
+			}
+		}
+		rows = append(rows, line)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
@@ -0,0 +1,348 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/graphql"
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// GraphQLMethod is the pseudo-method that selects GraphQL mode. Requests
+// are sent as POSTs with a JSON envelope built from the query and variables
+// editors.
+const GraphQLMethod = "GRAPHQL"
+
+// maxCompletions limits how many completion candidates are listed at once
+const maxCompletions = 8
+
+// maxShownGraphQLErrors limits how many validation errors are listed
+const maxShownGraphQLErrors = 3
+
+var (
+	completionStyle         lipgloss.Style
+	completionSelectedStyle lipgloss.Style
+)
+
+func init() {
+	styles.OnApply(func() {
+		completionStyle = lipgloss.NewStyle().Foreground(styles.Text)
+		completionSelectedStyle = styles.SelectedStyle()
+	})
+}
+
+func newGraphQLEditors() (textarea.Model, textarea.Model) {
+	query := textarea.New()
+	query.Placeholder = "query {\n  user(id: 1) {\n    name\n  }\n}"
+	query.ShowLineNumbers = true
+
+	variables := textarea.New()
+	variables.Placeholder = `{"id": 1}`
+	variables.ShowLineNumbers = false
+
+	return query, variables
+}
+
+func (m Model) graphQLMode() bool {
+	return m.getSelectedMethod() == GraphQLMethod
+}
+
+// buildGraphQLRequest wraps the query and variables in a POST envelope
+func (m Model) buildGraphQLRequest(query, variables string) (http.Request, error) {
+	body, err := graphql.NewEnvelope(query, variables)
+	if err != nil {
+		return http.Request{}, err
+	}
+
+	headers := make(map[string]string, len(m.requestHeaders)+2)
+	headers["Content-Type"] = "application/json"
+	headers["Accept"] = "application/graphql-response+json, application/json"
+	for k, v := range m.requestHeaders {
+		headers[k] = v
+	}
+
+	return m.applyRequestOptions(http.Request{
+		Method:  "POST",
+		URL:     m.urlInput.Value(),
+		Headers: headers,
+		Body:    string(body),
+	}), nil
+}
+
+// fetchSchema runs the introspection query against the current URL
+func (m Model) fetchSchema() (Model, tea.Cmd) {
+	req, err := m.buildGraphQLRequest(graphql.IntrospectionQuery, "")
+	if err != nil {
+		m.notice = styles.ErrorStyle.Render("Schema fetch failed: " + err.Error())
+		return m, nil
+	}
+
+	m.graphSchemaLoading = true
+	m.notice = styles.InfoStyle.Render("Fetching schema from " + req.URL + "...")
+
+	client := m.httpClient
+	return m, func() tea.Msg {
+		resp := client.SendRequest(req)
+		if resp.Error != nil {
+			return SchemaMessage{Err: resp.Error}
+		}
+		if resp.StatusCode >= 400 && resp.Body == "" {
+			return SchemaMessage{Err: fmt.Errorf("server answered %d", resp.StatusCode)}
+		}
+		schema, err := graphql.ParseSchema([]byte(resp.Body))
+		return SchemaMessage{Schema: schema, Err: err}
+	}
+}
+
+func (m *Model) handleSchema(msg SchemaMessage) {
+	m.graphSchemaLoading = false
+	if msg.Err != nil {
+		m.notice = styles.ErrorStyle.Render("Schema fetch failed: " + msg.Err.Error())
+		return
+	}
+	m.graphSchema = msg.Schema
+	m.notice = styles.StatusStyle.Render(fmt.Sprintf("Loaded schema with %d types", len(msg.Schema.Types)))
+	m.validateGraphQL()
+}
+
+// updateGraphQLEditors passes a message to the focused editor, re-validating
+// the query as it changes
+func (m *Model) updateGraphQLEditors(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	if m.focused == 0 {
+		before := m.graphQuery.Value()
+		m.graphQuery, cmd = m.graphQuery.Update(msg)
+		if m.graphQuery.Value() != before {
+			m.validateGraphQL()
+		}
+		if m.graphSuggestions != nil {
+			m.refreshCompletion()
+		}
+	} else {
+		m.graphVariables, cmd = m.graphVariables.Update(msg)
+	}
+	return cmd
+}
+
+// updateCompletion handles navigation keys while the completion list is
+// open. It reports whether the key was consumed.
+func (m *Model) updateCompletion(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "up":
+		m.graphSuggestIndex = (m.graphSuggestIndex - 1 + len(m.graphSuggestions)) % len(m.graphSuggestions)
+	case "down":
+		m.graphSuggestIndex = (m.graphSuggestIndex + 1) % len(m.graphSuggestions)
+	case "enter", "tab":
+		m.acceptCompletion()
+	case "esc":
+		m.graphSuggestions = nil
+	default:
+		return false
+	}
+	return true
+}
+
+// validateGraphQL checks the query, against the schema when one is loaded
+// and for syntax only otherwise
+func (m *Model) validateGraphQL() {
+	query := m.graphQuery.Value()
+	if strings.TrimSpace(query) == "" {
+		m.graphErrors = nil
+		return
+	}
+
+	if m.graphSchema != nil {
+		m.graphErrors = graphql.Validate(m.graphSchema, query)
+		return
+	}
+
+	m.graphErrors = nil
+	if _, err := graphql.Parse(query); err != nil {
+		if e, ok := err.(*graphql.Error); ok {
+			m.graphErrors = []*graphql.Error{e}
+		}
+	}
+}
+
+// openCompletion lists what can be typed at the cursor
+func (m *Model) openCompletion() {
+	if m.graphSchema == nil {
+		m.notice = styles.HelpStyle.Render("Fetch the schema with Ctrl+G to enable completion")
+		return
+	}
+	m.refreshCompletion()
+	if len(m.graphSuggestions) == 0 {
+		m.graphSuggestions = nil
+		m.notice = styles.HelpStyle.Render("No completions here")
+	}
+}
+
+func (m *Model) refreshCompletion() {
+	suggestions, prefix := graphql.Complete(m.graphSchema, m.graphQuery.Value(), m.graphCursorOffset())
+	if len(suggestions) == 0 {
+		m.graphSuggestions = nil
+		return
+	}
+	m.graphSuggestions = suggestions
+	m.graphPrefix = prefix
+	m.graphSuggestIndex = min(m.graphSuggestIndex, len(suggestions)-1)
+}
+
+// acceptCompletion inserts the rest of the selected suggestion
+func (m *Model) acceptCompletion() {
+	suggestion := m.graphSuggestions[m.graphSuggestIndex]
+	m.graphQuery.InsertString(strings.TrimPrefix(suggestion.Label, m.graphPrefix))
+	m.graphSuggestions = nil
+	m.graphSuggestIndex = 0
+	m.validateGraphQL()
+}
+
+// graphCursorOffset converts the query editor's cursor to a byte offset
+func (m Model) graphCursorOffset() int {
+	lines := strings.Split(m.graphQuery.Value(), "\n")
+	row := min(m.graphQuery.Line(), len(lines)-1)
+
+	offset := 0
+	for _, line := range lines[:row] {
+		offset += len(line) + 1
+	}
+
+	info := m.graphQuery.LineInfo()
+	col := min(info.StartColumn+info.CharOffset, len([]rune(lines[row])))
+	return offset + len(string([]rune(lines[row])[:col]))
+}
+
+// renderGraphQLBody renders the query and variables editors, validation
+// results and the completion list
+func (m Model) renderGraphQLBody() string {
+	queryLabel, variablesLabel := styles.InfoStyle.Render("Query"), styles.InfoStyle.Render("Variables (JSON)")
+
+	queryEditor := styles.BlurredStyle.Render(m.graphQuery.View())
+	variablesEditor := styles.BlurredStyle.Render(m.graphVariables.View())
+	if m.focused == 0 {
+		queryEditor = styles.FocusedStyle.Render(m.graphQuery.View())
+	} else {
+		variablesEditor = styles.FocusedStyle.Render(m.graphVariables.View())
+	}
+
+	var schemaStatus string
+	switch {
+	case m.graphSchemaLoading:
+		schemaStatus = styles.InfoStyle.Render("Fetching schema...")
+	case m.graphSchema != nil:
+		schemaStatus = styles.InfoStyle.Render(fmt.Sprintf("Schema: %d types", len(m.graphSchema.Types))) +
+			styles.HelpStyle.Render(" • Ctrl+G: Refresh • Ctrl+Space: Complete")
+	default:
+		schemaStatus = styles.HelpStyle.Render("Ctrl+G: Fetch schema for completion and validation")
+	}
+
+	rows := []string{
+		styles.HeaderStyle.Render("GraphQL Request"),
+		schemaStatus,
+		styles.HelpStyle.Render("Tab: Switch editor • Ctrl+S: Send"),
+		"",
+		queryLabel,
+		queryEditor,
+	}
+	if completions := m.renderCompletions(); completions != "" {
+		rows = append(rows, completions)
+	}
+	rows = append(rows, m.renderGraphQLValidation(), "", variablesLabel, variablesEditor)
+
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m Model) renderGraphQLValidation() string {
+	if strings.TrimSpace(m.graphQuery.Value()) == "" {
+		return ""
+	}
+	if len(m.graphErrors) == 0 {
+		if m.graphSchema != nil {
+			return styles.StatusStyle.Render("✓ Valid against schema")
+		}
+		return styles.StatusStyle.Render("✓ Syntax OK")
+	}
+
+	var lines []string
+	for _, e := range m.graphErrors[:min(len(m.graphErrors), maxShownGraphQLErrors)] {
+		lines = append(lines, styles.ErrorStyle.Render(fmt.Sprintf("✗ %d:%d %s", e.Pos.Line, e.Pos.Column, e.Message)))
+	}
+	if more := len(m.graphErrors) - maxShownGraphQLErrors; more > 0 {
+		lines = append(lines, styles.HelpStyle.Render(fmt.Sprintf("  and %d more", more)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderCompletions lists a window of suggestions around the selected one
+func (m Model) renderCompletions() string {
+	if len(m.graphSuggestions) == 0 {
+		return ""
+	}
+
+	start := max(0, min(m.graphSuggestIndex-maxCompletions/2, len(m.graphSuggestions)-maxCompletions))
+	end := min(len(m.graphSuggestions), start+maxCompletions)
+
+	width := 0
+	for _, s := range m.graphSuggestions[start:end] {
+		width = max(width, len(s.Label))
+	}
+
+	var lines []string
+	for i := start; i < end; i++ {
+		s := m.graphSuggestions[i]
+		line := fmt.Sprintf(" %-*s  %s ", width, s.Label, s.Detail)
+		if i == m.graphSuggestIndex {
+			lines = append(lines, completionSelectedStyle.Render(line))
+		} else {
+			lines = append(lines, completionStyle.Render(line))
+		}
+	}
+	lines = append(lines, styles.HelpStyle.Render(fmt.Sprintf(" %d/%d • ↑/↓: Select • Enter/Tab: Insert • Esc: Close", m.graphSuggestIndex+1, len(m.graphSuggestions))))
+
+	return strings.Join(lines, "\n")
+}
+
+// renderGraphQLResponse shows a response's errors ahead of, and separately
+// from, its data
+func (m Model) renderGraphQLResponse(resp *graphql.Response) string {
+	lines := []string{
+		styles.ErrorStyle.Copy().Bold(true).Render(fmt.Sprintf("✗ Errors (%d)", len(resp.Errors))),
+	}
+	for i, e := range resp.Errors {
+		lines = append(lines, styles.ErrorStyle.Render(fmt.Sprintf("  %d. %s", i+1, e.Message)))
+
+		var details []string
+		for _, loc := range e.Locations {
+			details = append(details, fmt.Sprintf("at %d:%d", loc.Line, loc.Column))
+		}
+		if path := e.PathString(); path != "" {
+			details = append(details, "path "+path)
+		}
+		if code, ok := e.Extensions["code"]; ok {
+			details = append(details, fmt.Sprintf("code %v", code))
+		}
+		if len(details) > 0 {
+			lines = append(lines, styles.HelpStyle.Render("     "+strings.Join(details, " • ")))
+		}
+	}
+
+	lines = append(lines, "", styles.HeaderStyle.Render("Data"))
+	if len(resp.Data) == 0 || string(resp.Data) == "null" {
+		lines = append(lines, styles.HelpStyle.Render("null"))
+	} else {
+		lines = append(lines, m.highlighter.Highlight(string(resp.Data), "application/json"))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// graphQLKeyActive reports whether a GraphQL editor key applies right now
+func (m Model) graphQLKeyActive(msg tea.KeyMsg, binding key.Binding) bool {
+	return key.Matches(msg, binding) && m.activeTab == BodyTab && m.graphQLMode()
+}
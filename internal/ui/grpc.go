@@ -0,0 +1,475 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/grpc"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// GRPCMethod is the pseudo-method that selects gRPC mode. The URL field
+// holds the target (host:port, grpc:// or grpcs://) and the body is the
+// request message as JSON.
+const GRPCMethod = "GRPC"
+
+// maxGRPCMessages caps the response log of a streaming call
+const maxGRPCMessages = 1000
+
+// maxListedMethods is how many methods the picker shows at once
+const maxListedMethods = 8
+
+var grpcMethodStyle lipgloss.Style
+
+func init() {
+	styles.OnApply(func() {
+		grpcMethodStyle = styles.SelectedStyle()
+	})
+}
+
+// grpcModeSelected reports whether the request should go out as a gRPC call
+func (m Model) grpcModeSelected() bool {
+	return m.getSelectedMethod() == GRPCMethod || grpc.IsURL(m.urlInput.Value())
+}
+
+// reflectServices lists the target's services through server reflection
+func (m Model) reflectServices() (Model, tea.Cmd) {
+	target := m.urlInput.Value()
+	metadata := m.grpcMetadata()
+
+	m.grpcLoading = true
+	m.notice = styles.InfoStyle.Render("Listing services on " + target + "...")
+
+	return m, func() tea.Msg {
+		catalog, err := grpc.Reflect(context.Background(), target, metadata)
+		return GRPCCatalogMessage{Catalog: catalog, Target: target, Err: err}
+	}
+}
+
+// startLoadProtos opens the prompt for .proto and descriptor set files
+func (m Model) startLoadProtos() (Model, tea.Cmd) {
+	m.loadingProtos = true
+	m.protoInput.CursorEnd()
+	return m, m.protoInput.Focus()
+}
+
+// updateLoadProtos handles key presses while the proto files prompt is active
+func (m Model) updateLoadProtos(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case msg.String() == "esc":
+		m.loadingProtos = false
+		m.protoInput.Blur()
+		m.updateFocus()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		var paths []string
+		for _, field := range strings.Fields(m.protoInput.Value()) {
+			path, err := expandHome(field)
+			if err != nil {
+				m.notice = styles.ErrorStyle.Render("Loading failed: " + err.Error())
+				return m, nil
+			}
+			paths = append(paths, path)
+		}
+		if len(paths) == 0 {
+			return m, nil
+		}
+
+		m.loadingProtos = false
+		m.protoInput.Blur()
+		m.updateFocus()
+		m.grpcLoading = true
+		return m, func() tea.Msg {
+			catalog, err := grpc.LoadFiles(paths)
+			return GRPCCatalogMessage{Catalog: catalog, Err: err}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.protoInput, cmd = m.protoInput.Update(msg)
+	return m, cmd
+}
+
+// handleGRPCCatalog installs a freshly loaded catalog, keeping the chosen
+// method if the new catalog has it
+func (m *Model) handleGRPCCatalog(msg GRPCCatalogMessage) {
+	m.grpcLoading = false
+	if msg.Err != nil {
+		m.notice = styles.ErrorStyle.Render("Listing services failed: " + msg.Err.Error())
+		return
+	}
+
+	m.grpcCatalog = msg.Catalog
+	m.grpcCatalogTarget = msg.Target
+	if _, ok := msg.Catalog.Find(m.grpcRPC); !ok && len(msg.Catalog.Methods) > 0 {
+		m.grpcRPC = msg.Catalog.Methods[0].FullName()
+	}
+	m.notice = styles.StatusStyle.Render(fmt.Sprintf("Loaded %d methods from %s", len(msg.Catalog.Methods), msg.Catalog.Source))
+	m.updateFocus()
+}
+
+// grpcMetadata is the headers table, sent as request metadata
+func (m Model) grpcMetadata() map[string]string {
+	metadata := make(map[string]string, len(m.requestHeaders))
+	for k, v := range m.requestHeaders {
+		metadata[k] = v
+	}
+	return metadata
+}
+
+// invokeGRPC calls the chosen method, listing the target's services first
+// if no catalog covers it yet
+func (m Model) invokeGRPC() (Model, tea.Cmd) {
+	m.resetStream()
+	closing := m.closeSocket()
+	m.socketMode = false
+	m.cancelGRPC()
+	m.loading = true
+	m.activeTab = ResponseTab
+
+	target := m.urlInput.Value()
+	metadata := m.grpcMetadata()
+	body := m.bodyTextarea.Value()
+	rpc := m.grpcRPC
+
+	catalog := m.grpcCatalog
+	if catalog != nil && m.grpcCatalogTarget != "" && m.grpcCatalogTarget != target {
+		catalog = nil
+	}
+
+	return m, tea.Batch(
+		closing,
+		m.spinner.Tick,
+		func() tea.Msg {
+			start := time.Now()
+			msg := GRPCStartedMessage{Started: start}
+
+			if catalog == nil {
+				reflected, err := grpc.Reflect(context.Background(), target, metadata)
+				if err != nil {
+					msg.Err = err
+					return msg
+				}
+				catalog = reflected
+				msg.Catalog, msg.Target = reflected, target
+			}
+
+			method, ok := catalog.Find(rpc)
+			if !ok {
+				msg.Err = errors.New("choose a method in the Body tab")
+				if rpc != "" {
+					msg.Err = fmt.Errorf("%s is not in %s", rpc, catalog.Source)
+				}
+				return msg
+			}
+
+			msg.Call, msg.Err = grpc.Invoke(target, catalog, method, body, metadata)
+			return msg
+		},
+	)
+}
+
+// handleGRPCStarted shows the response log for a call that is under way
+func (m *Model) handleGRPCStarted(msg GRPCStartedMessage) tea.Cmd {
+	if msg.Catalog != nil {
+		m.handleGRPCCatalog(GRPCCatalogMessage{Catalog: msg.Catalog, Target: msg.Target})
+	}
+
+	m.loading = false
+	m.activeTab = ResponseTab
+	m.statusCode = 0
+	m.responseTime = time.Since(msg.Started)
+	m.responseHeaders = make(map[string]string)
+	m.responseHeadersContent = m.formatResponseHeaders()
+	m.responseContentType = ""
+	m.jsonTree, m.table = nil, nil
+	m.responseView = PrettyView
+	m.responseRedirects, m.redirectStopped = nil, false
+	m.responseErr = msg.Err
+
+	if msg.Err != nil {
+		m.grpcMode = false
+		m.responseBody = ""
+		m.response = m.renderResponseContent()
+		m.refreshResponseViewports()
+		return nil
+	}
+
+	m.grpcMode = true
+	m.grpcCall = msg.Call
+	m.grpcUnary = msg.Call.Method.Kind() == "unary"
+	m.grpcStarted = msg.Started
+	m.grpcMessages = nil
+	m.grpcReceived = 0
+	m.grpcRendered = nil
+	m.grpcStatus = nil
+	m.grpcTrailer = nil
+	m.responseContentType = "application/json"
+	m.refreshGRPC()
+	return waitForGRPC(msg.Call)
+}
+
+// waitForGRPC delivers the next response message of call, or GRPCEndMessage
+// once the call ends
+func waitForGRPC(call *grpc.Call) tea.Cmd {
+	return func() tea.Msg {
+		message, ok := <-call.Messages
+		if !ok {
+			return GRPCEndMessage{Call: call}
+		}
+		return GRPCMessage{Call: call, Message: message}
+	}
+}
+
+// cancelGRPC abandons the call in flight, if any
+func (m *Model) cancelGRPC() {
+	if m.grpcCall != nil {
+		m.grpcCall.Cancel()
+		m.grpcCall = nil
+	}
+}
+
+// appendGRPCMessage records a response message and re-renders the log
+func (m *Model) appendGRPCMessage(message grpc.Message) {
+	m.grpcMessages = append(m.grpcMessages, message)
+	m.grpcReceived++
+	m.grpcRendered = append(m.grpcRendered, m.renderGRPCMessage(message, m.grpcReceived))
+	if over := len(m.grpcMessages) - maxGRPCMessages; over > 0 {
+		m.grpcMessages = m.grpcMessages[over:]
+		m.grpcRendered = m.grpcRendered[over:]
+	}
+	m.refreshGRPC()
+}
+
+// finishGRPC records the status, headers and trailers of an ended call
+func (m *Model) finishGRPC(call *grpc.Call) {
+	status := call.Status()
+	m.grpcStatus = &status
+	m.grpcCall = nil
+	m.responseTime = time.Since(m.grpcStarted)
+	m.responseHeaders = call.Header()
+	m.grpcTrailer = call.Trailer()
+	m.responseHeadersContent = m.formatResponseHeaders() + "\n\n" + formatTrailers(m.grpcTrailer)
+
+	// A single response can be browsed like any JSON body
+	if len(m.grpcMessages) == 1 {
+		m.buildTree()
+		m.buildTable()
+	}
+	m.refreshGRPC()
+}
+
+// formatTrailers renders the trailers section of the headers sub-tab
+func formatTrailers(trailers map[string]string) string {
+	section := styles.HeaderStyle.Render("Trailers")
+	if len(trailers) == 0 {
+		return section + "\n" + styles.HelpStyle.Render("No trailers")
+	}
+
+	keys := make([]string, 0, len(trailers))
+	for key := range trailers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := []string{section}
+	for _, key := range keys {
+		lines = append(lines, styles.InfoStyle.Render(key)+": "+styles.JsonStyle.Render(trailers[key]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// refreshGRPC rebuilds the rendered and plain-text response log, following
+// the newest message if the view was already at the bottom
+func (m *Model) refreshGRPC() {
+	follow := m.responseViewport.AtBottom()
+
+	switch {
+	case len(m.grpcRendered) > 0:
+		m.response = strings.Join(m.grpcRendered, "\n")
+	case m.grpcStatus != nil:
+		m.response = styles.HelpStyle.Render("No response messages")
+	default:
+		m.response = styles.HelpStyle.Render("Waiting for response...")
+	}
+
+	var raw []string
+	for _, message := range m.grpcMessages {
+		raw = append(raw, message.JSON)
+	}
+	m.responseBody = strings.Join(raw, "\n")
+	m.responseSize = int64(len(m.responseBody))
+
+	if m.searchInput.Value() != "" {
+		m.runSearch()
+		return
+	}
+	m.refreshResponseViewports()
+	if follow {
+		m.responseViewport.GotoBottom()
+	}
+}
+
+// renderGRPCMessage renders a response message; messages of streaming calls
+// get a numbered, timestamped header
+func (m Model) renderGRPCMessage(message grpc.Message, n int) string {
+	body := m.highlighter.Highlight(message.JSON, "application/json")
+	if m.grpcUnary {
+		return body
+	}
+	header := streamMetaStyle.Render(message.Time.Format("15:04:05.000")) + " " +
+		socketReceivedStyle.Render("←") + " " + streamTypeStyle.Render(fmt.Sprintf("message %d", n))
+	return header + "\n" + body
+}
+
+// renderGRPCStatus shows the call's state and the keys that apply
+func (m Model) renderGRPCStatus() string {
+	count := styles.InfoStyle.Render(fmt.Sprintf("%d messages", len(m.grpcMessages)))
+
+	switch {
+	case m.grpcCall != nil:
+		return streamLiveStyle.Render("● Calling "+m.grpcCall.Method.FullName()) + "  " + count + "  " +
+			styles.HelpStyle.Render("x: Cancel")
+	case m.grpcStatus != nil && m.grpcStatus.OK():
+		return styles.StatusStyle.Render("✓ OK") + "  " + count
+	case m.grpcStatus != nil:
+		text := fmt.Sprintf("✕ %s (%d)", m.grpcStatus.Code, m.grpcStatus.Code)
+		if m.grpcStatus.Message != "" {
+			text += ": " + m.grpcStatus.Message
+		}
+		return styles.ErrorStyle.Render(text) + "  " + count
+	}
+	return count
+}
+
+// renderGRPCStatusCode is the status bar's status for gRPC calls
+func (m Model) renderGRPCStatusCode() string {
+	style := lipgloss.NewStyle().Bold(true)
+	switch {
+	case m.grpcStatus == nil:
+		return style.Foreground(styles.Warning).Render("gRPC: in progress")
+	case m.grpcStatus.OK():
+		return style.Foreground(styles.Success).Render("gRPC: OK")
+	}
+	return style.Foreground(styles.Danger).Render("gRPC: " + m.grpcStatus.Code.String())
+}
+
+// updateGRPCMethods moves through the method picker. Enter fills the editor
+// with a template of the chosen method's request.
+func (m *Model) updateGRPCMethods(msg tea.KeyMsg) {
+	methods := m.grpcCatalog.Methods
+	if len(methods) == 0 {
+		return
+	}
+
+	index := 0
+	for i, method := range methods {
+		if method.FullName() == m.grpcRPC {
+			index = i
+		}
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		index = (index - 1 + len(methods)) % len(methods)
+	case key.Matches(msg, m.keys.Down):
+		index = (index + 1) % len(methods)
+	case key.Matches(msg, m.keys.Enter):
+		m.bodyTextarea.SetValue(m.grpcCatalog.Template(methods[index]))
+		m.focused = 1
+		m.updateFocus()
+	}
+	m.grpcRPC = methods[index].FullName()
+}
+
+// grpcPickerFocused reports whether keys in the Body tab go to the method
+// picker rather than the message editor
+func (m Model) grpcPickerFocused() bool {
+	return m.focused == 0 && m.grpcCatalog != nil && len(m.grpcCatalog.Methods) > 0
+}
+
+// renderGRPCBody renders the method picker and the request message editor
+func (m Model) renderGRPCBody() string {
+	rows := []string{styles.HeaderStyle.Render("gRPC Request")}
+
+	switch {
+	case m.grpcLoading:
+		rows = append(rows, styles.InfoStyle.Render("Loading services..."))
+	case m.grpcCatalog != nil:
+		rows = append(rows, styles.InfoStyle.Render(fmt.Sprintf("%d methods from %s", len(m.grpcCatalog.Methods), m.grpcCatalog.Source))+
+			styles.HelpStyle.Render(" • Ctrl+G: Reflect • Ctrl+T: Load files"))
+	default:
+		rows = append(rows, styles.HelpStyle.Render("Ctrl+G: List services via reflection • Ctrl+T: Load .proto or descriptor set files"))
+	}
+
+	if m.loadingProtos {
+		rows = append(rows, styles.FocusedStyle.Render(m.protoInput.View()),
+			styles.HelpStyle.Render("Space-separated paths • Enter: Load • Esc: Cancel"))
+	}
+
+	if picker := m.renderGRPCMethods(); picker != "" {
+		rows = append(rows, "", picker)
+	}
+
+	editor := styles.BlurredStyle.Render(m.bodyTextarea.View())
+	if !m.grpcPickerFocused() {
+		editor = styles.FocusedStyle.Render(m.bodyTextarea.View())
+	}
+	rows = append(rows, "",
+		styles.InfoStyle.Render("Request message (JSON)"),
+		styles.HelpStyle.Render("Tab: Switch between method and message • Metadata comes from the Headers tab • Ctrl+S: Call"),
+		editor,
+	)
+
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// renderGRPCMethods lists a window of methods around the chosen one
+func (m Model) renderGRPCMethods() string {
+	if m.grpcCatalog == nil || len(m.grpcCatalog.Methods) == 0 {
+		return ""
+	}
+	methods := m.grpcCatalog.Methods
+
+	selected := 0
+	width := 0
+	for i, method := range methods {
+		if method.FullName() == m.grpcRPC {
+			selected = i
+		}
+		width = max(width, len(method.FullName()))
+	}
+
+	start := max(0, min(selected-maxListedMethods/2, len(methods)-maxListedMethods))
+	end := min(len(methods), start+maxListedMethods)
+
+	var lines []string
+	for i := start; i < end; i++ {
+		method := methods[i]
+		line := fmt.Sprintf(" %-*s  %-16s %s → %s ", width, method.FullName(), method.Kind(), method.Input(), method.Output())
+		switch {
+		case i == selected && m.grpcPickerFocused():
+			lines = append(lines, grpcMethodStyle.Render(line))
+		case i == selected:
+			lines = append(lines, styles.InfoStyle.Render(line))
+		default:
+			lines = append(lines, completionStyle.Render(line))
+		}
+	}
+	help := fmt.Sprintf(" %d/%d", selected+1, len(methods))
+	if m.grpcPickerFocused() {
+		help += " • ↑/↓: Choose method • Enter: Insert request template"
+	}
+	lines = append(lines, styles.HelpStyle.Render(help))
+
+	return strings.Join(lines, "\n")
+}
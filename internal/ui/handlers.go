@@ -1,9 +1,11 @@
 package ui
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/list"
@@ -12,14 +14,16 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 
+	"github.com/pixperk/quest/internal/config"
+	"github.com/pixperk/quest/internal/graphql"
 	"github.com/pixperk/quest/internal/http"
 	"github.com/pixperk/quest/internal/styles"
-	"github.com/pixperk/quest/internal/syntax"
+	"github.com/pixperk/quest/internal/ws"
 )
 
-func NewModel() Model {
+// NewModel builds the UI around the effective configuration
+func NewModel(cfg config.Config) (Model, error) {
 	urlInput := textinput.New()
 	urlInput.Placeholder = "https://api.example.com/endpoint"
 	urlInput.Focus()
@@ -39,17 +43,17 @@ func NewModel() Model {
 	bodyTextarea.SetWidth(60)
 	bodyTextarea.SetHeight(10)
 
-	methods := []list.Item{
-		HTTPMethod{Name: "GET", Desc: "Retrieve data"},
-		HTTPMethod{Name: "POST", Desc: "Create new resource"},
-		HTTPMethod{Name: "PUT", Desc: "Update/replace resource"},
-		HTTPMethod{Name: "DELETE", Desc: "Remove resource"},
-		HTTPMethod{Name: "PATCH", Desc: "Partial update"},
-		HTTPMethod{Name: "HEAD", Desc: "Headers only"},
-		HTTPMethod{Name: "OPTIONS", Desc: "Available methods"},
-	}
+	graphQuery, graphVariables := newGraphQLEditors()
+	rpcMethod, rpcParams := newRPCEditors()
+	formName, formValue, formType := newFormEditors()
+
+	methodInput := textinput.New()
+	methodInput.Prompt = "Custom method: "
+	methodInput.Placeholder = "PURGE"
+	methodInput.CharLimit = 40
+	methodInput.Width = 20
 
-	methodList := list.New(methods, NewMethodDelegate(), 15, 7)
+	methodList := list.New(defaultMethods(), NewMethodDelegate(), 15, 7)
 	methodList.Title = "HTTP Method"
 	methodList.SetShowStatusBar(false)
 	methodList.SetFilteringEnabled(false)
@@ -63,25 +67,57 @@ func NewModel() Model {
 
 	viewport := viewport.New(60, 15)
 
+	searchInput := textinput.New()
+	searchInput.Prompt = "/"
+	searchInput.Placeholder = "search"
+	searchInput.Width = 30
+
+	saveInput := textinput.New()
+	saveInput.Prompt = "Save to: "
+	saveInput.Placeholder = "response.json"
+	saveInput.Width = 40
+
+	protoInput := textinput.New()
+	protoInput.Prompt = "Proto files: "
+	protoInput.Placeholder = "api/service.proto or service.protoset"
+	protoInput.Width = 50
+
+	optionInput := textinput.New()
+	optionInput.Width = 50
+
+	cookieInput := textinput.New()
+	cookieInput.Prompt = "Value: "
+	cookieInput.Width = 50
+
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(styles.HotPink)
 
 	help := help.New()
 	help.ShowAll = false
 
-	return Model{
+	m := Model{
 		urlInput:          urlInput,
 		methodList:        methodList,
+		methodInput:       methodInput,
 		requestList:       requestList,
 		headerKey:         headerKey,
 		headerValue:       headerValue,
 		bodyTextarea:      bodyTextarea,
+		graphQuery:        graphQuery,
+		graphVariables:    graphVariables,
+		formName:          formName,
+		formValue:         formValue,
+		formType:          formType,
+		rpcMethod:         rpcMethod,
+		rpcParams:         rpcParams,
+		rpcNextID:         1,
 		responseViewport:  viewport,
 		headersViewport:   viewport,
+		searchInput:       searchInput,
+		saveInput:         saveInput,
+		protoInput:        protoInput,
 		help:              help,
 		spinner:           s,
-		highlighter:       syntax.NewHighlighter(),
 		activeTab:         URLTab,
 		responseSubTab:    ResponseBodySubTab,
 		focused:           0,
@@ -91,7 +127,14 @@ func NewModel() Model {
 		savedRequests:     make([]SavedRequest, 0),
 		httpClient:        http.NewClient(),
 		showingLoadDialog: false,
+		cookieInput:       cookieInput,
+		optionInput:       optionInput,
 	}
+	if err := m.applyConfig(cfg); err != nil {
+		return Model{}, err
+	}
+	m.loadCookieJar()
+	return m, nil
 }
 
 func (m Model) Init() tea.Cmd {
@@ -109,7 +152,18 @@ func (m *Model) updateSizes() {
 	m.headerKey.Width = (m.width - 35) / 2
 	m.headerValue.Width = (m.width - 35) / 2
 	m.bodyTextarea.SetWidth(m.width - 10)
-	m.bodyTextarea.SetHeight(m.height - 25)
+	// Leave room for the body mode and Content-Type lines
+	m.bodyTextarea.SetHeight(m.height - 28)
+	m.graphQuery.SetWidth(m.width - 10)
+	m.graphQuery.SetHeight(max(3, (m.height-25)*2/3-3))
+	m.graphVariables.SetWidth(m.width - 10)
+	m.graphVariables.SetHeight(max(3, (m.height-25)/3-5))
+	m.formName.Width = (m.width - 40) / 4
+	m.formValue.Width = (m.width - 40) / 2
+	m.formType.Width = (m.width - 40) / 4
+	m.rpcMethod.Width = m.width - 20
+	m.rpcParams.SetWidth(m.width - 10)
+	m.rpcParams.SetHeight(max(3, m.height-25-8))
 	m.responseViewport.Width = m.width - 6
 	m.responseViewport.Height = m.height - 25
 	m.headersViewport.Width = m.width - 6
@@ -118,14 +172,24 @@ func (m *Model) updateSizes() {
 
 func (m *Model) updateFocus() {
 	m.urlInput.Blur()
+	m.methodInput.Blur()
 	m.headerKey.Blur()
 	m.headerValue.Blur()
 	m.bodyTextarea.Blur()
+	m.graphQuery.Blur()
+	m.graphVariables.Blur()
+	m.rpcMethod.Blur()
+	m.rpcParams.Blur()
+	m.formName.Blur()
+	m.formValue.Blur()
+	m.formType.Blur()
 
 	switch m.activeTab {
 	case URLTab:
 		if m.focused == 0 {
 			m.urlInput.Focus()
+		} else if m.focused == 2 {
+			m.methodInput.Focus()
 		}
 	case HeadersTab:
 		if m.focused == 0 {
@@ -134,7 +198,28 @@ func (m *Model) updateFocus() {
 			m.headerValue.Focus()
 		}
 	case BodyTab:
-		m.bodyTextarea.Focus()
+		switch {
+		case m.grpcModeSelected():
+			if !m.grpcPickerFocused() {
+				m.bodyTextarea.Focus()
+			}
+		case m.jsonRPCMode() && m.focused == 0:
+			m.rpcMethod.Focus()
+		case m.jsonRPCMode():
+			m.rpcParams.Focus()
+		case m.formMode() && m.focused == 0:
+			m.formName.Focus()
+		case m.formMode() && m.focused == 1:
+			m.formValue.Focus()
+		case m.formMode():
+			m.formType.Focus()
+		case !m.graphQLMode():
+			m.bodyTextarea.Focus()
+		case m.focused == 0:
+			m.graphQuery.Focus()
+		default:
+			m.graphVariables.Focus()
+		}
 	}
 }
 
@@ -146,32 +231,127 @@ func (m Model) getSelectedMethod() string {
 }
 
 func (m Model) sendRequest() (Model, tea.Cmd) {
-	m.loading = true
-	m.activeTab = ResponseTab
+	if m.getSelectedMethod() == WebSocketMethod || ws.IsURL(m.urlInput.Value()) {
+		if m.socket != nil {
+			return m, m.sendFrame()
+		}
+		return m.connectSocket()
+	}
+
+	if m.grpcModeSelected() {
+		return m.invokeGRPC()
+	}
+
+	if m.jsonRPCMode() {
+		return m.sendRPC()
+	}
 
-	req := http.Request{
+	req := m.buildRequest()
+	if m.graphQLMode() {
+		var err error
+		if req, err = m.buildGraphQLRequest(m.graphQuery.Value(), m.graphVariables.Value()); err != nil {
+			m.notice = styles.ErrorStyle.Render("GraphQL: " + err.Error())
+			return m, nil
+		}
+	}
+
+	closing := m.closeSocket()
+	m.socketMode = false
+	m.cancelGRPC()
+	m.grpcMode = false
+	m.resetStream()
+	m, cmd := m.send(req)
+	return m, tea.Batch(closing, cmd)
+}
+
+// buildRequest assembles a request from the current inputs
+func (m Model) buildRequest() http.Request {
+	return m.applyRequestOptions(http.Request{
 		Method:  m.getSelectedMethod(),
 		URL:     m.urlInput.Value(),
 		Headers: m.requestHeaders,
 		Body:    m.bodyTextarea.Value(),
-	}
+
+		ContentType: m.bodyContentType,
+		BodyMode:    m.bodyMode,
+		Form:        m.formFields,
+	})
+}
+
+// send starts req in the background, reporting progress until it completes
+func (m Model) send(req http.Request) (Model, tea.Cmd) {
+	m.loading = true
+	// GraphQL and JSON-RPC are sent as POST, so the mode is kept to render
+	// the response in
+	m.responseMethod = m.getSelectedMethod()
+	m.activeTab = ResponseTab
+	m.progress = http.Progress{}
+
+	// Progress updates are dropped rather than queued if the UI falls behind;
+	// the next one supersedes them anyway
+	progress := make(chan http.Progress, 1)
+	m.progressCh = progress
+	ctx, cancel := context.WithCancelCause(context.Background())
+	m.cancelRequest = cancel
 
 	return m, tea.Batch(
 		m.spinner.Tick,
+		waitForProgress(progress),
 		func() tea.Msg {
-			resp := m.httpClient.SendRequest(req)
+			resp := m.httpClient.SendRequestContext(ctx, req, func(p http.Progress) {
+				select {
+				case progress <- p:
+				default:
+				}
+			})
+			close(progress)
+			url := resp.URL
+			if url == "" {
+				url = req.URL
+			}
 			return ResponseMessage{
 				StatusCode:   resp.StatusCode,
+				Proto:        resp.Proto,
+				URL:          url,
 				Headers:      resp.Headers,
 				Body:         resp.Body,
 				ContentType:  resp.ContentType,
 				ResponseTime: resp.ResponseTime,
 				Error:        resp.Error,
+				BodySize:     resp.BodySize,
+				BodyFile:     resp.BodyFile,
+				Binary:       resp.Binary,
+				Stream:       resp.Stream,
+
+				Redirects:       resp.Redirects,
+				RedirectStopped: resp.RedirectStopped,
+				SetCookies:      resp.SetCookies,
+				Connection:      resp.Connection,
+				Attempts:        resp.Attempts,
 			}
 		},
 	)
 }
 
+// waitForProgress delivers the next download progress update, if any
+func waitForProgress(progress <-chan http.Progress) tea.Cmd {
+	return func() tea.Msg {
+		p, ok := <-progress
+		if !ok {
+			return nil
+		}
+		return ProgressMessage{Progress: p}
+	}
+}
+
+// discardResponseFile removes the temp file holding a spilled response body
+func (m *Model) discardResponseFile() {
+	if m.responseFile != "" {
+		os.Remove(m.responseFile)
+		m.responseFile = ""
+	}
+}
+
 func (m Model) saveCurrentRequest() (Model, tea.Cmd) {
 	if m.urlInput.Value() == "" {
 		return m, nil
@@ -184,6 +364,52 @@ func (m Model) saveCurrentRequest() (Model, tea.Cmd) {
 		Headers: make(map[string]string),
 		Body:    m.bodyTextarea.Value(),
 	}
+	if m.graphQLMode() {
+		req, err := m.buildGraphQLRequest(m.graphQuery.Value(), m.graphVariables.Value())
+		if err != nil {
+			m.notice = styles.ErrorStyle.Render("GraphQL: " + err.Error())
+			return m, nil
+		}
+		request.Body = req.Body
+	}
+	if m.grpcModeSelected() {
+		request.RPC = m.grpcRPC
+	}
+	if m.bodyModesAvailable() {
+		request.ContentType = m.bodyContentType
+	}
+	if m.redirectPolicy != (http.RedirectPolicy{}) {
+		policy := m.redirectPolicy
+		request.Redirects = &policy
+	}
+	if !m.tlsConfig.IsZero() {
+		config := m.tlsConfig
+		request.TLS = &config
+	}
+	if !m.proxyConfig.IsZero() {
+		proxy := m.proxyConfig
+		request.Proxy = &proxy
+	}
+	if !m.target.IsZero() {
+		target := m.target
+		request.Target = &target
+	}
+	if m.retryPolicy != (http.RetryPolicy{}) {
+		policy := m.retryPolicy
+		request.Retry = &policy
+	}
+	if m.formMode() {
+		request.BodyMode = m.bodyMode.String()
+		request.Form = m.formFields
+	}
+	if m.jsonRPCMode() {
+		req, err := m.buildRPCRequest()
+		if err != nil {
+			m.notice = styles.ErrorStyle.Render("JSON-RPC: " + err.Error())
+			return m, nil
+		}
+		request.Body = req.Body
+	}
 
 	for k, v := range m.requestHeaders {
 		request.Headers[k] = v
@@ -195,6 +421,16 @@ func (m Model) saveCurrentRequest() (Model, tea.Cmd) {
 	if err := m.saveRequestsToFile(requests); err != nil {
 		return m, nil
 	}
+	var left []string
+	if m.tlsConfig.PKCS12Password != "" {
+		left = append(left, "the PKCS#12 password (tls.pkcs12_password)")
+	}
+	if withoutCredentials(m.proxyConfig.URL) != m.proxyConfig.URL {
+		left = append(left, "the proxy credentials (proxy.url or $HTTPS_PROXY)")
+	}
+	if len(left) > 0 {
+		m.notice = styles.HelpStyle.Render("Saved without " + strings.Join(left, " and ") + "; set them in the configuration")
+	}
 
 	return m, nil
 }
@@ -214,12 +450,32 @@ func (m Model) loadRequestsFromFile() []SavedRequest {
 }
 
 func (m Model) saveRequestsToFile(requests []SavedRequest) error {
+	// Passwords stay out of .quest, including ones saved by older versions;
+	// they belong in the configuration
+	for i, request := range requests {
+		if request.TLS != nil && request.TLS.PKCS12Password != "" {
+			config := *request.TLS
+			config.PKCS12Password = ""
+			requests[i].TLS = &config
+		}
+		if request.Proxy != nil && withoutCredentials(request.Proxy.URL) != request.Proxy.URL {
+			proxy := *request.Proxy
+			proxy.URL = withoutCredentials(proxy.URL)
+			requests[i].Proxy = &proxy
+		}
+	}
+
 	data, err := json.MarshalIndent(requests, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(".quest", data, 0644)
+	// Saved requests can carry tokens in their headers and bodies, so the
+	// file is kept private, even one created before this was the case
+	if err := os.WriteFile(".quest", data, 0600); err != nil {
+		return err
+	}
+	return os.Chmod(".quest", 0600)
 }
 
 func (m Model) showLoadRequestDialog() (Model, tea.Cmd) {
@@ -242,14 +498,51 @@ func (m Model) showLoadRequestDialog() (Model, tea.Cmd) {
 func (m Model) loadSelectedRequest(request SavedRequest) (Model, tea.Cmd) {
 	m.urlInput.SetValue(request.URL)
 	m.bodyTextarea.SetValue(request.Body)
-
-	for i, item := range m.methodList.Items() {
-		if method, ok := item.(HTTPMethod); ok && method.Name == request.Method {
-			m.methodList.Select(i)
-			break
+	m.grpcRPC = request.RPC
+	m.bodyContentType = request.ContentType
+	m.redirectPolicy = http.RedirectPolicy{}
+	if request.Redirects != nil {
+		m.redirectPolicy = *request.Redirects
+	}
+	m.tlsConfig = http.TLSConfig{}
+	if request.TLS != nil {
+		m.tlsConfig = *request.TLS
+	}
+	m.proxyConfig = http.ProxyConfig{}
+	if request.Proxy != nil {
+		m.proxyConfig = *request.Proxy
+	}
+	m.target = http.Target{}
+	if request.Target != nil {
+		m.target = *request.Target
+	}
+	m.retryPolicy = http.RetryPolicy{}
+	if request.Retry != nil {
+		// A hand-edited .quest file can hold any limits, so they are checked
+		// before the policy is used
+		if err := request.Retry.Validate(); err != nil {
+			m.notice = styles.ErrorStyle.Render("Retry policy ignored: " + err.Error())
+		} else {
+			m.retryPolicy = *request.Retry
+		}
+	}
+	m.bodyMode = http.ParseBodyMode(request.BodyMode)
+	m.formFields = request.Form
+	if request.Method == JSONRPCMethod {
+		m.loadRPCCalls(request.Body)
+	}
+	if request.Method == GraphQLMethod {
+		if query, variables, err := graphql.ParseEnvelope(request.Body); err == nil {
+			m.graphQuery.SetValue(query)
+			m.graphVariables.SetValue(variables)
+			m.validateGraphQL()
 		}
 	}
 
+	if request.Method != "" {
+		m.selectMethod(request.Method)
+	}
+
 	m.requestHeaders = make(map[string]string)
 	for k, v := range request.Headers {
 		m.requestHeaders[k] = v
@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/hexview"
+	"github.com/pixperk/quest/internal/search"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+var (
+	hexOffsetStyle lipgloss.Style
+	hexBytesStyle  lipgloss.Style
+	hexASCIIStyle  lipgloss.Style
+)
+
+func init() {
+	styles.OnApply(func() {
+		hexOffsetStyle = lipgloss.NewStyle().Foreground(styles.Muted)
+		hexBytesStyle = lipgloss.NewStyle().Foreground(styles.Text)
+		hexASCIIStyle = lipgloss.NewStyle().Foreground(styles.Success)
+	})
+}
+
+func (m Model) showingHex() bool {
+	return m.activeTab == ResponseTab && m.hexActive()
+}
+
+// hexActive reports whether the body sub-tab is set to the hex view,
+// regardless of which tab is focused
+func (m Model) hexActive() bool {
+	return m.responseSubTab == ResponseBodySubTab &&
+		m.responseView == HexView &&
+		m.responseBody != ""
+}
+
+// updateHex handles scrolling keys for the hex view. It reports whether the
+// key was consumed.
+func (m *Model) updateHex(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		m.scrollHex(m.hexRowOffset - 1)
+	case key.Matches(msg, m.keys.Down):
+		m.scrollHex(m.hexRowOffset + 1)
+	case msg.String() == "pgup":
+		m.scrollHex(m.hexRowOffset - m.hexHeight())
+	case msg.String() == "pgdown":
+		m.scrollHex(m.hexRowOffset + m.hexHeight())
+	case msg.String() == "home" || msg.String() == "g":
+		m.scrollHex(0)
+	case msg.String() == "end" || msg.String() == "G":
+		m.scrollHex(m.hexRows())
+	default:
+		return false
+	}
+	return true
+}
+
+func (m Model) hexHeight() int {
+	return max(1, m.responseViewport.Height-2)
+}
+
+func (m Model) hexRows() int {
+	return (len(m.responseBody) + hexview.BytesPerLine - 1) / hexview.BytesPerLine
+}
+
+// scrollHex moves the first visible row, keeping the last page full
+func (m *Model) scrollHex(row int) {
+	m.hexRowOffset = max(0, min(row, m.hexRows()-m.hexHeight()))
+}
+
+// runHexSearch looks for the query as bytes in the raw body. Hex pairs are
+// decoded, quoted or other text is matched literally, and regex mode runs
+// the pattern over the raw bytes.
+func (m *Model) runHexSearch() {
+	data := []byte(m.responseBody)
+	query := m.searchInput.Value()
+
+	m.searchMatches, m.searchErr = nil, nil
+	m.searchIndex = 0
+	if query == "" {
+		return
+	}
+
+	if m.searchOptions.Regex {
+		re, err := search.Compile(query, m.searchOptions)
+		if err != nil {
+			m.searchErr = err
+			return
+		}
+		for _, loc := range re.FindAllIndex(data, -1) {
+			if loc[0] < loc[1] {
+				m.searchMatches = append(m.searchMatches, hexMatch(loc[0], loc[1]))
+			}
+		}
+	} else {
+		// Bytes given in hex are matched exactly
+		pattern, isHex := hexview.ParsePattern(query)
+		for _, offset := range hexview.Find(data, pattern, !m.searchOptions.CaseSensitive && !isHex) {
+			m.searchMatches = append(m.searchMatches, hexMatch(offset, offset+len(pattern)))
+		}
+	}
+
+	for i, match := range m.searchMatches {
+		if match.Line >= m.hexRowOffset {
+			m.searchIndex = i
+			break
+		}
+	}
+
+	m.scrollToMatch()
+}
+
+// hexMatch records a byte range as a match; Line is the dump row it starts on
+// and Start/End are byte offsets into the body
+func hexMatch(start, end int) search.Match {
+	return search.Match{Line: start / hexview.BytesPerLine, Start: start, End: end}
+}
+
+// renderHex renders the format summary and the visible rows of the dump
+func (m Model) renderHex() string {
+	data := []byte(m.responseBody)
+
+	format := m.hexFormat
+	if format == "" {
+		format = "unknown format"
+	}
+	info := fmt.Sprintf("%s • %s", format, formatBytes(int64(len(data))))
+	if rows := m.hexRows(); rows > m.hexHeight() {
+		info += fmt.Sprintf(" • offset %08x • row %d/%d", m.hexRowOffset*hexview.BytesPerLine, m.hexRowOffset+1, rows)
+	}
+
+	lines := []string{styles.InfoStyle.Render(info)}
+
+	end := min(m.hexRows(), m.hexRowOffset+m.hexHeight())
+	for row := m.hexRowOffset; row < end; row++ {
+		offset := row * hexview.BytesPerLine
+		chunk := data[offset:min(offset+hexview.BytesPerLine, len(data))]
+
+		line := styleHexLine(hexview.Line(chunk, offset))
+		if spans := m.hexSpans(offset, offset+len(chunk)); len(spans) > 0 {
+			line = search.Mark(line, spans)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// hexSpans converts the matches overlapping the row of bytes [start, end)
+// into column spans over both the hex and ASCII columns of that row
+func (m Model) hexSpans(start, end int) []search.Span {
+	first := sort.Search(len(m.searchMatches), func(i int) bool {
+		return m.searchMatches[i].End > start
+	})
+
+	var hexSpans, asciiSpans []search.Span
+	for i := first; i < len(m.searchMatches) && m.searchMatches[i].Start < end; i++ {
+		match := m.searchMatches[i]
+		from, to := max(match.Start, start)-start, min(match.End, end)-start
+
+		on, off := searchMatchOn, searchMatchOff
+		if i == m.searchIndex {
+			on, off = searchCurrentOn, searchCurrentOff
+		}
+
+		hexSpans = append(hexSpans, search.Span{
+			Start: hexview.HexColumn(from), End: hexview.HexColumn(to-1) + 2, On: on, Off: off,
+		})
+		asciiSpans = append(asciiSpans, search.Span{
+			Start: hexview.ASCIIColumn(from), End: hexview.ASCIIColumn(to), On: on, Off: off,
+		})
+	}
+
+	return append(hexSpans, asciiSpans...)
+}
+
+// styleHexLine colours the offset, byte and ASCII columns of a dump row
+func styleHexLine(line string) string {
+	gutter := strings.LastIndex(line, " |")
+	if len(line) < 8 || gutter < 8 {
+		return line
+	}
+	return hexOffsetStyle.Render(line[:8]) +
+		hexBytesStyle.Render(line[8:gutter]) +
+		hexASCIIStyle.Render(line[gutter:])
+}
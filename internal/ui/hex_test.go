@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/pixperk/quest/internal/config"
+	"github.com/pixperk/quest/internal/hexview"
+)
+
+// newTestModel builds a model from the default configuration
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+	m, err := NewModel(config.Config{Theme: config.DefaultTheme})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// inTempDir runs the rest of the test in an empty directory, where saved
+// requests and responses are written
+func inTempDir(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestUpdateHexScrolls(t *testing.T) {
+	tests := []struct {
+		name  string
+		start int
+		key   tea.KeyMsg
+		want  int
+	}{
+		{"down", 0, tea.KeyMsg{Type: tea.KeyDown}, 1},
+		{"up", 5, tea.KeyMsg{Type: tea.KeyUp}, 4},
+		{"up at the top", 0, tea.KeyMsg{Type: tea.KeyUp}, 0},
+		{"page down", 0, tea.KeyMsg{Type: tea.KeyPgDown}, 8},
+		{"end", 0, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")}, 92},
+		{"home", 40, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t)
+			m.activeTab = ResponseTab
+			m.responseSubTab = ResponseBodySubTab
+			m.responseView = HexView
+			m.responseBody = strings.Repeat("\x00", 100*hexview.BytesPerLine)
+			m.responseViewport.Height = 10
+			m.hexRowOffset = tt.start
+
+			updated, _ := m.Update(tt.key)
+			if got := updated.(Model).hexRowOffset; got != tt.want {
+				t.Errorf("hexRowOffset = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/jsonrpc"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// JSONRPCMethod is the pseudo-method that selects JSON-RPC mode. Calls are
+// POSTed in a JSON-RPC 2.0 envelope with ids numbered across the session.
+const JSONRPCMethod = "JSONRPC"
+
+var (
+	rpcResultStyle lipgloss.Style
+	rpcErrorStyle  lipgloss.Style
+)
+
+func init() {
+	styles.OnApply(func() {
+		rpcResultStyle = lipgloss.NewStyle().Foreground(styles.Success).Bold(true)
+		rpcErrorStyle = lipgloss.NewStyle().Foreground(styles.Danger).Bold(true)
+	})
+}
+
+func newRPCEditors() (textinput.Model, textarea.Model) {
+	method := textinput.New()
+	method.Prompt = "Method: "
+	method.Placeholder = "eth_getBalance"
+	method.Width = 40
+
+	params := textarea.New()
+	params.Placeholder = `["0x407d73d8a49eeb85d32cf465507dd71d507100c1", "latest"]`
+	params.ShowLineNumbers = false
+
+	return method, params
+}
+
+func (m Model) jsonRPCMode() bool {
+	return m.getSelectedMethod() == JSONRPCMethod
+}
+
+// rpcCalls is the batch plus the call in the editors, if it has a method
+func (m Model) rpcCalls() []jsonrpc.Call {
+	calls := append([]jsonrpc.Call(nil), m.rpcBatch...)
+	if strings.TrimSpace(m.rpcMethod.Value()) != "" {
+		calls = append(calls, jsonrpc.Call{Method: m.rpcMethod.Value(), Params: m.rpcParams.Value()})
+	}
+	return calls
+}
+
+// addRPCCall queues the call in the editors and clears them for the next
+func (m *Model) addRPCCall() {
+	call := jsonrpc.Call{Method: m.rpcMethod.Value(), Params: m.rpcParams.Value()}
+	if err := call.Validate(); err != nil {
+		m.notice = styles.ErrorStyle.Render("JSON-RPC: " + err.Error())
+		return
+	}
+
+	m.rpcBatch = append(m.rpcBatch, call)
+	m.rpcMethod.SetValue("")
+	m.rpcParams.SetValue("")
+	m.focused = 0
+	m.updateFocus()
+	m.notice = styles.InfoStyle.Render(fmt.Sprintf("Added %s to the batch (%d calls)", call.Method, len(m.rpcBatch)))
+}
+
+// buildRPCRequest wraps the calls in an envelope, numbering them from the
+// session's next id
+func (m Model) buildRPCRequest() (http.Request, error) {
+	body, err := jsonrpc.Envelope(m.rpcCalls(), m.rpcNextID)
+	if err != nil {
+		return http.Request{}, err
+	}
+
+	headers := make(map[string]string, len(m.requestHeaders)+1)
+	headers["Content-Type"] = "application/json"
+	for k, v := range m.requestHeaders {
+		headers[k] = v
+	}
+
+	return m.applyRequestOptions(http.Request{
+		Method:  "POST",
+		URL:     m.urlInput.Value(),
+		Headers: headers,
+		Body:    string(body),
+	}), nil
+}
+
+// sendRPC sends the calls and remembers which method each id belongs to
+func (m Model) sendRPC() (Model, tea.Cmd) {
+	req, err := m.buildRPCRequest()
+	if err != nil {
+		m.notice = styles.ErrorStyle.Render("JSON-RPC: " + err.Error())
+		return m, nil
+	}
+
+	calls := m.rpcCalls()
+	m.rpcSent = make(map[string]string, len(calls))
+	for i, call := range calls {
+		m.rpcSent[fmt.Sprint(m.rpcNextID+i)] = strings.TrimSpace(call.Method)
+	}
+	m.rpcNextID += len(calls)
+
+	closing := m.closeSocket()
+	m.socketMode = false
+	m.cancelGRPC()
+	m.grpcMode = false
+	m.resetStream()
+	m, cmd := m.send(req)
+	return m, tea.Batch(closing, cmd)
+}
+
+// loadRPCCalls puts saved calls back in the editors: the last one in the
+// method and params editors and the rest in the batch
+func (m *Model) loadRPCCalls(body string) {
+	calls, err := jsonrpc.ParseEnvelope(body)
+	if err != nil || len(calls) == 0 {
+		return
+	}
+	last := calls[len(calls)-1]
+	m.rpcBatch = calls[:len(calls)-1]
+	m.rpcMethod.SetValue(last.Method)
+	m.rpcParams.SetValue(last.Params)
+}
+
+// renderRPCResponses shows each response's result or error, matched to the
+// method it answers, in request order
+func (m Model) renderRPCResponses(responses []jsonrpc.Response) string {
+	var results, errors int
+	for _, response := range responses {
+		if response.Error != nil {
+			errors++
+		} else {
+			results++
+		}
+	}
+
+	summary := rpcResultStyle.Render(fmt.Sprintf("%d results", results))
+	if errors > 0 {
+		summary += "  " + rpcErrorStyle.Render(fmt.Sprintf("%d errors", errors))
+	}
+	if missing := len(m.rpcSent) - len(responses); missing > 0 {
+		summary += "  " + styles.HelpStyle.Render(fmt.Sprintf("%d unanswered", missing))
+	}
+
+	sections := []string{summary}
+	for _, response := range responses {
+		sections = append(sections, "", m.renderRPCResponse(response))
+	}
+	return strings.Join(sections, "\n")
+}
+
+func (m Model) renderRPCResponse(response jsonrpc.Response) string {
+	label := "#" + response.IDString()
+	if method, ok := m.rpcSent[response.IDString()]; ok {
+		label += " " + method
+	}
+
+	if response.Error == nil {
+		header := rpcResultStyle.Render("✓ "+label) + " " + styles.HelpStyle.Render("result")
+		return header + "\n" + m.highlighter.Highlight(string(response.Result), "application/json")
+	}
+
+	e := response.Error
+	lines := []string{
+		rpcErrorStyle.Render("✗ "+label) + " " +
+			styles.ErrorStyle.Render(fmt.Sprintf("error %d", e.Code)) + " " +
+			styles.HelpStyle.Render("("+jsonrpc.CodeName(e.Code)+")"),
+		styles.ErrorStyle.Render(e.Message),
+	}
+	if len(e.Data) > 0 && string(e.Data) != "null" {
+		lines = append(lines, styles.HelpStyle.Render("data:"), m.highlighter.Highlight(string(e.Data), "application/json"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderRPCBody renders the call editors and the queued batch
+func (m Model) renderRPCBody() string {
+	methodInput := styles.BlurredStyle.Render(m.rpcMethod.View())
+	paramsEditor := styles.BlurredStyle.Render(m.rpcParams.View())
+	if m.focused == 0 {
+		methodInput = styles.FocusedStyle.Render(m.rpcMethod.View())
+	} else {
+		paramsEditor = styles.FocusedStyle.Render(m.rpcParams.View())
+	}
+
+	rows := []string{
+		styles.HeaderStyle.Render("JSON-RPC Call"),
+		styles.HelpStyle.Render(fmt.Sprintf("Next id: %d • Tab: Switch field • Ctrl+A: Add to batch • Ctrl+X: Clear batch • Ctrl+S: Send", m.rpcNextID)),
+		"",
+		methodInput,
+		styles.InfoStyle.Render("Params (JSON array or object, optional)"),
+		paramsEditor,
+	}
+
+	if len(m.rpcBatch) > 0 {
+		rows = append(rows, "", styles.InfoStyle.Render(fmt.Sprintf("Batch (%d calls, sent before the call above)", len(m.rpcBatch))))
+		for i, call := range m.rpcBatch {
+			params := strings.Join(strings.Fields(call.Params), " ")
+			if len(params) > 60 {
+				params = params[:57] + "..."
+			}
+			rows = append(rows, fmt.Sprintf("  %d. %s %s", i+1, styles.InfoStyle.Render(call.Method), styles.HelpStyle.Render(params)))
+		}
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
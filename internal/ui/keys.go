@@ -1,6 +1,14 @@
 package ui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+)
 
 type KeyMap struct {
 	Up              key.Binding
@@ -22,6 +30,40 @@ type KeyMap struct {
 	PrevResponseTab key.Binding
 	NextFocus       key.Binding
 	PrevFocus       key.Binding
+	Search          key.Binding
+	NextMatch       key.Binding
+	PrevMatch       key.Binding
+	ToggleCase      key.Binding
+	ToggleRegex     key.Binding
+	CycleView       key.Binding
+	ToggleNode      key.Binding
+	ExpandAll       key.Binding
+	CollapseAll     key.Binding
+	ExpandDepth     key.Binding
+	CopyValue       key.Binding
+	CopyPath        key.Binding
+	SortColumn      key.Binding
+	ToggleColumn    key.Binding
+	ShowAllColumns  key.Binding
+	ExportCSV       key.Binding
+	SaveResponse    key.Binding
+	PauseStream     key.Binding
+	StopStream      key.Binding
+	Reconnect       key.Binding
+	FrameFormat     key.Binding
+	Ping            key.Binding
+	Disconnect      key.Binding
+	FetchSchema     key.Binding
+	Complete        key.Binding
+	LoadProtos      key.Binding
+	AddCall         key.Binding
+	ClearBatch      key.Binding
+	BodyMode        key.Binding
+	ContentType     key.Binding
+	AddField        key.Binding
+	FileField       key.Binding
+	ClearFields     key.Binding
+	CycleTheme      key.Binding
 }
 
 func (k KeyMap) ShortHelp() []key.Binding {
@@ -34,12 +76,78 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 		{k.Tab, k.NextTab, k.PrevTab},
 		{k.NextFocus, k.PrevFocus},
 		{k.NextResponseTab, k.PrevResponseTab},
+		{k.Search, k.NextMatch, k.PrevMatch, k.ToggleCase, k.ToggleRegex},
+		{k.CycleView, k.ToggleNode, k.ExpandDepth, k.ExpandAll, k.CollapseAll, k.CopyValue, k.CopyPath},
+		{k.SortColumn, k.ToggleColumn, k.ShowAllColumns, k.ExportCSV},
+		{k.PauseStream, k.StopStream, k.Reconnect},
+		{k.FrameFormat, k.Ping, k.Disconnect},
+		{k.FetchSchema, k.Complete, k.LoadProtos},
+		{k.AddCall, k.ClearBatch},
+		{k.BodyMode, k.ContentType, k.AddField, k.FileField, k.ClearFields},
 		{k.Send, k.AddHeader, k.ClearHeaders, k.Enter},
-		{k.SaveRequest, k.LoadRequest},
-		{k.Help, k.Quit},
+		{k.SaveRequest, k.LoadRequest, k.SaveResponse},
+		{k.CycleTheme, k.Help, k.Quit},
 	}
 }
 
+// Rebind returns k with the named actions bound to other keys, keeping
+// their help text; an empty list unbinds the action. Actions are named as
+// the KeyMap fields in snake case, e.g. save_request.
+func (k KeyMap) Rebind(bindings map[string][]string) (KeyMap, error) {
+	actions := k.actions()
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		binding, ok := actions[name]
+		if !ok {
+			return k, fmt.Errorf("keys: unknown action %q", name)
+		}
+		keys := bindings[name]
+		if name == "expand_depth" {
+			// The key pressed is the depth to expand to
+			for _, pressed := range keys {
+				if len(pressed) != 1 || pressed[0] < '0' || pressed[0] > '9' {
+					return k, fmt.Errorf("keys: expand_depth takes digits, not %q", pressed)
+				}
+			}
+		}
+		if len(keys) == 0 {
+			*binding = key.NewBinding(key.WithDisabled())
+			continue
+		}
+		*binding = key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(keys, "/"), binding.Help().Desc))
+	}
+	return k, nil
+}
+
+// actions maps each action's name to its binding in k
+func (k *KeyMap) actions() map[string]*key.Binding {
+	actions := make(map[string]*key.Binding)
+	v := reflect.ValueOf(k).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		actions[snakeCase(v.Type().Field(i).Name)] = v.Field(i).Addr().Interface().(*key.Binding)
+	}
+	return actions
+}
+
+// snakeCase turns a field name like ExportCSV into export_csv
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
 var DefaultKeys = KeyMap{
 	Up: key.NewBinding(
 		key.WithKeys("up", "k"),
@@ -109,6 +217,142 @@ var DefaultKeys = KeyMap{
 		key.WithKeys("alt+left", "alt+h"),
 		key.WithHelp("alt+←", "prev focus"),
 	),
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search response"),
+	),
+	NextMatch: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next match"),
+	),
+	PrevMatch: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "prev match"),
+	),
+	ToggleCase: key.NewBinding(
+		key.WithKeys("alt+c"),
+		key.WithHelp("alt+c", "toggle case sensitivity"),
+	),
+	ToggleRegex: key.NewBinding(
+		key.WithKeys("alt+r"),
+		key.WithHelp("alt+r", "toggle regex search"),
+	),
+	CycleView: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "switch response view"),
+	),
+	ToggleNode: key.NewBinding(
+		key.WithKeys("enter", " "),
+		key.WithHelp("enter", "expand/collapse node"),
+	),
+	ExpandAll: key.NewBinding(
+		key.WithKeys("*"),
+		key.WithHelp("*", "expand all"),
+	),
+	CollapseAll: key.NewBinding(
+		key.WithKeys("0"),
+		key.WithHelp("0", "collapse all"),
+	),
+	ExpandDepth: key.NewBinding(
+		key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"),
+		key.WithHelp("1-9", "expand to depth"),
+	),
+	CopyValue: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "copy node value"),
+	),
+	CopyPath: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "copy node path"),
+	),
+	SortColumn: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sort by column"),
+	),
+	ToggleColumn: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "hide/show column"),
+	),
+	ShowAllColumns: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "show all columns"),
+	),
+	ExportCSV: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "export table to CSV"),
+	),
+	SaveResponse: key.NewBinding(
+		key.WithKeys("ctrl+o"),
+		key.WithHelp("ctrl+o", "save response"),
+	),
+	PauseStream: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "pause/resume stream"),
+	),
+	StopStream: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "stop stream"),
+	),
+	Reconnect: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "reconnect stream"),
+	),
+	FrameFormat: key.NewBinding(
+		key.WithKeys("ctrl+f"),
+		key.WithHelp("ctrl+f", "cycle websocket frame format"),
+	),
+	Ping: key.NewBinding(
+		key.WithKeys("ctrl+p"),
+		key.WithHelp("ctrl+p", "ping websocket"),
+	),
+	Disconnect: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "close websocket"),
+	),
+	FetchSchema: key.NewBinding(
+		key.WithKeys("ctrl+g"),
+		key.WithHelp("ctrl+g", "fetch graphql schema / list grpc services"),
+	),
+	Complete: key.NewBinding(
+		key.WithKeys("ctrl+@", "ctrl+ "),
+		key.WithHelp("ctrl+space", "complete graphql field"),
+	),
+	LoadProtos: key.NewBinding(
+		key.WithKeys("ctrl+t"),
+		key.WithHelp("ctrl+t", "load .proto or descriptor set files"),
+	),
+	AddCall: key.NewBinding(
+		key.WithKeys("ctrl+a"),
+		key.WithHelp("ctrl+a", "add json-rpc call to batch"),
+	),
+	ClearBatch: key.NewBinding(
+		key.WithKeys("ctrl+x"),
+		key.WithHelp("ctrl+x", "clear json-rpc batch"),
+	),
+	BodyMode: key.NewBinding(
+		key.WithKeys("ctrl+b"),
+		key.WithHelp("ctrl+b", "cycle body mode (raw, multipart, url-encoded)"),
+	),
+	ContentType: key.NewBinding(
+		key.WithKeys("ctrl+y"),
+		key.WithHelp("ctrl+y", "cycle body content type"),
+	),
+	AddField: key.NewBinding(
+		key.WithKeys("ctrl+a"),
+		key.WithHelp("ctrl+a", "add form field"),
+	),
+	FileField: key.NewBinding(
+		key.WithKeys("ctrl+u"),
+		key.WithHelp("ctrl+u", "switch form field between text and file"),
+	),
+	ClearFields: key.NewBinding(
+		key.WithKeys("ctrl+x"),
+		key.WithHelp("ctrl+x", "clear form fields"),
+	),
+	CycleTheme: key.NewBinding(
+		key.WithKeys("f2"),
+		key.WithHelp("f2", "switch theme"),
+	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
 		key.WithHelp("?", "toggle help"),
@@ -0,0 +1,27 @@
+package ui
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name     string
+		bindings map[string][]string
+		ok       bool
+	}{
+		{"rebind", map[string][]string{"send": {"ctrl+s", "f5"}}, true},
+		{"unbind", map[string][]string{"ping": {}}, true},
+		{"depth digits", map[string][]string{"expand_depth": {"1", "2", "9"}}, true},
+		{"unknown action", map[string][]string{"launch": {"x"}}, false},
+		{"depth letter", map[string][]string{"expand_depth": {"d"}}, false},
+		{"depth without runes", map[string][]string{"expand_depth": {"ctrl+d"}}, false},
+		{"depth function key", map[string][]string{"expand_depth": {"f2"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DefaultKeys.Rebind(tt.bindings); (err == nil) != tt.ok {
+				t.Errorf("Rebind() = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
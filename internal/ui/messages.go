@@ -1,12 +1,118 @@
 package ui
 
-import "time"
+import (
+	"time"
+
+	"github.com/pixperk/quest/internal/graphql"
+	"github.com/pixperk/quest/internal/grpc"
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/ws"
+)
 
 type ResponseMessage struct {
 	StatusCode   int
+	Proto        string
+	URL          string
 	Headers      map[string]string
 	Body         string
 	ContentType  string
 	ResponseTime time.Duration
 	Error        error
+	BodySize     int64
+	BodyFile     string
+	Binary       bool
+	Stream       *http.EventStream
+
+	Redirects       []http.Hop
+	RedirectStopped bool
+	SetCookies      []http.SetCookie
+	Connection      *http.Connection
+	Attempts        []http.Attempt
+}
+
+// ProgressMessage reports how much of the response body has arrived
+type ProgressMessage struct {
+	Progress http.Progress
+}
+
+// ResponseSavedMessage reports the outcome of writing a response to disk
+type ResponseSavedMessage struct {
+	Path  string
+	Bytes int64
+	Err   error
+}
+
+// EventMessage carries one event from an open event stream
+type EventMessage struct {
+	Stream *http.EventStream
+	Event  http.Event
+}
+
+// StreamEndMessage reports that an event stream has closed
+type StreamEndMessage struct {
+	Stream *http.EventStream
+	Err    error
+}
+
+// SocketOpenedMessage reports the outcome of a WebSocket handshake
+type SocketOpenedMessage struct {
+	Conn      *ws.Conn
+	URL       string
+	Handshake ws.Handshake
+	Elapsed   time.Duration
+	Err       error
+}
+
+// SocketMessage carries a frame received on an open WebSocket
+type SocketMessage struct {
+	Conn    *ws.Conn
+	Message ws.Message
+}
+
+// SocketSentMessage reports the outcome of writing a frame
+type SocketSentMessage struct {
+	Conn    *ws.Conn
+	Message ws.Message
+	Err     error
+}
+
+// SocketClosedMessage reports that a WebSocket connection has ended
+type SocketClosedMessage struct {
+	Conn *ws.Conn
+	Err  error
+}
+
+// SchemaMessage carries the result of a GraphQL introspection query
+type SchemaMessage struct {
+	Schema *graphql.Schema
+	Err    error
+}
+
+// GRPCCatalogMessage carries services listed by reflection or loaded from
+// files; Target is empty for files
+type GRPCCatalogMessage struct {
+	Catalog *grpc.Catalog
+	Target  string
+	Err     error
+}
+
+// GRPCStartedMessage reports the outcome of starting a gRPC call, along
+// with the catalog if one had to be fetched for it
+type GRPCStartedMessage struct {
+	Call    *grpc.Call
+	Catalog *grpc.Catalog
+	Target  string
+	Started time.Time
+	Err     error
+}
+
+// GRPCMessage carries a response message of a gRPC call
+type GRPCMessage struct {
+	Call    *grpc.Call
+	Message grpc.Message
+}
+
+// GRPCEndMessage reports that a gRPC call has ended
+type GRPCEndMessage struct {
+	Call *grpc.Call
 }
@@ -1,18 +1,88 @@
 package ui
 
 import (
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/pixperk/quest/internal/http"
 	"github.com/pixperk/quest/internal/styles"
 )
 
+// Method groups, in the order the URL tab shows them
+const (
+	StandardGroup = "HTTP"
+	WebDAVGroup   = "WebDAV"
+	ModeGroup     = "Modes"
+	CustomGroup   = "Custom"
+)
+
+var methodGroups = []string{StandardGroup, WebDAVGroup, ModeGroup, CustomGroup}
+
 type HTTPMethod struct {
-	Name string
-	Desc string
+	Name  string
+	Desc  string
+	Group string
+}
+
+// defaultMethods are the methods offered before any custom ones are added
+func defaultMethods() []list.Item {
+	return []list.Item{
+		HTTPMethod{Name: "GET", Desc: "Retrieve data", Group: StandardGroup},
+		HTTPMethod{Name: "POST", Desc: "Create new resource", Group: StandardGroup},
+		HTTPMethod{Name: "PUT", Desc: "Update/replace resource", Group: StandardGroup},
+		HTTPMethod{Name: "DELETE", Desc: "Remove resource", Group: StandardGroup},
+		HTTPMethod{Name: "PATCH", Desc: "Partial update", Group: StandardGroup},
+		HTTPMethod{Name: "HEAD", Desc: "Headers only", Group: StandardGroup},
+		HTTPMethod{Name: "OPTIONS", Desc: "Available methods", Group: StandardGroup},
+		HTTPMethod{Name: "TRACE", Desc: "Echo the request", Group: StandardGroup},
+		HTTPMethod{Name: "PROPFIND", Desc: "Read properties", Group: WebDAVGroup},
+		HTTPMethod{Name: "PROPPATCH", Desc: "Change properties", Group: WebDAVGroup},
+		HTTPMethod{Name: "MKCOL", Desc: "Create collection", Group: WebDAVGroup},
+		HTTPMethod{Name: "COPY", Desc: "Copy resource", Group: WebDAVGroup},
+		HTTPMethod{Name: "MOVE", Desc: "Move resource", Group: WebDAVGroup},
+		HTTPMethod{Name: "LOCK", Desc: "Lock resource", Group: WebDAVGroup},
+		HTTPMethod{Name: "UNLOCK", Desc: "Unlock resource", Group: WebDAVGroup},
+		HTTPMethod{Name: "REPORT", Desc: "Run a report", Group: WebDAVGroup},
+		HTTPMethod{Name: "SEARCH", Desc: "Search resources", Group: WebDAVGroup},
+		HTTPMethod{Name: WebSocketMethod, Desc: "WebSocket connection", Group: ModeGroup},
+		HTTPMethod{Name: GraphQLMethod, Desc: "GraphQL query", Group: ModeGroup},
+		HTTPMethod{Name: GRPCMethod, Desc: "gRPC call", Group: ModeGroup},
+		HTTPMethod{Name: JSONRPCMethod, Desc: "JSON-RPC 2.0 call", Group: ModeGroup},
+	}
+}
+
+// selectMethod selects the named method, adding it as a custom method if
+// it isn't listed yet
+func (m *Model) selectMethod(name string) {
+	items := m.methodList.Items()
+	for i, item := range items {
+		if method, ok := item.(HTTPMethod); ok && method.Name == name {
+			m.methodList.Select(i)
+			return
+		}
+	}
+	m.methodList.InsertItem(len(items), HTTPMethod{Name: name, Desc: "Custom method", Group: CustomGroup})
+	m.methodList.Select(len(items))
+}
+
+// addCustomMethod selects the method typed in the custom method input
+func (m *Model) addCustomMethod() {
+	name := strings.TrimSpace(m.methodInput.Value())
+	if err := http.ValidateMethod(name); err != nil {
+		m.notice = styles.ErrorStyle.Render("Method: " + err.Error())
+		return
+	}
+
+	m.selectMethod(name)
+	m.methodInput.SetValue("")
+	m.focused = 1
+	m.updateFocus()
+	m.notice = styles.InfoStyle.Render(fmt.Sprintf("Selected custom method %s", name))
 }
 
 func (m HTTPMethod) FilterValue() string {
@@ -51,9 +121,7 @@ func (d MethodDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 	var style lipgloss.Style
 	if index == m.Index() {
 		// Selected item style
-		style = lipgloss.NewStyle().
-			Foreground(styles.White).
-			Background(styles.HotPink).
+		style = styles.SelectedStyle().
 			Padding(0, 1).
 			Bold(true)
 	} else {
@@ -65,3 +133,51 @@ func (d MethodDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 
 	w.Write([]byte(style.Render(method.Name)))
 }
+
+// renderMethodRows lays out the method buttons a group per row, wrapping
+// groups that don't fit the terminal
+func (m Model) renderMethodRows() string {
+	selected := m.getSelectedMethod()
+	labelWidth := 0
+	for _, group := range methodGroups {
+		labelWidth = max(labelWidth, lipgloss.Width(group))
+	}
+	maxWidth := max(m.width-labelWidth-8, 20)
+
+	var rows []string
+	for _, group := range methodGroups {
+		var buttons []string
+		for _, item := range m.methodList.Items() {
+			method, ok := item.(HTTPMethod)
+			if !ok || method.Group != group {
+				continue
+			}
+			switch {
+			case method.Name == selected && m.focused == 1:
+				buttons = append(buttons, styles.ActiveTabStyle.Render(" "+method.Name+" "))
+			case method.Name == selected:
+				buttons = append(buttons, styles.TabStyle.Render(" "+method.Name+" "))
+			default:
+				buttons = append(buttons, styles.BlurredStyle.Render(" "+method.Name+" "))
+			}
+		}
+		if len(buttons) == 0 {
+			continue
+		}
+
+		label := styles.HelpStyle.Copy().Width(labelWidth + 1).Render(group)
+		var line []string
+		lineWidth := 0
+		for _, button := range buttons {
+			if lineWidth > 0 && lineWidth+lipgloss.Width(button) > maxWidth {
+				rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Center, append([]string{label}, line...)...))
+				label = strings.Repeat(" ", labelWidth+1)
+				line, lineWidth = nil, 0
+			}
+			line = append(line, button)
+			lineWidth += lipgloss.Width(button)
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Center, append([]string{label}, line...)...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectMethod(t *testing.T) {
+	m := newTestModel(t)
+	count := len(m.methodList.Items())
+
+	m.selectMethod("PROPFIND")
+	if got := m.getSelectedMethod(); got != "PROPFIND" {
+		t.Errorf("selected %q, want PROPFIND", got)
+	}
+	if len(m.methodList.Items()) != count {
+		t.Errorf("selecting a listed method added an item")
+	}
+
+	m.selectMethod("PURGE")
+	m.selectMethod("GET")
+	m.selectMethod("PURGE")
+	if got := m.getSelectedMethod(); got != "PURGE" {
+		t.Errorf("selected %q, want PURGE", got)
+	}
+	items := m.methodList.Items()
+	if len(items) != count+1 {
+		t.Fatalf("%d methods after adding PURGE twice, want %d", len(items), count+1)
+	}
+	if method := items[count].(HTTPMethod); method.Name != "PURGE" || method.Group != CustomGroup {
+		t.Errorf("added %+v, want PURGE in the custom group", method)
+	}
+}
+
+func TestAddCustomMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		selected string
+		notice   string
+	}{
+		{" PURGE ", "PURGE", "Selected custom method PURGE"},
+		{"BAD METHOD", "GET", "Method: method cannot contain ' '"},
+		{"", "GET", "Method: method cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		m := newTestModel(t)
+		m.methodInput.SetValue(tt.input)
+		m.addCustomMethod()
+
+		if got := m.getSelectedMethod(); got != tt.selected {
+			t.Errorf("addCustomMethod(%q) selected %q, want %q", tt.input, got, tt.selected)
+		}
+		if !strings.Contains(m.notice, tt.notice) {
+			t.Errorf("addCustomMethod(%q) notice = %q, want it to mention %q", tt.input, m.notice, tt.notice)
+		}
+	}
+}
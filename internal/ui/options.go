@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// optionsFocus is the URL tab focus index of the request options panel
+const optionsFocus = 3
+
+// maxRedirectLimit is the highest redirect limit the options panel offers
+const maxRedirectLimit = 50
+
+// requestOption is a per-request setting on the URL tab's options panel
+type requestOption struct {
+	name  string
+	value func(m Model) string
+	// change steps the setting by delta, which is -1 or +1
+	change func(m *Model, delta int)
+
+	// Text settings are typed into an editor instead: text is what the
+	// editor starts with and edit stores what was entered
+	text func(m Model) string
+	edit func(m *Model, value string) error
+	// path completes file paths in the editor; secret hides what's typed
+	path   bool
+	secret bool
+}
+
+var requestOptions = concatOptions(redirectOptions, retryOptions, tlsOptions, proxyOptions, targetOptions)
+
+func concatOptions(groups ...[]requestOption) []requestOption {
+	var options []requestOption
+	for _, group := range groups {
+		options = append(options, group...)
+	}
+	return options
+}
+
+var redirectOptions = []requestOption{
+	{
+		name:   "Follow redirects",
+		value:  func(m Model) string { return onOff(!m.redirectPolicy.NoFollow) },
+		change: func(m *Model, _ int) { m.redirectPolicy.NoFollow = !m.redirectPolicy.NoFollow },
+	},
+	{
+		name:  "Max redirects",
+		value: func(m Model) string { return fmt.Sprint(m.redirectPolicy.Limit()) },
+		change: func(m *Model, delta int) {
+			m.redirectPolicy.MaxHops = min(max(m.redirectPolicy.Limit()+delta, 1), maxRedirectLimit)
+		},
+	},
+	{
+		name:   "Keep method on 303",
+		value:  func(m Model) string { return onOff(m.redirectPolicy.KeepMethod) },
+		change: func(m *Model, _ int) { m.redirectPolicy.KeepMethod = !m.redirectPolicy.KeepMethod },
+	},
+}
+
+// applyRequestOptions adds the options panel's settings to req
+func (m Model) applyRequestOptions(req http.Request) http.Request {
+	req.Redirects = m.redirectPolicy
+	req.TLS = m.tlsConfig
+	req.Proxy = m.proxyConfig
+	req.Target = m.target
+	req.Retry = m.retryPolicy
+	return req
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// updateOptions moves through and changes the request options
+func (m *Model) updateOptions(msg tea.KeyMsg) tea.Cmd {
+	option := requestOptions[m.optionIndex]
+	switch msg.String() {
+	case "up", "k":
+		m.optionIndex = (m.optionIndex + len(requestOptions) - 1) % len(requestOptions)
+	case "down", "j":
+		m.optionIndex = (m.optionIndex + 1) % len(requestOptions)
+	case "left", "h":
+		if option.change != nil {
+			option.change(m, -1)
+		}
+	case "right", "l", " ", "enter":
+		if option.edit != nil {
+			return m.startOptionEdit(option)
+		}
+		option.change(m, 1)
+	}
+	return nil
+}
+
+// startOptionEdit opens the editor for a text setting
+func (m *Model) startOptionEdit(option requestOption) tea.Cmd {
+	m.editingOption = true
+	m.optionInput.Prompt = option.name + ": "
+	m.optionInput.EchoMode = textinput.EchoNormal
+	if option.secret {
+		m.optionInput.EchoMode = textinput.EchoPassword
+	}
+	m.optionInput.ShowSuggestions = option.path
+	m.optionInput.SetValue(option.text(*m))
+	m.optionInput.CursorEnd()
+	return m.optionInput.Focus()
+}
+
+// updateOptionEdit handles the text setting editor; an empty value clears
+// the setting
+func (m Model) updateOptionEdit(msg tea.KeyMsg) (Model, tea.Cmd) {
+	option := requestOptions[m.optionIndex]
+	switch msg.String() {
+	case "esc":
+		m.editingOption = false
+		m.optionInput.Blur()
+		return m, nil
+	case "enter":
+		if err := option.edit(&m, m.optionInput.Value()); err != nil {
+			m.notice = styles.ErrorStyle.Render(option.name + ": " + err.Error())
+			return m, nil
+		}
+		m.notice = ""
+		m.editingOption = false
+		m.optionInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.optionInput, cmd = m.optionInput.Update(msg)
+	if option.path {
+		m.optionInput.SetSuggestions(pathSuggestions(m.optionInput.Value()))
+	}
+	return m, cmd
+}
+
+// renderOptions renders the request options panel
+func (m Model) renderOptions() string {
+	nameWidth := 0
+	for _, option := range requestOptions {
+		nameWidth = max(nameWidth, lipgloss.Width(option.name))
+	}
+
+	// Keep the selection in view when the panel doesn't fit
+	height := min(max(m.height-46, 5), len(requestOptions))
+	start := max(0, min(m.optionIndex-height/2, len(requestOptions)-height))
+	end := start + height
+
+	rows := []string{styles.HeaderStyle.Render("Request Options")}
+	for i, option := range requestOptions[start:end] {
+		i += start
+		name := option.name + strings.Repeat(" ", nameWidth-lipgloss.Width(option.name))
+		value := "◂ " + option.value(m) + " ▸"
+		if option.edit != nil {
+			value = "[ " + option.value(m) + " ]"
+		}
+		if m.focused == optionsFocus && i == m.optionIndex {
+			rows = append(rows, styles.InfoStyle.Render("▸ "+name+"  ")+styles.JsonStyle.Render(value))
+		} else {
+			rows = append(rows, styles.HelpStyle.Render("  "+name+"  "+value))
+		}
+	}
+	if height < len(requestOptions) {
+		rows = append(rows, styles.HelpStyle.Render(fmt.Sprintf("  ↕ %d–%d of %d", start+1, end, len(requestOptions))))
+	}
+	if m.editingOption {
+		rows = append(rows, styles.FocusedStyle.Render(m.optionInput.View()),
+			styles.HelpStyle.Render("Enter: Set (empty clears) • Esc: Cancel"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// proxyOptions are the per-request proxy settings on the options panel
+var proxyOptions = []requestOption{
+	{
+		name: "Proxy",
+		value: func(m Model) string {
+			return inherited(describeProxy(m.proxyConfig), describeProxy(m.httpClient.Proxy), environmentProxy())
+		},
+		text: func(m Model) string {
+			if m.proxyConfig.Direct {
+				return "direct"
+			}
+			return m.proxyConfig.URL
+		},
+		edit: func(m *Model, value string) error {
+			value = strings.TrimSpace(value)
+			switch strings.ToLower(value) {
+			case "":
+				m.proxyConfig.URL, m.proxyConfig.Direct = "", false
+			case "direct", "none":
+				m.proxyConfig.URL, m.proxyConfig.Direct = "", true
+			default:
+				proxyURL, err := http.ParseProxyURL(value)
+				if err != nil {
+					return err
+				}
+				m.proxyConfig.URL, m.proxyConfig.Direct = proxyURL.String(), false
+			}
+			return nil
+		},
+	},
+	{
+		name: "No proxy",
+		value: func(m Model) string {
+			return inherited(m.proxyConfig.NoProxy, m.httpClient.Proxy.NoProxy, environmentNoProxy())
+		},
+		text: func(m Model) string { return m.proxyConfig.NoProxy },
+		edit: func(m *Model, value string) error {
+			m.proxyConfig.NoProxy = strings.Join(strings.Fields(strings.ReplaceAll(value, ",", " ")), ",")
+			return nil
+		},
+	},
+}
+
+// describeProxy shows a proxy setting without its password
+func describeProxy(config http.ProxyConfig) string {
+	if config.Direct {
+		return "direct"
+	}
+	if u, err := url.Parse(config.URL); err == nil && config.URL != "" {
+		return u.Redacted()
+	}
+	return config.URL
+}
+
+// withoutCredentials removes any user:password@ from a proxy URL, keeping
+// the rest as typed
+func withoutCredentials(raw string) string {
+	full := raw
+	if !strings.Contains(raw, "://") {
+		full = "http://" + raw
+	}
+	u, err := url.Parse(full)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	u.User = nil
+	if full != raw {
+		return strings.TrimPrefix(u.String(), "http://")
+	}
+	return u.String()
+}
+
+// environmentProxy names the proxy environment variables in effect
+func environmentProxy() string {
+	var set []string
+	for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY"} {
+		if os.Getenv(name) != "" || os.Getenv(strings.ToLower(name)) != "" {
+			set = append(set, "$"+name)
+		}
+	}
+	if len(set) == 0 {
+		return "none"
+	}
+	return strings.Join(set, ", ")
+}
+
+func environmentNoProxy() string {
+	if os.Getenv("NO_PROXY") != "" || os.Getenv("no_proxy") != "" {
+		return "$NO_PROXY"
+	}
+	return "none"
+}
+
+// renderProxyStatus shows the proxy the current URL would go through
+func (m Model) renderProxyStatus() string {
+	if m.urlInput.Value() == "" {
+		return ""
+	}
+	proxy, err := m.httpClient.Proxy.Merge(m.proxyConfig).ProxyFor(m.urlInput.Value())
+	switch {
+	case err != nil:
+		return styles.ErrorStyle.Render("Proxy: " + err.Error())
+	case proxy != "":
+		return styles.InfoStyle.Render("⇄ via " + proxy)
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// renderRedirectSummary notes the redirects behind the response, or that
+// the response is a redirect that wasn't followed
+func (m Model) renderRedirectSummary() string {
+	if m.socketMode || m.grpcMode {
+		return ""
+	}
+
+	var parts []string
+	if hops := len(m.responseRedirects); hops > 0 {
+		noun := "redirects"
+		if hops == 1 {
+			noun = "redirect"
+		}
+		parts = append(parts, styles.InfoStyle.Render(fmt.Sprintf("↪ Followed %d %s", hops, noun)))
+	}
+	if m.redirectStopped {
+		reason := "following is off"
+		if !m.redirectPolicy.NoFollow {
+			reason = fmt.Sprintf("limit of %d reached", m.redirectPolicy.Limit())
+		}
+		stopped := "⤼ Redirect not followed (" + reason + ")"
+		if location := m.responseHeaders["Location"]; location != "" {
+			stopped += " → " + location
+		}
+		parts = append(parts, styles.ErrorStyle.Render(stopped))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, "  ") + styles.HelpStyle.Render(" • Headers tab shows the chain")
+}
+
+// formatRedirectChain lists each redirect followed with its status, timing
+// and headers, ahead of the final response's headers
+func (m Model) formatRedirectChain() string {
+	if len(m.responseRedirects) == 0 {
+		return ""
+	}
+
+	lines := []string{styles.HeaderStyle.Render(fmt.Sprintf("Redirect Chain (%d hops)", len(m.responseRedirects)))}
+	for i, hop := range m.responseRedirects {
+		lines = append(lines, "", formatHop(i+1, hop))
+	}
+
+	final := fmt.Sprintf("%d. %s %s", len(m.responseRedirects)+1,
+		styles.StyledStatusCode(m.statusCode), styles.InfoStyle.Render(m.responseURL))
+	lines = append(lines, "", final+styles.HelpStyle.Render(" (final response)"), "", styles.HeaderStyle.Render("Response Headers"))
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func formatHop(n int, hop http.Hop) string {
+	lines := []string{
+		fmt.Sprintf("%d. %s %s %s %s", n,
+			styles.StyledStatusCode(hop.StatusCode),
+			styles.HelpStyle.Render(nethttp.StatusText(hop.StatusCode)),
+			styles.StyledMethod(hop.Method),
+			styles.InfoStyle.Render(hop.URL)) +
+			styles.HelpStyle.Render(" "+hop.Duration.Round(time.Microsecond).String()),
+		"   → " + styles.JsonStyle.Render(hop.Location),
+	}
+
+	keys := make([]string, 0, len(hop.Headers))
+	for key := range hop.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lines = append(lines, "   "+styles.InfoStyle.Render(key)+": "+styles.JsonStyle.Render(hop.Headers[key]))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/graphql"
+	"github.com/pixperk/quest/internal/jsonrpc"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+var responseViewNames = map[ResponseView]string{
+	PrettyView: "Pretty",
+	TreeView:   "Tree",
+	TableView:  "Table",
+	HexView:    "Hex",
+}
+
+// availableResponseViews lists the body views that make sense for the
+// current response, in cycling order
+func (m Model) availableResponseViews() []ResponseView {
+	views := []ResponseView{PrettyView}
+	if m.jsonTree != nil {
+		views = append(views, TreeView)
+	}
+	if m.table != nil {
+		views = append(views, TableView)
+	}
+	if m.responseBody != "" {
+		views = append(views, HexView)
+	}
+	return views
+}
+
+func (m Model) hasResponseView(view ResponseView) bool {
+	for _, available := range m.availableResponseViews() {
+		if available == view {
+			return true
+		}
+	}
+	return false
+}
+
+// cycleResponseView switches the body to the next available view
+func (m *Model) cycleResponseView() {
+	if m.responseSubTab != ResponseBodySubTab {
+		return
+	}
+
+	views := m.availableResponseViews()
+	next := views[0]
+	for i, view := range views {
+		if view == m.responseView {
+			next = views[(i+1)%len(views)]
+			break
+		}
+	}
+
+	m.responseView = next
+	if next == TreeView || next == TableView {
+		m.clearSearch()
+	} else {
+		m.runSearch()
+	}
+}
+
+func (m Model) showingTree() bool {
+	return m.activeTab == ResponseTab &&
+		m.responseSubTab == ResponseBodySubTab &&
+		m.responseView == TreeView &&
+		m.jsonTree != nil
+}
+
+func (m Model) showingTable() bool {
+	return m.activeTab == ResponseTab &&
+		m.responseSubTab == ResponseBodySubTab &&
+		m.responseView == TableView &&
+		m.table != nil
+}
+
+// searchable reports whether the active response pane is a text viewport or
+// the hex view, both of which can be searched
+func (m Model) searchable() bool {
+	if m.hexActive() {
+		return true
+	}
+	if m.response == "" {
+		return false
+	}
+	return m.responseSubTab != ResponseBodySubTab || m.responseView == PrettyView
+}
+
+// renderResponseViews renders the body view switcher
+func (m Model) renderResponseViews() string {
+	views := m.availableResponseViews()
+	if len(views) < 2 {
+		return ""
+	}
+
+	var names []string
+	for _, view := range views {
+		if view == m.responseView {
+			names = append(names, styles.InfoStyle.Copy().Bold(true).Render(responseViewNames[view]))
+		} else {
+			names = append(names, styles.HelpStyle.Render(responseViewNames[view]))
+		}
+	}
+
+	parts := []string{styles.HelpStyle.Render("View: ")}
+	for i, name := range names {
+		if i > 0 {
+			parts = append(parts, styles.HelpStyle.Render(" · "))
+		}
+		parts = append(parts, name)
+	}
+	parts = append(parts, styles.HelpStyle.Render("  (v to switch)"))
+
+	return lipgloss.JoinHorizontal(lipgloss.Left, parts...)
+}
+
+// renderResponseContent renders the body of the response, or the error that
+// stopped the request
+func (m Model) renderResponseContent() string {
+	switch {
+	case m.responseErr != nil:
+		return styles.ErrorStyle.Render("Error: " + m.responseErr.Error())
+	case m.responseBinary:
+		return m.renderBinarySummary()
+	}
+	// Only responses to those modes are read as JSON-RPC or GraphQL, since
+	// a REST API can answer with the same shape
+	switch m.responseMethod {
+	case JSONRPCMethod:
+		if responses, ok := jsonrpc.ParseResponse(m.responseBody); ok {
+			return m.renderRPCResponses(responses)
+		}
+	case GraphQLMethod:
+		if resp, ok := graphql.ParseResponse(m.responseBody); ok && len(resp.Errors) > 0 {
+			return m.renderGraphQLResponse(resp)
+		}
+	}
+	return m.highlighter.Highlight(m.responseBody, m.responseContentType)
+}
@@ -0,0 +1,37 @@
+package ui
+
+import "testing"
+
+func TestRenderResponseContentModes(t *testing.T) {
+	const (
+		rpcBody     = `{"jsonrpc": "2.0", "id": 1, "result": 3}`
+		graphQLBody = `{"data": null, "errors": [{"message": "boom"}]}`
+	)
+	tests := []struct {
+		name    string
+		method  string
+		body    string
+		special bool
+	}{
+		{"JSON-RPC response to a REST request", "GET", rpcBody, false},
+		{"GraphQL-shaped response to a REST request", "POST", graphQLBody, false},
+		{"JSON-RPC response in JSON-RPC mode", JSONRPCMethod, rpcBody, true},
+		{"GraphQL errors in GraphQL mode", GraphQLMethod, graphQLBody, true},
+		{"GraphQL response in JSON-RPC mode", JSONRPCMethod, graphQLBody, false},
+		{"plain JSON in GraphQL mode", GraphQLMethod, `{"id": 1}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t)
+			m.responseMethod = tt.method
+			m.responseBody = tt.body
+			m.responseContentType = "application/json"
+
+			plain := m.highlighter.Highlight(tt.body, "application/json")
+			if special := m.renderResponseContent() != plain; special != tt.special {
+				t.Errorf("rendered specially = %v, want %v", special, tt.special)
+			}
+		})
+	}
+}
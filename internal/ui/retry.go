@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// retryBackoffs are the first-retry delays the options panel steps through,
+// in milliseconds
+var retryBackoffs = []int{100, 250, 500, 1000, 2000, 5000}
+
+// retryOptions are the per-request retry settings on the options panel
+var retryOptions = []requestOption{
+	{
+		name: "Max attempts",
+		value: func(m Model) string {
+			if m.retryPolicy.Attempts() == 1 {
+				return "1 (no retries)"
+			}
+			return fmt.Sprint(m.retryPolicy.Attempts())
+		},
+		change: func(m *Model, delta int) {
+			m.retryPolicy.MaxAttempts = min(max(m.retryPolicy.Attempts()+delta, 1), http.MaxRetryAttempts)
+			if m.retryPolicy.MaxAttempts == 1 {
+				m.retryPolicy.MaxAttempts = 0
+			}
+		},
+	},
+	{
+		name: "Retry statuses",
+		value: func(m Model) string {
+			if m.retryPolicy.Statuses == "" {
+				return http.DefaultRetryStatuses + " (default)"
+			}
+			return m.retryPolicy.Statuses
+		},
+		text: func(m Model) string { return m.retryPolicy.Statuses },
+		edit: func(m *Model, value string) error {
+			policy := m.retryPolicy
+			policy.Statuses = strings.Join(strings.Fields(strings.ReplaceAll(value, ",", " ")), ",")
+			if err := policy.Validate(); err != nil {
+				return err
+			}
+			m.retryPolicy = policy
+			return nil
+		},
+	},
+	{
+		name:   "Retry network errors",
+		value:  func(m Model) string { return onOff(!m.retryPolicy.NoNetworkErrors) },
+		change: func(m *Model, _ int) { m.retryPolicy.NoNetworkErrors = !m.retryPolicy.NoNetworkErrors },
+	},
+	{
+		name:   "Retry non-idempotent",
+		value:  func(m Model) string { return onOff(m.retryPolicy.NonIdempotent) + " (POST, PATCH and others)" },
+		change: func(m *Model, _ int) { m.retryPolicy.NonIdempotent = !m.retryPolicy.NonIdempotent },
+	},
+	{
+		name:  "Backoff",
+		value: func(m Model) string { return m.retryPolicy.Backoff().String() + " doubling, with jitter" },
+		change: func(m *Model, delta int) {
+			i := 0
+			for j, backoff := range retryBackoffs {
+				if time.Duration(backoff)*time.Millisecond <= m.retryPolicy.Backoff() {
+					i = j
+				}
+			}
+			m.retryPolicy.BackoffMS = retryBackoffs[min(max(i+delta, 0), len(retryBackoffs)-1)]
+		},
+	},
+}
+
+// renderRetrySummary notes, above the response, that it took more than one
+// attempt
+func (m Model) renderRetrySummary() string {
+	if m.socketMode || m.grpcMode || len(m.responseAttempts) < 2 {
+		return ""
+	}
+	last := m.responseAttempts[len(m.responseAttempts)-1]
+	summary := styles.InfoStyle.Render(fmt.Sprintf("↻ Answered on attempt %d", len(m.responseAttempts)))
+	if last.GaveUp != "" {
+		summary = styles.ErrorStyle.Render("↻ " + strings.ToUpper(last.GaveUp[:1]) + last.GaveUp[1:])
+	}
+	return summary + styles.HelpStyle.Render(" • Timeline tab lists each attempt")
+}
+
+// renderRetryProgress shows which attempt is in flight, or the wait before
+// the next one, while a retried request is loading
+func (m Model) renderRetryProgress() string {
+	attempts := m.retryPolicy.Attempts()
+	if retry := m.progress.Retry; retry != nil {
+		return styles.InfoStyle.Render(fmt.Sprintf("Attempt %d of %d: ", m.progress.Attempt, attempts)) +
+			attemptOutcome(*retry) + styles.InfoStyle.Render(", retrying in "+retry.Wait.Round(time.Millisecond).String()+"...")
+	}
+	return styles.InfoStyle.Render(fmt.Sprintf("Sending attempt %d of %d...", m.progress.Attempt, attempts))
+}
+
+// formatTimeline lists each attempt at the request, when it was sent, how
+// it ended and how long was waited before the next
+func (m Model) formatTimeline() string {
+	if len(m.responseAttempts) == 0 {
+		return styles.HelpStyle.Render("No attempts recorded for this response")
+	}
+
+	noun := "attempts"
+	if len(m.responseAttempts) == 1 {
+		noun = "attempt"
+	}
+	lines := []string{styles.HeaderStyle.Render(fmt.Sprintf("Timeline (%d %s)", len(m.responseAttempts), noun))}
+	for i, attempt := range m.responseAttempts {
+		line := fmt.Sprintf("%d. %s  %s %s", i+1,
+			styles.HelpStyle.Render(fmt.Sprintf("+%-9s", attempt.Start.Round(time.Millisecond))),
+			attemptOutcome(attempt),
+			styles.HelpStyle.Render(attempt.Duration.Round(time.Microsecond).String()))
+		lines = append(lines, "", line)
+
+		switch {
+		case attempt.Wait > 0 && attempt.RetryAfter:
+			lines = append(lines, "   ↻ "+styles.JsonStyle.Render("waited "+attempt.Wait.String())+styles.HelpStyle.Render(" as Retry-After asked"))
+		case attempt.Wait > 0:
+			lines = append(lines, "   ↻ "+styles.JsonStyle.Render("waited "+attempt.Wait.Round(time.Millisecond).String())+styles.HelpStyle.Render(" backing off"))
+		case attempt.GaveUp != "":
+			lines = append(lines, "   "+styles.ErrorStyle.Render("✖ "+attempt.GaveUp))
+		case i == len(m.responseAttempts)-1:
+			lines = append(lines, "   "+styles.HelpStyle.Render("(final response)"))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// attemptOutcome is an attempt's status, or its error
+func attemptOutcome(attempt http.Attempt) string {
+	if attempt.Err != nil {
+		return styles.ErrorStyle.Render(attempt.Err.Error())
+	}
+	return styles.StyledStatusCode(attempt.StatusCode) + " " + styles.HelpStyle.Render(nethttp.StatusText(attempt.StatusCode))
+}
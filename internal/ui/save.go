@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// startSaveResponse opens the save prompt with a suggested filename
+func (m Model) startSaveResponse() (Model, tea.Cmd) {
+	m.savingResponse = true
+	m.saveConfirm = ""
+	m.saveInput.SetValue(http.SuggestFilename(m.responseHeaders, m.responseURL, m.responseContentType))
+	m.saveInput.CursorEnd()
+	return m, m.saveInput.Focus()
+}
+
+// updateSaveResponse handles key presses while the save prompt is active
+func (m Model) updateSaveResponse(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case msg.String() == "esc":
+		m.savingResponse = false
+		m.saveInput.Blur()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Tab):
+		m.saveHeaders = !m.saveHeaders
+		m.saveConfirm = ""
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		path, err := expandHome(strings.TrimSpace(m.saveInput.Value()))
+		if err != nil {
+			m.notice = styles.ErrorStyle.Render("Save failed: " + err.Error())
+			return m, nil
+		}
+		if path == "" {
+			return m, nil
+		}
+
+		// Ask once before replacing an existing file
+		if _, err := os.Stat(path); err == nil && m.saveConfirm != path {
+			m.saveConfirm = path
+			m.notice = styles.ErrorStyle.Render(path + " already exists • Enter again to overwrite")
+			return m, nil
+		}
+
+		m.savingResponse = false
+		m.saveInput.Blur()
+		m.notice = styles.InfoStyle.Render("Saving to " + path + "...")
+		return m, m.saveResponse(path)
+	}
+
+	var cmd tea.Cmd
+	m.saveInput, cmd = m.saveInput.Update(msg)
+	m.saveConfirm = ""
+	return m, cmd
+}
+
+// saveResponse writes the raw body, and optionally the status line and
+// headers, to path. Spilled bodies are copied from their temp file so the
+// saved file is always complete.
+func (m Model) saveResponse(path string) tea.Cmd {
+	var head string
+	if m.saveHeaders {
+		head = http.FormatHead(m.responseProto, m.statusCode, m.responseHeaders)
+	}
+	body, bodyFile := m.responseBody, m.responseFile
+
+	return func() tea.Msg {
+		written, err := writeResponse(path, head, body, bodyFile)
+		return ResponseSavedMessage{Path: path, Bytes: written, Err: err}
+	}
+}
+
+func writeResponse(path, head, body, bodyFile string) (int64, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return 0, err
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	n, err := io.WriteString(f, head)
+	written := int64(n)
+	if err != nil {
+		return written, err
+	}
+
+	var src io.Reader = strings.NewReader(body)
+	if bodyFile != "" {
+		spilled, err := os.Open(bodyFile)
+		if err != nil {
+			return written, err
+		}
+		defer spilled.Close()
+		src = spilled
+	}
+
+	copied, err := io.Copy(f, src)
+	written += copied
+	if err != nil {
+		return written, err
+	}
+
+	return written, f.Close()
+}
+
+// expandHome resolves a leading ~ to the user's home directory
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// handleResponseSaved reports where the response went, or why it didn't
+func (m *Model) handleResponseSaved(msg ResponseSavedMessage) {
+	if msg.Err != nil {
+		m.notice = styles.ErrorStyle.Render("Save failed: " + msg.Err.Error())
+		return
+	}
+	m.notice = styles.StatusStyle.Render("Saved " + formatBytes(msg.Bytes) + " to " + msg.Path)
+}
+
+// renderSavePrompt renders the filename prompt and the headers toggle
+func (m Model) renderSavePrompt() string {
+	if !m.savingResponse {
+		return ""
+	}
+
+	headers := styles.HelpStyle.Render("[ ] include headers")
+	if m.saveHeaders {
+		headers = styles.InfoStyle.Copy().Bold(true).Render("[x] include headers")
+	}
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		m.saveInput.View(),
+		"  ",
+		headers,
+		"  ",
+		styles.HelpStyle.Render("Tab: toggle headers • Enter: save • Esc: cancel"),
+	)
+}
@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/search"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// SGR sequences used to mark matches. Only reverse video and underline are
+// toggled so the syntax colours underneath survive.
+const (
+	searchMatchOn    = "\x1b[7m"
+	searchMatchOff   = "\x1b[27m"
+	searchCurrentOn  = "\x1b[4;7m"
+	searchCurrentOff = "\x1b[24;27m"
+)
+
+func (m Model) startSearch() (Model, tea.Cmd) {
+	m.searching = true
+	m.searchInput.CursorEnd()
+	return m, m.searchInput.Focus()
+}
+
+// updateSearch handles key presses while the search prompt is active
+func (m Model) updateSearch(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case msg.String() == "esc":
+		m.searching = false
+		m.searchInput.Blur()
+		m.clearSearch()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		m.searching = false
+		m.searchInput.Blur()
+		return m, nil
+
+	case key.Matches(msg, m.keys.ToggleCase):
+		m.searchOptions.CaseSensitive = !m.searchOptions.CaseSensitive
+		m.runSearch()
+		return m, nil
+
+	case key.Matches(msg, m.keys.ToggleRegex):
+		m.searchOptions.Regex = !m.searchOptions.Regex
+		m.runSearch()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	m.runSearch()
+	return m, cmd
+}
+
+// clearSearch drops the query and all match highlighting
+func (m *Model) clearSearch() {
+	m.searchInput.SetValue("")
+	m.searchMatches = nil
+	m.searchIndex = 0
+	m.searchErr = nil
+	m.refreshResponseViewports()
+}
+
+// runSearch re-evaluates the query against the active response viewport or
+// the raw bytes in the hex view
+func (m *Model) runSearch() {
+	if m.hexActive() {
+		m.runHexSearch()
+		m.refreshResponseViewports()
+		return
+	}
+
+	lines := search.Lines(m.searchContent())
+	m.searchMatches, m.searchErr = search.Find(lines, m.searchInput.Value(), m.searchOptions)
+	m.searchIndex = 0
+
+	// Prefer the first match at or below what the user is looking at
+	vp := m.activeResponseViewport()
+	for i, match := range m.searchMatches {
+		if match.Line >= vp.YOffset {
+			m.searchIndex = i
+			break
+		}
+	}
+
+	m.refreshResponseViewports()
+	m.scrollToMatch()
+}
+
+func (m *Model) nextMatch() {
+	if len(m.searchMatches) == 0 {
+		return
+	}
+	m.searchIndex = (m.searchIndex + 1) % len(m.searchMatches)
+	m.refreshResponseViewports()
+	m.scrollToMatch()
+}
+
+func (m *Model) prevMatch() {
+	if len(m.searchMatches) == 0 {
+		return
+	}
+	m.searchIndex = (m.searchIndex - 1 + len(m.searchMatches)) % len(m.searchMatches)
+	m.refreshResponseViewports()
+	m.scrollToMatch()
+}
+
+// scrollToMatch centres the current match if it is outside the viewport
+func (m *Model) scrollToMatch() {
+	if len(m.searchMatches) == 0 {
+		return
+	}
+
+	line := m.searchMatches[m.searchIndex].Line
+	if m.hexActive() {
+		if line < m.hexRowOffset || line >= m.hexRowOffset+m.hexHeight() {
+			m.scrollHex(line - m.hexHeight()/2)
+		}
+		return
+	}
+
+	vp := m.activeResponseViewport()
+	if line < vp.YOffset || line >= vp.YOffset+vp.Height {
+		vp.SetYOffset(line - vp.Height/2)
+	}
+}
+
+// activeResponseViewport returns the viewport of the current response sub-tab
+func (m *Model) activeResponseViewport() *viewport.Model {
+	if m.responseSubTab != ResponseBodySubTab {
+		return &m.headersViewport
+	}
+	return &m.responseViewport
+}
+
+// searchContent returns the styled text shown in the active response viewport
+func (m Model) searchContent() string {
+	switch m.responseSubTab {
+	case ResponseHeadersSubTab:
+		return m.responseHeadersContent
+	case ResponseCookiesSubTab:
+		return m.responseCookiesContent
+	case ResponseConnectionSubTab:
+		return m.responseConnectionContent
+	case ResponseTimelineSubTab:
+		return m.responseTimelineContent
+	}
+	return m.response
+}
+
+// refreshResponseViewports resets the viewport contents, marking matches in
+// whichever one is currently being searched
+func (m *Model) refreshResponseViewports() {
+	m.responseViewport.SetContent(m.response)
+	// The sub-tabs besides Body share the headers viewport
+	if m.responseSubTab == ResponseBodySubTab {
+		m.headersViewport.SetContent(m.responseHeadersContent)
+	} else {
+		m.headersViewport.SetContent(m.searchContent())
+	}
+
+	// Hex matches are byte offsets and are marked when the dump is rendered
+	if len(m.searchMatches) > 0 && !m.hexActive() {
+		m.activeResponseViewport().SetContent(m.markMatches(m.searchContent()))
+	}
+}
+
+// markMatches highlights every match in content, emphasising the current one
+func (m Model) markMatches(content string) string {
+	lines := strings.Split(content, "\n")
+
+	spans := make(map[int][]search.Span)
+	for i, match := range m.searchMatches {
+		span := search.Span{Start: match.Start, End: match.End, On: searchMatchOn, Off: searchMatchOff}
+		if i == m.searchIndex {
+			span.On, span.Off = searchCurrentOn, searchCurrentOff
+		}
+		spans[match.Line] = append(spans[match.Line], span)
+	}
+
+	for line, lineSpans := range spans {
+		if line < len(lines) {
+			lines[line] = search.Mark(lines[line], lineSpans)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderSearchBar renders the search prompt, match counter and option toggles
+func (m Model) renderSearchBar() string {
+	if !m.searching && m.searchInput.Value() == "" {
+		return ""
+	}
+
+	var counter string
+	switch {
+	case m.searchErr != nil:
+		counter = styles.ErrorStyle.Render("invalid pattern")
+	case m.searchInput.Value() == "":
+		counter = ""
+	case len(m.searchMatches) == 0:
+		counter = styles.ErrorStyle.Render("no matches")
+	default:
+		counter = styles.InfoStyle.Render(fmt.Sprintf("%d/%d", m.searchIndex+1, len(m.searchMatches)))
+	}
+
+	toggle := func(label string, on bool) string {
+		if on {
+			return styles.InfoStyle.Copy().Bold(true).Render("[" + label + "]")
+		}
+		return styles.HelpStyle.Render("[" + label + "]")
+	}
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		m.searchInput.View(),
+		"  ",
+		counter,
+		"  ",
+		toggle("Aa", m.searchOptions.CaseSensitive),
+		" ",
+		toggle(".*", m.searchOptions.Regex),
+		"  ",
+		styles.HelpStyle.Render("n/N: next/prev • Alt+C: case • Alt+R: regex • Esc: clear"),
+	)
+}
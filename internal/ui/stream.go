@@ -0,0 +1,224 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// maxStreamEvents caps how many events are kept; older ones are dropped
+const maxStreamEvents = 1000
+
+var (
+	streamLiveStyle  lipgloss.Style
+	streamTypeStyle  lipgloss.Style
+	streamMetaStyle  lipgloss.Style
+	streamPausedText lipgloss.Style
+)
+
+func init() {
+	styles.OnApply(func() {
+		streamLiveStyle = lipgloss.NewStyle().Foreground(styles.Success).Bold(true)
+		streamTypeStyle = lipgloss.NewStyle().Foreground(styles.Accent).Bold(true)
+		streamMetaStyle = lipgloss.NewStyle().Foreground(styles.Muted)
+		streamPausedText = lipgloss.NewStyle().Foreground(styles.Warning).Bold(true)
+	})
+}
+
+// waitForEvent delivers the next event of stream, or StreamEndMessage once
+// it closes
+func waitForEvent(stream *http.EventStream) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-stream.Events
+		if !ok {
+			return StreamEndMessage{Stream: stream, Err: stream.Err()}
+		}
+		return EventMessage{Stream: stream, Event: event}
+	}
+}
+
+// openStream switches the response tab to the live event view for stream
+func (m *Model) openStream(stream *http.EventStream) tea.Cmd {
+	m.streaming = true
+	m.stream = stream
+	m.streamErr = nil
+	m.streamStopped = false
+	m.refreshStream()
+	return waitForEvent(stream)
+}
+
+// closeStream stops the open stream, if any
+func (m *Model) closeStream() {
+	if m.stream != nil {
+		m.stream.Close()
+		m.stream = nil
+	}
+}
+
+// resetStream forgets the events of a previous stream
+func (m *Model) resetStream() {
+	m.closeStream()
+	m.streaming = false
+	m.streamEvents = nil
+	m.streamRendered = nil
+	m.streamLastID = ""
+	m.streamPaused = false
+	m.streamPending = 0
+}
+
+// appendEvent records an event, re-rendering the view unless paused
+func (m *Model) appendEvent(event http.Event) {
+	m.streamEvents = append(m.streamEvents, event)
+	m.streamRendered = append(m.streamRendered, m.renderEvent(event))
+	if over := len(m.streamEvents) - maxStreamEvents; over > 0 {
+		m.streamEvents = m.streamEvents[over:]
+		m.streamRendered = m.streamRendered[over:]
+	}
+	if event.ID != "" {
+		m.streamLastID = event.ID
+	}
+
+	if m.streamPaused {
+		m.streamPending++
+		return
+	}
+	m.refreshStream()
+}
+
+// toggleStreamPause freezes or resumes the event view. Events keep being
+// received while paused and appear on resume.
+func (m *Model) toggleStreamPause() {
+	m.streamPaused = !m.streamPaused
+	if !m.streamPaused {
+		m.streamPending = 0
+		m.refreshStream()
+	}
+}
+
+// stopStream closes the connection but keeps the events received so far
+func (m *Model) stopStream() {
+	if m.stream == nil {
+		return
+	}
+	m.closeStream()
+	m.streamStopped = true
+}
+
+// reconnectStream re-sends the request, passing the last seen event ID so
+// the server can resume where the previous connection left off
+func (m Model) reconnectStream() (Model, tea.Cmd) {
+	m.closeStream()
+
+	req := m.buildRequest()
+	if m.streamLastID != "" {
+		headers := make(map[string]string, len(req.Headers)+1)
+		for k, v := range req.Headers {
+			headers[k] = v
+		}
+		headers["Last-Event-ID"] = m.streamLastID
+		req.Headers = headers
+	}
+
+	return m.send(req)
+}
+
+// refreshStream rebuilds the rendered and raw bodies from the kept events,
+// following the newest event if the view was already at the bottom
+func (m *Model) refreshStream() {
+	follow := m.responseViewport.AtBottom()
+
+	if len(m.streamRendered) == 0 {
+		m.response = styles.HelpStyle.Render("Waiting for events...")
+	} else {
+		m.response = strings.Join(m.streamRendered, "\n\n")
+	}
+
+	raw := make([]string, len(m.streamEvents))
+	for i, event := range m.streamEvents {
+		raw[i] = eventText(event)
+	}
+	m.responseBody = strings.Join(raw, "")
+	m.responseSize = int64(len(m.responseBody))
+
+	if m.searchInput.Value() != "" {
+		m.runSearch()
+		return
+	}
+	m.refreshResponseViewports()
+	if follow {
+		m.responseViewport.GotoBottom()
+	}
+}
+
+// eventText formats an event back into the wire format, so saving or
+// searching a stream works on the same text the server sent
+func eventText(event http.Event) string {
+	var b strings.Builder
+	if event.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", event.ID)
+	}
+	if event.Type != "message" {
+		fmt.Fprintf(&b, "event: %s\n", event.Type)
+	}
+	for _, line := range strings.Split(event.Data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// renderEvent renders an event's header line and its data, pretty printing
+// JSON payloads
+func (m Model) renderEvent(event http.Event) string {
+	header := []string{
+		streamMetaStyle.Render(event.Received.Format("15:04:05.000")),
+		streamTypeStyle.Render(event.Type),
+	}
+	if event.ID != "" {
+		header = append(header, streamMetaStyle.Render("id: ")+event.ID)
+	}
+
+	data := event.Data
+	trimmed := strings.TrimSpace(data)
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		data = m.highlighter.Highlight(trimmed, "application/json")
+	}
+
+	return strings.Join(header, "  ") + "\n" + data
+}
+
+// renderStreamStatus shows whether the stream is live and the keys that
+// control it
+func (m Model) renderStreamStatus() string {
+	count := fmt.Sprintf("%d events", len(m.streamEvents))
+	if m.streamLastID != "" {
+		count += " • last id " + m.streamLastID
+	}
+
+	var state, keys string
+	switch {
+	case m.stream != nil && m.streamPaused:
+		state = streamPausedText.Render(fmt.Sprintf("❚❚ Paused (%d new)", m.streamPending))
+		keys = "p: Resume • x: Stop"
+	case m.stream != nil:
+		state = streamLiveStyle.Render("● Live")
+		keys = "p: Pause • x: Stop"
+	case m.streamErr != nil:
+		state = styles.ErrorStyle.Render("✕ Disconnected: " + m.streamErr.Error())
+		keys = "r: Reconnect"
+	case m.streamStopped:
+		state = streamMetaStyle.Render("■ Stopped")
+		keys = "r: Reconnect"
+	default:
+		state = streamMetaStyle.Render("■ Closed by server")
+		keys = "r: Reconnect"
+	}
+
+	return state + "  " + styles.InfoStyle.Render(count) + "  " + styles.HelpStyle.Render(keys)
+}
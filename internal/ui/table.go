@@ -0,0 +1,295 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/styles"
+	"github.com/pixperk/quest/internal/tabular"
+)
+
+const (
+	maxTableColumnWidth = 30
+	tableSeparator      = " │ "
+)
+
+var (
+	tableHeaderStyle lipgloss.Style
+	tableCellStyle   lipgloss.Style
+	tableCursorStyle lipgloss.Style
+)
+
+func init() {
+	styles.OnApply(func() {
+		tableHeaderStyle = lipgloss.NewStyle().Foreground(styles.Info).Bold(true)
+		tableCellStyle = lipgloss.NewStyle().Foreground(styles.Text)
+		tableCursorStyle = styles.SelectedStyle().Bold(true)
+	})
+}
+
+// buildTable detects tabular responses: CSV bodies and JSON arrays of objects
+func (m *Model) buildTable() {
+	m.table = nil
+	m.tableSortColumn = -1
+	m.tableSortDesc = false
+	m.tableHidden = make(map[int]bool)
+	m.tableRow, m.tableCol = 0, 0
+	m.tableRowOffset, m.tableColOffset = 0, 0
+
+	if strings.Contains(m.responseContentType, "csv") {
+		if table, err := tabular.FromCSV(m.responseBody); err == nil {
+			m.table = table
+		}
+	} else if table, ok := tabular.FromJSON(m.jsonTree); ok {
+		m.table = table
+	}
+
+	if m.table == nil || len(m.table.Columns) == 0 {
+		m.table = nil
+		return
+	}
+
+	m.tableRows = m.table.Rows
+	m.tableWidths = make([]int, len(m.table.Columns))
+	for c, column := range m.table.Columns {
+		width := lipgloss.Width(column) + 2 // room for the sort arrow
+		for _, row := range m.table.Rows {
+			width = max(width, lipgloss.Width(row[c]))
+		}
+		m.tableWidths[c] = min(width, maxTableColumnWidth)
+	}
+}
+
+// updateTable handles navigation keys while the table view is shown and
+// reports whether the key was consumed
+func (m *Model) updateTable(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		m.moveTableRow(m.tableRow - 1)
+
+	case key.Matches(msg, m.keys.Down):
+		m.moveTableRow(m.tableRow + 1)
+
+	case msg.String() == "pgup":
+		m.moveTableRow(m.tableRow - m.tableHeight())
+
+	case msg.String() == "pgdown":
+		m.moveTableRow(m.tableRow + m.tableHeight())
+
+	case msg.String() == "home" || msg.String() == "g":
+		m.moveTableRow(0)
+
+	case msg.String() == "end" || msg.String() == "G":
+		m.moveTableRow(len(m.tableRows) - 1)
+
+	case key.Matches(msg, m.keys.Left):
+		m.moveTableCol(m.tableCol - 1)
+
+	case key.Matches(msg, m.keys.Right):
+		m.moveTableCol(m.tableCol + 1)
+
+	case key.Matches(msg, m.keys.SortColumn):
+		// Cycle ascending → descending → unsorted on the current column
+		switch {
+		case m.tableSortColumn != m.tableCol:
+			m.tableSortColumn, m.tableSortDesc = m.tableCol, false
+		case !m.tableSortDesc:
+			m.tableSortDesc = true
+		default:
+			m.tableSortColumn, m.tableSortDesc = -1, false
+		}
+		m.tableRows = m.table.SortedRows(m.tableSortColumn, m.tableSortDesc)
+
+	case key.Matches(msg, m.keys.ToggleColumn):
+		m.tableHidden[m.tableCol] = !m.tableHidden[m.tableCol]
+		m.moveTableCol(m.tableCol)
+
+	case key.Matches(msg, m.keys.ShowAllColumns):
+		m.tableHidden = make(map[int]bool)
+		m.moveTableCol(m.tableCol)
+
+	case key.Matches(msg, m.keys.ExportCSV):
+		m.exportTable()
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+// tableHeight is the number of data rows that fit below the header,
+// separator and help lines
+func (m Model) tableHeight() int {
+	return max(1, m.responseViewport.Height-4)
+}
+
+func (m *Model) moveTableRow(index int) {
+	m.tableRow = max(0, min(index, len(m.tableRows)-1))
+
+	height := m.tableHeight()
+	if m.tableRow < m.tableRowOffset {
+		m.tableRowOffset = m.tableRow
+	} else if m.tableRow >= m.tableRowOffset+height {
+		m.tableRowOffset = m.tableRow - height + 1
+	}
+}
+
+// moveTableCol selects a column and scrolls horizontally until it fits
+func (m *Model) moveTableCol(index int) {
+	m.tableCol = max(0, min(index, len(m.table.Columns)-1))
+
+	if m.tableCol < m.tableColOffset {
+		m.tableColOffset = m.tableCol
+		return
+	}
+	for m.tableColOffset < m.tableCol && m.tableSpan(m.tableColOffset, m.tableCol) > m.tableWidth() {
+		m.tableColOffset++
+	}
+}
+
+// tableWidth is the horizontal space available to columns
+func (m Model) tableWidth() int {
+	return max(10, m.responseViewport.Width-2)
+}
+
+// tableSpan is the rendered width of columns from..to inclusive
+func (m Model) tableSpan(from, to int) int {
+	width := 0
+	for c := from; c <= to; c++ {
+		if c > from {
+			width += lipgloss.Width(tableSeparator)
+		}
+		width += m.columnWidth(c)
+	}
+	return width
+}
+
+func (m Model) columnWidth(c int) int {
+	if m.tableHidden[c] {
+		return 1
+	}
+	return m.tableWidths[c]
+}
+
+// visibleTableColumns returns the indexes of columns that aren't hidden
+func (m Model) visibleTableColumns() []int {
+	var columns []int
+	for c := range m.table.Columns {
+		if !m.tableHidden[c] {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+// exportTable writes the visible columns, in the current sort order, to a
+// CSV file in the working directory
+func (m *Model) exportTable() {
+	path := fmt.Sprintf("quest-table-%s.csv", time.Now().Format("20060102-150405"))
+
+	f, err := os.Create(path)
+	if err != nil {
+		m.notice = styles.ErrorStyle.Render("Export failed: " + err.Error())
+		return
+	}
+	defer f.Close()
+
+	if err := m.table.WriteCSV(f, m.tableRows, m.visibleTableColumns()); err != nil {
+		m.notice = styles.ErrorStyle.Render("Export failed: " + err.Error())
+		return
+	}
+
+	m.notice = styles.StatusStyle.Render("Exported table to " + path)
+}
+
+// renderTable renders the header, separator and visible window of rows
+func (m Model) renderTable() string {
+	if len(m.table.Columns) == 0 {
+		return styles.HelpStyle.Render("No columns")
+	}
+
+	// Work out which columns fit starting from the horizontal offset
+	last := m.tableColOffset
+	for last+1 < len(m.table.Columns) && m.tableSpan(m.tableColOffset, last+1) <= m.tableWidth() {
+		last++
+	}
+
+	var header []string
+	for c := m.tableColOffset; c <= last; c++ {
+		name := m.table.Columns[c]
+		if m.tableHidden[c] {
+			name = "·"
+		} else if c == m.tableSortColumn {
+			if m.tableSortDesc {
+				name += " ▼"
+			} else {
+				name += " ▲"
+			}
+		}
+		style := tableHeaderStyle
+		if c == m.tableCol {
+			style = style.Copy().Underline(true)
+		}
+		header = append(header, style.Render(fitCell(name, m.columnWidth(c))))
+	}
+
+	lines := []string{
+		"  " + strings.Join(header, tableSeparator),
+		"  " + styles.HelpStyle.Render(strings.Repeat("─", m.tableSpan(m.tableColOffset, last))),
+	}
+
+	end := min(len(m.tableRows), m.tableRowOffset+m.tableHeight())
+	for r := m.tableRowOffset; r < end; r++ {
+		cursor := "  "
+		if r == m.tableRow {
+			cursor = lipgloss.NewStyle().Foreground(styles.Accent).Bold(true).Render("› ")
+		}
+
+		var cells []string
+		for c := m.tableColOffset; c <= last; c++ {
+			value := m.tableRows[r][c]
+			if m.tableHidden[c] {
+				value = "·"
+			}
+			style := tableCellStyle
+			if r == m.tableRow && c == m.tableCol {
+				style = tableCursorStyle
+			}
+			cells = append(cells, style.Render(fitCell(value, m.columnWidth(c))))
+		}
+		lines = append(lines, cursor+strings.Join(cells, tableSeparator))
+	}
+
+	status := fmt.Sprintf("Row %d/%d • Column %d/%d", m.tableRow+1, len(m.tableRows), m.tableCol+1, len(m.table.Columns))
+	if hidden := len(m.table.Columns) - len(m.visibleTableColumns()); hidden > 0 {
+		status += fmt.Sprintf(" • %d hidden", hidden)
+	}
+	lines = append(lines, styles.HelpStyle.Render(status))
+
+	return strings.Join(lines, "\n")
+}
+
+// fitCell flattens a value onto one line and pads or truncates it to width
+func fitCell(value string, width int) string {
+	value = strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ").Replace(value)
+
+	if lipgloss.Width(value) > width {
+		runes := []rune(value)
+		if len(runes) > width {
+			runes = runes[:width]
+		}
+		for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+			runes = runes[:len(runes)-1]
+		}
+		value = string(runes) + "…"
+	}
+
+	return value + strings.Repeat(" ", max(0, width-lipgloss.Width(value)))
+}
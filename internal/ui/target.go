@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// targetOptions are the per-request connection overrides on the options
+// panel
+var targetOptions = []requestOption{
+	{
+		name:  "Unix socket",
+		value: func(m Model) string { return orNone(m.target.UnixSocket) },
+		text:  func(m Model) string { return m.target.UnixSocket },
+		edit: func(m *Model, value string) error {
+			path, err := expandHome(strings.TrimSpace(value))
+			if err != nil {
+				return err
+			}
+			if path != "" {
+				if _, err := os.Stat(path); err != nil {
+					return err
+				}
+			}
+			m.target.UnixSocket = path
+			return nil
+		},
+		path: true,
+	},
+	targetRulesOption("Resolve", func(t *http.Target) *[]string { return &t.Resolve }),
+	targetRulesOption("Connect to", func(t *http.Target) *[]string { return &t.ConnectTo }),
+}
+
+// targetRulesOption is a list of curl-style rules, entered space separated
+// and checked when they're entered
+func targetRulesOption(name string, field func(t *http.Target) *[]string) requestOption {
+	return requestOption{
+		name:  name,
+		value: func(m Model) string { return orNone(strings.Join(*field(&m.target), " ")) },
+		text:  func(m Model) string { return strings.Join(*field(&m.target), " ") },
+		edit: func(m *Model, value string) error {
+			target := m.target
+			*field(&target) = strings.Fields(value)
+			if err := target.Validate(); err != nil {
+				return err
+			}
+			m.target = target
+			return nil
+		},
+	}
+}
+
+// renderRequestPreview shows the request line and where it will connect,
+// marking a connection the target settings redirect
+func (m Model) renderRequestPreview() string {
+	rawURL := m.urlInput.Value()
+	if rawURL == "" {
+		return ""
+	}
+	preview := "→ " + m.getSelectedMethod() + " " + rawURL
+	addr, overridden, err := m.target.ConnectAddr(rawURL)
+	switch {
+	case err != nil:
+		return styles.HelpStyle.Render(preview) + "  " + styles.ErrorStyle.Render(err.Error())
+	case overridden:
+		return styles.HelpStyle.Render(preview+"  connects to ") + styles.InfoStyle.Render(addr)
+	case addr != "":
+		return styles.HelpStyle.Render(preview + "  connects to " + addr)
+	}
+	return styles.HelpStyle.Render(preview)
+}
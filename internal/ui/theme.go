@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/config"
+	"github.com/pixperk/quest/internal/styles"
+	"github.com/pixperk/quest/internal/syntax"
+)
+
+// loadTheme finds the named theme. With NO_COLOR set the theme is still
+// checked, but the monochrome one is used instead.
+func loadTheme(name string) (styles.Theme, error) {
+	theme, err := styles.LoadTheme(name, config.ThemeDir())
+	if err != nil {
+		return styles.Theme{}, err
+	}
+	if styles.NoColor() {
+		return styles.Mono, nil
+	}
+	return theme, nil
+}
+
+// applyTheme redraws everything in theme, including the response on show
+func (m *Model) applyTheme(theme styles.Theme) {
+	styles.Apply(theme)
+	m.highlighter = syntax.NewHighlighter()
+	m.spinner.Style = lipgloss.NewStyle().Foreground(styles.Accent)
+	m.restyleResponse()
+}
+
+// cycleTheme switches to the next built-in or user theme, skipping theme
+// files that don't load
+func (m *Model) cycleTheme() {
+	if styles.NoColor() {
+		m.notice = styles.HelpStyle.Render("NO_COLOR is set, so themes are off")
+		return
+	}
+
+	names := styles.ThemeNames(config.ThemeDir())
+	current := -1
+	for i, name := range names {
+		if name == styles.Current().Name {
+			current = i
+		}
+	}
+
+	var skipped []string
+	for step := 1; step <= len(names); step++ {
+		theme, err := styles.LoadTheme(names[(current+step)%len(names)], config.ThemeDir())
+		if err != nil {
+			skipped = append(skipped, err.Error())
+			continue
+		}
+		m.applyTheme(theme)
+		m.notice = styles.InfoStyle.Render("Theme: " + theme.Name)
+		if len(skipped) > 0 {
+			m.notice += styles.ErrorStyle.Render(" • Skipped " + strings.Join(skipped, "; "))
+		}
+		return
+	}
+}
+
+// restyleResponse re-renders the response on show, which was drawn in the
+// previous theme's colours
+func (m *Model) restyleResponse() {
+	if m.response == "" {
+		return
+	}
+
+	switch {
+	case m.streaming:
+		for i, event := range m.streamEvents {
+			m.streamRendered[i] = m.renderEvent(event)
+		}
+	case m.socketMode:
+		for i, message := range m.socketLog {
+			m.socketRendered[i] = m.renderSocketMessage(message)
+		}
+	case m.grpcMode:
+		first := m.grpcReceived - len(m.grpcMessages)
+		for i, message := range m.grpcMessages {
+			m.grpcRendered[i] = m.renderGRPCMessage(message, first+i+1)
+		}
+	default:
+		m.response = m.renderResponseContent()
+	}
+
+	m.responseHeadersContent = m.formatRedirectChain() + m.formatResponseHeaders()
+	if m.grpcMode && m.grpcStatus != nil {
+		m.responseHeadersContent += "\n\n" + formatTrailers(m.grpcTrailer)
+	}
+	m.responseCookiesContent = m.formatSetCookies(m.responseSetCookies)
+	m.responseConnectionContent = m.formatConnection()
+	m.responseTimelineContent = m.formatTimeline()
+
+	switch {
+	case m.streaming:
+		// A paused stream is redrawn when it resumes
+		if !m.streamPaused {
+			m.refreshStream()
+		}
+	case m.socketMode:
+		m.refreshSocket()
+	case m.grpcMode:
+		m.refreshGRPC()
+	case m.searchInput.Value() != "":
+		m.runSearch()
+	default:
+		m.refreshResponseViewports()
+	}
+}
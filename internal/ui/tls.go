@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// tlsOptions are the per-request TLS settings on the options panel. Each
+// shows the configured setting while the request doesn't set its own.
+var tlsOptions = []requestOption{
+	{
+		name: "Verify TLS",
+		value: func(m Model) string {
+			if m.httpClient.TLS.Insecure {
+				return "off (config)"
+			}
+			return onOff(!m.tlsConfig.Insecure)
+		},
+		change: func(m *Model, _ int) { m.tlsConfig.Insecure = !m.tlsConfig.Insecure },
+	},
+	{
+		name: "Min TLS version",
+		value: func(m Model) string {
+			return inherited(m.tlsConfig.MinVersion, m.httpClient.TLS.MinVersion, "default")
+		},
+		change: func(m *Model, delta int) {
+			// Step through the versions with "" (Go's default) before the first
+			versions := append([]string{""}, http.TLSVersions...)
+			i := 0
+			for j, version := range versions {
+				if version == m.tlsConfig.MinVersion {
+					i = j
+				}
+			}
+			m.tlsConfig.MinVersion = versions[(i+delta+len(versions))%len(versions)]
+		},
+	},
+	tlsFileOption("CA bundle", func(c *http.TLSConfig) *string { return &c.CAFile }),
+	tlsFileOption("Client cert", func(c *http.TLSConfig) *string { return &c.CertFile }),
+	tlsFileOption("Client key", func(c *http.TLSConfig) *string { return &c.KeyFile }),
+	tlsFileOption("PKCS#12 bundle", func(c *http.TLSConfig) *string { return &c.PKCS12File }),
+	{
+		name: "PKCS#12 password",
+		value: func(m Model) string {
+			if m.tlsConfig.PKCS12Password == "" {
+				return "none"
+			}
+			return strings.Repeat("•", 8)
+		},
+		text:   func(m Model) string { return m.tlsConfig.PKCS12Password },
+		edit:   func(m *Model, value string) error { m.tlsConfig.PKCS12Password = value; return nil },
+		secret: true,
+	},
+	{
+		name: "SNI server name",
+		value: func(m Model) string {
+			return inherited(m.tlsConfig.ServerName, m.httpClient.TLS.ServerName, "from URL")
+		},
+		text: func(m Model) string { return m.tlsConfig.ServerName },
+		edit: func(m *Model, value string) error { m.tlsConfig.ServerName = strings.TrimSpace(value); return nil },
+	},
+}
+
+// tlsFileOption is a TLS setting naming a file, checked when it's entered
+func tlsFileOption(name string, field func(c *http.TLSConfig) *string) requestOption {
+	return requestOption{
+		name: name,
+		value: func(m Model) string {
+			return inherited(*field(&m.tlsConfig), *field(&m.httpClient.TLS), "none")
+		},
+		text: func(m Model) string { return *field(&m.tlsConfig) },
+		edit: func(m *Model, value string) error {
+			path, err := expandHome(strings.TrimSpace(value))
+			if err != nil {
+				return err
+			}
+			if path != "" {
+				if _, err := os.Stat(path); err != nil {
+					return err
+				}
+			}
+			*field(&m.tlsConfig) = path
+			return nil
+		},
+		path: true,
+	}
+}
+
+// inherited shows a request's setting, else the one configured for every
+// request, else what happens when neither sets it
+func inherited(request, configured, unset string) string {
+	switch {
+	case request != "":
+		return request
+	case configured != "":
+		return configured + " (config)"
+	}
+	return unset
+}
+
+// expandTLSPaths expands a leading ~ in the files a TLS config names
+func expandTLSPaths(config http.TLSConfig) http.TLSConfig {
+	for _, path := range []*string{&config.CAFile, &config.CertFile, &config.KeyFile, &config.PKCS12File} {
+		if expanded, err := expandHome(*path); err == nil {
+			*path = expanded
+		}
+	}
+	return config
+}
+
+// renderTLSWarning flags, loudly, that server certificates aren't being
+// verified
+func (m Model) renderTLSWarning() string {
+	switch {
+	case m.httpClient.TLS.Insecure:
+		return styles.AlertStyle.Render("⚠ TLS VERIFICATION OFF (" + m.configSource("tls.insecure") + ")")
+	case m.tlsConfig.Insecure:
+		return styles.AlertStyle.Render("⚠ TLS VERIFICATION OFF")
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pixperk/quest/internal/http"
+)
+
+func TestSaveCurrentRequestStripsSecrets(t *testing.T) {
+	tests := []struct {
+		name   string
+		tls    http.TLSConfig
+		proxy  http.ProxyConfig
+		notice []string
+	}{
+		{
+			name: "nothing secret",
+			tls:  http.TLSConfig{CAFile: "ca.pem", Insecure: true},
+		},
+		{
+			name:   "PKCS#12 password",
+			tls:    http.TLSConfig{PKCS12File: "client.p12", PKCS12Password: "s3cret"},
+			notice: []string{"PKCS#12 password"},
+		},
+		{
+			name:   "password and proxy credentials",
+			tls:    http.TLSConfig{PKCS12File: "client.p12", PKCS12Password: "s3cret"},
+			proxy:  http.ProxyConfig{URL: "http://user:pw@proxy.local"},
+			notice: []string{"PKCS#12 password", "proxy credentials"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inTempDir(t)
+			m := newTestModel(t)
+			m.urlInput.SetValue("https://example.com")
+			m.tlsConfig = tt.tls
+			m.proxyConfig = tt.proxy
+
+			m, _ = m.saveCurrentRequest()
+
+			saved := m.loadRequestsFromFile()
+			if len(saved) != 1 || saved[0].TLS == nil {
+				t.Fatalf("saved %+v, want one request with TLS settings", saved)
+			}
+			want := tt.tls
+			want.PKCS12Password = ""
+			if *saved[0].TLS != want {
+				t.Errorf("saved TLS = %+v, want %+v", *saved[0].TLS, want)
+			}
+			if m.tlsConfig != tt.tls {
+				t.Errorf("saving changed the request's TLS settings to %+v", m.tlsConfig)
+			}
+
+			for _, mention := range tt.notice {
+				if !strings.Contains(m.notice, mention) {
+					t.Errorf("notice %q doesn't mention %q", m.notice, mention)
+				}
+			}
+			if len(tt.notice) == 0 && strings.Contains(m.notice, "Saved without") {
+				t.Errorf("notice %q warns about secrets that weren't set", m.notice)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/jsontree"
+	"github.com/pixperk/quest/internal/styles"
+)
+
+// defaultTreeDepth is how deep a freshly parsed response is expanded
+const defaultTreeDepth = 2
+
+var treeValueStyles map[jsontree.Kind]lipgloss.Style
+
+func init() {
+	styles.OnApply(func() {
+		treeValueStyles = map[jsontree.Kind]lipgloss.Style{
+			jsontree.Object: styles.HelpStyle,
+			jsontree.Array:  styles.HelpStyle,
+			jsontree.String: lipgloss.NewStyle().Foreground(styles.Success),
+			jsontree.Number: lipgloss.NewStyle().Foreground(styles.Special),
+			jsontree.Bool:   lipgloss.NewStyle().Foreground(styles.Caution),
+			jsontree.Null:   lipgloss.NewStyle().Foreground(styles.Muted),
+		}
+	})
+}
+
+// buildTree parses the raw response body into a tree, if it is JSON
+func (m *Model) buildTree() {
+	m.jsonTree = nil
+	m.treeCursor = 0
+	m.treeOffset = 0
+
+	root, err := jsontree.Parse([]byte(m.responseBody))
+	if err != nil {
+		return
+	}
+
+	root.ExpandToDepth(defaultTreeDepth)
+	m.jsonTree = root
+}
+
+// updateTree handles navigation keys while the tree view is shown and
+// reports whether the key was consumed
+func (m *Model) updateTree(msg tea.KeyMsg) bool {
+	nodes := m.jsonTree.Visible()
+	node := nodes[m.treeCursor]
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		m.moveTreeCursor(m.treeCursor - 1)
+
+	case key.Matches(msg, m.keys.Down):
+		m.moveTreeCursor(m.treeCursor + 1)
+
+	case msg.String() == "pgup":
+		m.moveTreeCursor(m.treeCursor - m.treeHeight())
+
+	case msg.String() == "pgdown":
+		m.moveTreeCursor(m.treeCursor + m.treeHeight())
+
+	case msg.String() == "home" || msg.String() == "g":
+		m.moveTreeCursor(0)
+
+	case msg.String() == "end" || msg.String() == "G":
+		m.moveTreeCursor(len(nodes) - 1)
+
+	case key.Matches(msg, m.keys.Left):
+		// Collapse the node, or step out to its parent if already collapsed
+		if node.Expanded {
+			node.Expanded = false
+		} else if node.Parent != nil {
+			for i, n := range nodes {
+				if n == node.Parent {
+					m.moveTreeCursor(i)
+					break
+				}
+			}
+		}
+
+	case key.Matches(msg, m.keys.Right):
+		if node.IsContainer() && !node.Expanded {
+			node.Expanded = true
+		} else if node.Expanded && len(node.Children) > 0 {
+			m.moveTreeCursor(m.treeCursor + 1)
+		}
+
+	case key.Matches(msg, m.keys.ToggleNode):
+		if node.IsContainer() {
+			node.Expanded = !node.Expanded
+		}
+
+	case key.Matches(msg, m.keys.ExpandAll):
+		m.jsonTree.SetExpanded(true)
+		m.keepTreeCursorOn(node)
+
+	case key.Matches(msg, m.keys.CollapseAll):
+		m.jsonTree.ExpandToDepth(1)
+		m.moveTreeCursor(0)
+
+	case key.Matches(msg, m.keys.ExpandDepth):
+		if len(msg.Runes) != 1 || msg.Runes[0] < '0' || msg.Runes[0] > '9' {
+			return false
+		}
+		m.jsonTree.ExpandToDepth(int(msg.Runes[0] - '0'))
+		m.keepTreeCursorOn(node)
+
+	case key.Matches(msg, m.keys.CopyValue):
+		m.copyToClipboard("value", node.JSON())
+
+	case key.Matches(msg, m.keys.CopyPath):
+		m.copyToClipboard("path", node.Path())
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+// moveTreeCursor moves the selection, clamping it and scrolling to keep it
+// in view
+func (m *Model) moveTreeCursor(index int) {
+	count := len(m.jsonTree.Visible())
+	m.treeCursor = max(0, min(index, count-1))
+
+	height := m.treeHeight()
+	if m.treeCursor < m.treeOffset {
+		m.treeOffset = m.treeCursor
+	} else if m.treeCursor >= m.treeOffset+height {
+		m.treeOffset = m.treeCursor - height + 1
+	}
+}
+
+// keepTreeCursorOn reselects node after the visible set has changed, falling
+// back to its nearest visible ancestor
+func (m *Model) keepTreeCursorOn(node *jsontree.Node) {
+	nodes := m.jsonTree.Visible()
+	for n := node; n != nil; n = n.Parent {
+		for i, visible := range nodes {
+			if visible == n {
+				m.moveTreeCursor(i)
+				return
+			}
+		}
+	}
+	m.moveTreeCursor(0)
+}
+
+// treeHeight is the number of rows available to the tree, leaving room for
+// its help line
+func (m Model) treeHeight() int {
+	return max(1, m.responseViewport.Height-1)
+}
+
+func (m *Model) copyToClipboard(what, text string) {
+	if err := clipboard.WriteAll(text); err != nil {
+		m.notice = styles.ErrorStyle.Render("Could not copy " + what + ": " + err.Error())
+		return
+	}
+	m.notice = styles.StatusStyle.Render("Copied " + what + " to clipboard")
+}
+
+// renderTree renders the visible window of the JSON tree
+func (m Model) renderTree() string {
+	nodes := m.jsonTree.Visible()
+	end := min(len(nodes), m.treeOffset+m.treeHeight())
+
+	var lines []string
+	for i := m.treeOffset; i < end; i++ {
+		lines = append(lines, m.renderTreeNode(nodes[i], i == m.treeCursor))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderTreeNode renders a single row: cursor, indentation, fold marker,
+// label and either a scalar value or a container summary
+func (m Model) renderTreeNode(node *jsontree.Node, selected bool) string {
+	cursor := "  "
+	if selected {
+		cursor = lipgloss.NewStyle().Foreground(styles.Accent).Bold(true).Render("› ")
+	}
+
+	marker := "  "
+	if node.IsContainer() {
+		marker = "▸ "
+		if node.Expanded {
+			marker = "▾ "
+		}
+	}
+
+	labelStyle := styles.InfoStyle
+	if node.Parent == nil || node.Parent.Kind == jsontree.Array {
+		labelStyle = styles.HelpStyle
+	}
+	if selected {
+		labelStyle = labelStyle.Copy().Bold(true).Underline(true)
+	}
+
+	prefix := cursor + strings.Repeat("  ", node.Depth) + marker + labelStyle.Render(node.Label()) + ": "
+
+	value := node.Summary()
+	if room := m.responseViewport.Width - lipgloss.Width(prefix); room > 1 && len([]rune(value)) > room {
+		value = string([]rune(value)[:room-1]) + "…"
+	}
+
+	return prefix + treeValueStyles[node.Kind].Render(value)
+}
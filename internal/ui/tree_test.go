@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestUpdateTreeKeepsSelection(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"expand all", "*"},
+		{"expand to depth", "4"},
+		{"expand to shallow depth", "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t)
+			m.activeTab = ResponseTab
+			m.responseSubTab = ResponseBodySubTab
+			m.responseView = TreeView
+			m.responseBody = `{"a": {"b": {"c": {"d": [1, 2, 3]}}}, "e": [4, 5], "z": 6}`
+			m.responseViewport.Height = 40
+			m.buildTree()
+
+			nodes := m.jsonTree.Visible()
+			m.treeCursor = len(nodes) - 1
+			selected := nodes[m.treeCursor]
+
+			updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.key)})
+			m = updated.(Model)
+			if got := m.jsonTree.Visible()[m.treeCursor]; got != selected {
+				t.Errorf("selection moved from %s to %s", selected.Path(), got.Path())
+			}
+		})
+	}
+}
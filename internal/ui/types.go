@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -11,8 +12,16 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 
+	"github.com/pixperk/quest/internal/config"
+	"github.com/pixperk/quest/internal/graphql"
+	"github.com/pixperk/quest/internal/grpc"
 	"github.com/pixperk/quest/internal/http"
+	"github.com/pixperk/quest/internal/jsonrpc"
+	"github.com/pixperk/quest/internal/jsontree"
+	"github.com/pixperk/quest/internal/search"
 	"github.com/pixperk/quest/internal/syntax"
+	"github.com/pixperk/quest/internal/tabular"
+	"github.com/pixperk/quest/internal/ws"
 )
 
 type Tab int
@@ -22,6 +31,8 @@ const (
 	HeadersTab
 	BodyTab
 	ResponseTab
+	CookiesTab
+	ConfigTab
 	LoadRequestTab
 )
 
@@ -30,6 +41,18 @@ type ResponseSubTab int
 const (
 	ResponseBodySubTab ResponseSubTab = iota
 	ResponseHeadersSubTab
+	ResponseCookiesSubTab
+	ResponseConnectionSubTab
+	ResponseTimelineSubTab
+)
+
+type ResponseView int
+
+const (
+	PrettyView ResponseView = iota
+	TreeView
+	TableView
+	HexView
 )
 
 type SavedRequest struct {
@@ -38,6 +61,22 @@ type SavedRequest struct {
 	URL     string            `json:"url"`
 	Headers map[string]string `json:"headers"`
 	Body    string            `json:"body"`
+	// RPC is the gRPC method, for GRPC requests
+	RPC string `json:"rpc,omitempty"`
+	// Redirects is the redirect policy, if not the default
+	Redirects *http.RedirectPolicy `json:"redirects,omitempty"`
+	// TLS and Proxy hold the request's own settings, if any
+	TLS   *http.TLSConfig   `json:"tls,omitempty"`
+	Proxy *http.ProxyConfig `json:"proxy,omitempty"`
+	// Target overrides where the request connects, if set
+	Target *http.Target `json:"target,omitempty"`
+	// Retry is the retry policy, if the request is retried
+	Retry *http.RetryPolicy `json:"retry,omitempty"`
+	// ContentType is the Content-Type chosen for Body, if not inferred
+	ContentType string `json:"content_type,omitempty"`
+	// BodyMode and Form hold form bodies, which are sent instead of Body
+	BodyMode string           `json:"body_mode,omitempty"`
+	Form     []http.FormField `json:"form,omitempty"`
 }
 
 func (r SavedRequest) FilterValue() string {
@@ -58,29 +97,160 @@ type Model struct {
 
 	urlInput         textinput.Model
 	methodList       list.Model
+	methodInput      textinput.Model
 	requestList      list.Model
 	headerKey        textinput.Model
 	headerValue      textinput.Model
 	bodyTextarea     textarea.Model
 	responseViewport viewport.Model
 	headersViewport  viewport.Model
+	searchInput      textinput.Model
+	saveInput        textinput.Model
 	help             help.Model
 	spinner          spinner.Model
 	highlighter      *syntax.Highlighter
 
-	activeTab           Tab
-	responseSubTab      ResponseSubTab
-	focused             int
-	loading             bool
-	response            string
-	responseContentType string
-	statusCode          int
-	responseTime        time.Duration
-	responseHeaders     map[string]string
-	requestHeaders      map[string]string
-	httpClient          *http.Client
-	showingLoadDialog   bool
-	savedRequests       []SavedRequest
+	activeTab                 Tab
+	responseSubTab            ResponseSubTab
+	responseView              ResponseView
+	focused                   int
+	loading                   bool
+	response                  string
+	responseErr               error
+	responseMethod            string
+	responseBody              string
+	responseContentType       string
+	statusCode                int
+	responseTime              time.Duration
+	responseHeaders           map[string]string
+	responseHeadersContent    string
+	responseSize              int64
+	responseFile              string
+	responseProto             string
+	responseURL               string
+	responseBinary            bool
+	responseRedirects         []http.Hop
+	redirectStopped           bool
+	responseSetCookies        []http.SetCookie
+	responseCookiesContent    string
+	responseConnection        *http.Connection
+	responseConnectionContent string
+	responseAttempts          []http.Attempt
+	responseTimelineContent   string
+	progress                  http.Progress
+	progressCh                chan http.Progress
+	cancelRequest             context.CancelCauseFunc
+	requestHeaders            map[string]string
+	httpClient                *http.Client
+	showingLoadDialog         bool
+	savedRequests             []SavedRequest
+
+	searching     bool
+	searchOptions search.Options
+	searchMatches []search.Match
+	searchIndex   int
+	searchErr     error
+
+	jsonTree   *jsontree.Node
+	treeCursor int
+	treeOffset int
+
+	table           *tabular.Table
+	tableRows       [][]string
+	tableWidths     []int
+	tableHidden     map[int]bool
+	tableSortColumn int
+	tableSortDesc   bool
+	tableRow        int
+	tableCol        int
+	tableRowOffset  int
+	tableColOffset  int
+
+	streaming      bool
+	stream         *http.EventStream
+	streamEvents   []http.Event
+	streamRendered []string
+	streamLastID   string
+	streamPaused   bool
+	streamPending  int
+	streamStopped  bool
+	streamErr      error
+
+	socketMode     bool
+	socket         *ws.Conn
+	socketURL      string
+	socketLog      []ws.Message
+	socketRendered []string
+	socketErr      error
+	frameFormat    FrameFormat
+
+	grpcCatalog       *grpc.Catalog
+	grpcCatalogTarget string
+	grpcRPC           string
+	grpcLoading       bool
+	protoInput        textinput.Model
+	loadingProtos     bool
+	grpcMode          bool
+	grpcCall          *grpc.Call
+	grpcStarted       time.Time
+	grpcUnary         bool
+	grpcMessages      []grpc.Message
+	grpcReceived      int
+	grpcRendered      []string
+	grpcStatus        *grpc.Status
+	grpcTrailer       map[string]string
+
+	bodyContentType string
+
+	redirectPolicy http.RedirectPolicy
+	tlsConfig      http.TLSConfig
+	proxyConfig    http.ProxyConfig
+	target         http.Target
+	retryPolicy    http.RetryPolicy
+	optionIndex    int
+	optionInput    textinput.Model
+	editingOption  bool
+
+	config         config.Config
+	configOffset   int
+	environment    string
+	cookieJar      *http.CookieJar
+	cookiesEnabled bool
+	cookieIndex    int
+	cookieInput    textinput.Model
+	editingCookie  bool
+
+	bodyMode   http.BodyMode
+	formName   textinput.Model
+	formValue  textinput.Model
+	formType   textinput.Model
+	formFile   bool
+	formPaths  []string
+	formFields []http.FormField
+
+	rpcMethod textinput.Model
+	rpcParams textarea.Model
+	rpcBatch  []jsonrpc.Call
+	rpcNextID int
+	rpcSent   map[string]string
+
+	graphQuery         textarea.Model
+	graphVariables     textarea.Model
+	graphSchema        *graphql.Schema
+	graphSchemaLoading bool
+	graphErrors        []*graphql.Error
+	graphSuggestions   []graphql.Suggestion
+	graphPrefix        string
+	graphSuggestIndex  int
+
+	hexRowOffset int
+	hexFormat    string
+
+	savingResponse bool
+	saveHeaders    bool
+	saveConfirm    string
+
+	notice string
 
 	keys KeyMap
 }
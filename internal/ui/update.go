@@ -6,6 +6,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/pixperk/quest/internal/hexview"
+	"github.com/pixperk/quest/internal/http"
 	"github.com/pixperk/quest/internal/styles"
 )
 
@@ -21,9 +23,53 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		// A notice lasts until the next key press, which may set another
+		m.notice = ""
+
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+
+		if m.savingResponse {
+			return m.updateSaveResponse(msg)
+		}
+
+		if m.loadingProtos {
+			return m.updateLoadProtos(msg)
+		}
+
+		if m.editingCookie {
+			return m.updateCookieEdit(msg)
+		}
+
+		if m.editingOption {
+			return m.updateOptionEdit(msg)
+		}
+
+		if m.activeTab == BodyTab && m.graphSuggestions != nil && m.updateCompletion(msg) {
+			return m, nil
+		}
+
+		if m.showingTree() && m.updateTree(msg) {
+			return m, nil
+		}
+
+		if m.showingTable() && m.updateTable(msg) {
+			return m, nil
+		}
+
+		if m.showingHex() && m.updateHex(msg) {
+			return m, nil
+		}
+
 		switch {
 		case key.Matches(msg, m.keys.Quit):
-			return m, tea.Quit
+			m.discardResponseFile()
+			m.closeStream()
+			// Quit waits for the close frame to go out
+			closing := m.closeSocket()
+			m.cancelGRPC()
+			return m, tea.Sequence(closing, tea.Quit)
 
 		case key.Matches(msg, m.keys.Send):
 			if !m.loading && m.urlInput.Value() != "" {
@@ -35,17 +81,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.activeTab = URLTab
 				m.showingLoadDialog = false
 			} else {
-				m.activeTab = Tab((int(m.activeTab) + 1) % 4)
+				m.activeTab = Tab((int(m.activeTab) + 1) % 6)
 			}
 			m.focused = 0
 			m.updateFocus()
 
 		case key.Matches(msg, m.keys.PrevTab):
 			if m.activeTab == LoadRequestTab {
-				m.activeTab = ResponseTab
+				m.activeTab = ConfigTab
 				m.showingLoadDialog = false
 			} else {
-				m.activeTab = Tab((int(m.activeTab) + 3) % 4)
+				m.activeTab = Tab((int(m.activeTab) + 5) % 6)
 			}
 			m.focused = 0
 			m.updateFocus()
@@ -53,7 +99,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.NextFocus):
 			switch m.activeTab {
 			case URLTab:
-				m.focused = (m.focused + 1) % 2
+				m.focused = (m.focused + 1) % 4
 			case HeadersTab:
 				m.focused = (m.focused + 1) % 2
 			}
@@ -62,7 +108,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.PrevFocus):
 			switch m.activeTab {
 			case URLTab:
-				m.focused = (m.focused + 1) % 2
+				m.focused = (m.focused + 3) % 4
 			case HeadersTab:
 				m.focused = (m.focused + 1) % 2
 			}
@@ -71,9 +117,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Tab):
 			switch m.activeTab {
 			case URLTab:
-				m.focused = (m.focused + 1) % 2
+				m.focused = (m.focused + 1) % 4
 			case HeadersTab:
 				m.focused = (m.focused + 1) % 2
+			case BodyTab:
+				switch {
+				case m.graphQLMode() || m.grpcModeSelected() || m.jsonRPCMode():
+					m.focused = (m.focused + 1) % 2
+				case m.formMode() && !m.formPathCompletable():
+					// Tab would otherwise reach the newly focused input
+					m.focused = (m.focused + 1) % m.formInputCount()
+					m.updateFocus()
+					return m, nil
+				}
 			}
 			m.updateFocus()
 
@@ -104,6 +160,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.methodList.Select(newIndex)
 			}
 
+		case key.Matches(msg, m.keys.AddCall) && m.activeTab == BodyTab && m.jsonRPCMode():
+			m.addRPCCall()
+
+		case key.Matches(msg, m.keys.ClearBatch) && m.activeTab == BodyTab && m.jsonRPCMode():
+			m.rpcBatch = nil
+
+		case key.Matches(msg, m.keys.BodyMode) && m.activeTab == BodyTab && m.bodyModesAvailable():
+			m.cycleBodyMode()
+			return m, nil
+
+		case key.Matches(msg, m.keys.ContentType) && m.activeTab == BodyTab && m.bodyModesAvailable() && !m.formMode():
+			m.cycleContentType()
+			return m, nil
+
+		case key.Matches(msg, m.keys.AddField) && m.activeTab == BodyTab && m.formMode():
+			m.addFormField()
+			return m, nil
+
+		case key.Matches(msg, m.keys.FileField) && m.activeTab == BodyTab && m.formMode():
+			m.toggleFormFile()
+			return m, nil
+
+		case key.Matches(msg, m.keys.ClearFields) && m.activeTab == BodyTab && m.formMode():
+			m.formFields = nil
+
 		case key.Matches(msg, m.keys.AddHeader):
 			if m.activeTab == HeadersTab && m.headerKey.Value() != "" && m.headerValue.Value() != "" {
 				m.requestHeaders[m.headerKey.Value()] = m.headerValue.Value()
@@ -121,24 +202,94 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, m.keys.NextResponseTab):
 			if m.activeTab == ResponseTab {
-				m.responseSubTab = ResponseSubTab((int(m.responseSubTab) + 1) % 2)
+				m.responseSubTab = ResponseSubTab((int(m.responseSubTab) + 1) % len(responseSubTabNames))
+				m.runSearch()
 			}
 
 		case key.Matches(msg, m.keys.PrevResponseTab):
 			if m.activeTab == ResponseTab {
-				m.responseSubTab = ResponseSubTab((int(m.responseSubTab) + 1) % 2)
+				count := len(responseSubTabNames)
+				m.responseSubTab = ResponseSubTab((int(m.responseSubTab) + count - 1) % count)
+				m.runSearch()
 			}
 
+		case key.Matches(msg, m.keys.Search) && m.activeTab == ResponseTab && m.searchable():
+			return m.startSearch()
+
+		case key.Matches(msg, m.keys.FetchSchema) && m.graphQLMode() && !m.graphSchemaLoading && m.urlInput.Value() != "":
+			return m.fetchSchema()
+
+		case key.Matches(msg, m.keys.FetchSchema) && m.grpcModeSelected() && !m.grpcLoading && m.urlInput.Value() != "":
+			return m.reflectServices()
+
+		case key.Matches(msg, m.keys.LoadProtos) && m.grpcModeSelected() && !m.grpcLoading:
+			m.activeTab = BodyTab
+			return m.startLoadProtos()
+
+		case key.Matches(msg, m.keys.StopStream) && m.activeTab == ResponseTab && m.grpcCall != nil:
+			m.grpcCall.Cancel()
+
+		case m.graphQLKeyActive(msg, m.keys.Complete) && m.focused == 0:
+			m.openCompletion()
+			return m, nil
+
+		case key.Matches(msg, m.keys.FrameFormat) && m.getSelectedMethod() == WebSocketMethod:
+			m.frameFormat = FrameFormat((int(m.frameFormat) + 1) % len(frameFormatNames))
+
+		case key.Matches(msg, m.keys.Ping) && m.socket != nil:
+			return m, m.pingSocket()
+
+		case key.Matches(msg, m.keys.Disconnect) && m.activeTab == ResponseTab && m.socket != nil:
+			return m, m.disconnectSocket()
+
+		case key.Matches(msg, m.keys.PauseStream) && m.activeTab == ResponseTab && m.stream != nil:
+			m.toggleStreamPause()
+
+		case key.Matches(msg, m.keys.StopStream) && m.activeTab == ResponseTab && m.loading && m.cancelRequest != nil:
+			m.cancelRequest(http.ErrCancelled)
+
+		case key.Matches(msg, m.keys.StopStream) && m.activeTab == ResponseTab && m.stream != nil:
+			m.stopStream()
+
+		case key.Matches(msg, m.keys.Reconnect) && m.activeTab == ResponseTab && m.streaming && m.stream == nil && !m.loading:
+			return m.reconnectStream()
+
+		case key.Matches(msg, m.keys.SaveResponse) && m.activeTab == ResponseTab && (m.statusCode != 0 || m.grpcMode):
+			return m.startSaveResponse()
+
+		case key.Matches(msg, m.keys.CycleView) && m.activeTab == ResponseTab:
+			m.cycleResponseView()
+
+		case key.Matches(msg, m.keys.NextMatch) && m.activeTab == ResponseTab:
+			m.nextMatch()
+
+		case key.Matches(msg, m.keys.PrevMatch) && m.activeTab == ResponseTab:
+			m.prevMatch()
+
+		case key.Matches(msg, m.keys.ToggleCase) && m.activeTab == ResponseTab:
+			m.searchOptions.CaseSensitive = !m.searchOptions.CaseSensitive
+			m.runSearch()
+
+		case key.Matches(msg, m.keys.ToggleRegex) && m.activeTab == ResponseTab:
+			m.searchOptions.Regex = !m.searchOptions.Regex
+			m.runSearch()
+
 		case key.Matches(msg, m.keys.SaveRequest):
 			return m.saveCurrentRequest()
 
 		case key.Matches(msg, m.keys.LoadRequest):
 			return m.showLoadRequestDialog()
 
+		case key.Matches(msg, m.keys.CycleTheme):
+			m.cycleTheme()
+
 		case key.Matches(msg, m.keys.Help):
 			m.help.ShowAll = !m.help.ShowAll
 
 		case msg.String() == "esc":
+			if m.activeTab == ResponseTab {
+				m.clearSearch()
+			}
 			if m.activeTab == LoadRequestTab {
 				m.activeTab = URLTab
 				m.showingLoadDialog = false
@@ -149,21 +300,158 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ResponseMessage:
 		m.loading = false
+		m.cancelRequest = nil
 		m.statusCode = msg.StatusCode
 		m.responseTime = msg.ResponseTime
 		m.responseHeaders = msg.Headers
 		m.responseContentType = msg.ContentType
+		m.responseBody = msg.Body
+		m.responseSize = msg.BodySize
+		m.responseBinary = msg.Binary
+		m.discardResponseFile()
+		m.responseFile = msg.BodyFile
+		m.responseProto = msg.Proto
+		m.responseURL = msg.URL
+		m.responseRedirects = msg.Redirects
+		m.redirectStopped = msg.RedirectStopped
+		m.responseSetCookies = msg.SetCookies
+		m.responseCookiesContent = m.formatSetCookies(msg.SetCookies)
+		m.responseConnection = msg.Connection
+		m.responseConnectionContent = m.formatConnection()
+		m.responseAttempts = msg.Attempts
+		m.responseTimelineContent = m.formatTimeline()
+		if len(msg.SetCookies) > 0 && m.cookiesEnabled {
+			m.saveCookies()
+		}
+		m.hexFormat = hexview.Detect([]byte(msg.Body))
+		m.hexRowOffset = 0
+
+		if msg.Stream != nil {
+			m.responseHeadersContent = m.formatRedirectChain() + m.formatResponseHeaders()
+			m.jsonTree, m.table = nil, nil
+			m.responseView = PrettyView
+			m.activeTab = ResponseTab
+			return m, m.openStream(msg.Stream)
+		}
+		m.streaming = false
+		m.responseErr = msg.Error
+		m.response = m.renderResponseContent()
 
-		if msg.Error != nil {
-			m.response = styles.ErrorStyle.Render("Error: " + msg.Error.Error())
-		} else {
-			m.response = m.highlighter.Highlight(msg.Body, msg.ContentType)
+		m.responseHeadersContent = m.formatRedirectChain() + m.formatResponseHeaders()
+		m.buildTree()
+		m.buildTable()
+		switch {
+		case msg.Binary && msg.Body != "":
+			m.responseView = HexView
+		case !m.hasResponseView(m.responseView):
+			m.responseView = PrettyView
 		}
+		m.runSearch()
+		m.activeTab = ResponseTab
+		return m, nil
+
+	case EventMessage:
+		if msg.Stream != m.stream {
+			return m, nil
+		}
+		m.appendEvent(msg.Event)
+		return m, waitForEvent(msg.Stream)
 
-		m.responseViewport.SetContent(m.response)
+	case StreamEndMessage:
+		if msg.Stream != m.stream {
+			return m, nil
+		}
+		m.stream = nil
+		m.streamErr = msg.Err
+		m.streamPaused = false
+		m.refreshStream()
+		return m, nil
+
+	case SocketOpenedMessage:
+		m.loading = false
+		m.statusCode = msg.Handshake.StatusCode
+		m.responseTime = msg.Elapsed
+		m.responseHeaders = msg.Handshake.Headers
+		m.responseContentType = ""
+		m.responseHeadersContent = m.formatResponseHeaders()
+		m.responseSetCookies = nil
+		m.responseCookiesContent = m.formatSetCookies(nil)
+		m.responseConnection = nil
+		m.responseConnectionContent = m.formatConnection()
+		m.responseAttempts = nil
+		m.responseTimelineContent = m.formatTimeline()
+		m.responseRedirects, m.redirectStopped = nil, false
 		m.activeTab = ResponseTab
+		m.responseErr = msg.Err
+		if msg.Err != nil {
+			m.socketMode = false
+			m.responseBody = ""
+			m.response = m.renderResponseContent()
+			m.refreshResponseViewports()
+			return m, nil
+		}
+		return m, m.openSocket(msg)
+
+	case SocketMessage:
+		if msg.Conn != m.socket {
+			return m, nil
+		}
+		m.appendSocketMessage(msg.Message)
+		return m, waitForSocket(msg.Conn)
+
+	case SocketSentMessage:
+		if msg.Conn != m.socket {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.notice = styles.ErrorStyle.Render("Send failed: " + msg.Err.Error())
+			return m, nil
+		}
+		m.appendSocketMessage(msg.Message)
+		return m, nil
+
+	case SocketClosedMessage:
+		if msg.Conn != m.socket {
+			return m, nil
+		}
+		m.socket = nil
+		m.socketErr = msg.Err
+		m.refreshSocket()
 		return m, nil
 
+	case GRPCCatalogMessage:
+		m.handleGRPCCatalog(msg)
+		return m, nil
+
+	case GRPCStartedMessage:
+		return m, m.handleGRPCStarted(msg)
+
+	case GRPCMessage:
+		if msg.Call != m.grpcCall {
+			return m, nil
+		}
+		m.appendGRPCMessage(msg.Message)
+		return m, waitForGRPC(msg.Call)
+
+	case GRPCEndMessage:
+		if msg.Call != m.grpcCall {
+			return m, nil
+		}
+		m.finishGRPC(msg.Call)
+		return m, nil
+
+	case SchemaMessage:
+		m.handleSchema(msg)
+		return m, nil
+
+	case ResponseSavedMessage:
+		m.handleResponseSaved(msg)
+		return m, nil
+
+	case ProgressMessage:
+		m.progress = msg.Progress
+		return m, waitForProgress(m.progressCh)
+
 	case spinner.TickMsg:
 		if m.loading {
 			m.spinner, cmd = m.spinner.Update(msg)
@@ -176,6 +464,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.focused == 0 {
 			m.urlInput, cmd = m.urlInput.Update(msg)
 			cmds = append(cmds, cmd)
+		} else if m.focused == optionsFocus {
+			if keyMsg, ok := msg.(tea.KeyMsg); ok {
+				cmds = append(cmds, m.updateOptions(keyMsg))
+			}
+		} else if m.focused == 2 {
+			if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, m.keys.Enter) {
+				m.addCustomMethod()
+			} else {
+				m.methodInput, cmd = m.methodInput.Update(msg)
+				cmds = append(cmds, cmd)
+			}
 		} else {
 			// Don't pass left/right arrows to the method list when focused
 			if keyMsg, ok := msg.(tea.KeyMsg); ok && (keyMsg.String() == "left" || keyMsg.String() == "right") {
@@ -194,8 +493,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 	case BodyTab:
-		m.bodyTextarea, cmd = m.bodyTextarea.Update(msg)
-		cmds = append(cmds, cmd)
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && m.grpcModeSelected() && m.grpcPickerFocused() {
+			m.updateGRPCMethods(keyMsg)
+		} else if m.graphQLMode() {
+			cmds = append(cmds, m.updateGraphQLEditors(msg))
+		} else if m.jsonRPCMode() && m.focused == 0 {
+			m.rpcMethod, cmd = m.rpcMethod.Update(msg)
+			cmds = append(cmds, cmd)
+		} else if m.jsonRPCMode() {
+			m.rpcParams, cmd = m.rpcParams.Update(msg)
+			cmds = append(cmds, cmd)
+		} else if m.formMode() {
+			cmds = append(cmds, m.updateFormEditors(msg))
+		} else {
+			m.bodyTextarea, cmd = m.bodyTextarea.Update(msg)
+			cmds = append(cmds, cmd)
+		}
 	case ResponseTab:
 		if m.responseSubTab == ResponseBodySubTab {
 			m.responseViewport, cmd = m.responseViewport.Update(msg)
@@ -203,6 +516,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.headersViewport, cmd = m.headersViewport.Update(msg)
 		}
 		cmds = append(cmds, cmd)
+	case CookiesTab:
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			cmds = append(cmds, m.updateCookies(keyMsg))
+		}
+	case ConfigTab:
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			m.updateConfig(keyMsg)
+		}
 	case LoadRequestTab:
 		m.requestList, cmd = m.requestList.Update(msg)
 		cmds = append(cmds, cmd)
@@ -234,6 +555,10 @@ func (m Model) View() string {
 		content = m.renderBodyTab()
 	case ResponseTab:
 		content = m.renderResponseTab()
+	case CookiesTab:
+		content = m.renderCookiesTab()
+	case ConfigTab:
+		content = m.renderConfigTab()
 	case LoadRequestTab:
 		content = m.renderLoadRequestTab()
 	}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -20,7 +21,7 @@ func (m Model) renderHeader() string {
 func (m Model) renderTabs() string {
 	var tabs []string
 
-	tabNames := []string{"URL", "Headers", "Body", "Response"}
+	tabNames := []string{"URL", "Headers", "Body", "Response", "Cookies", "Config"}
 	for i, name := range tabNames {
 		if Tab(i) == m.activeTab {
 			tabs = append(tabs, styles.ActiveTabStyle.Render(name))
@@ -40,31 +41,29 @@ func (m Model) renderURLTab() string {
 	} else {
 		urlSection += styles.BlurredStyle.Render(m.urlInput.View())
 	}
+	if preview := m.renderRequestPreview(); preview != "" {
+		urlSection += "\n" + preview
+	}
 
-	// HTTP methods in horizontal layout
-	methodSection := styles.HeaderStyle.Render("HTTP Method") + "\n"
-
-	var methodButtons []string
-	methods := []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD"}
-	selectedMethod := m.getSelectedMethod()
+	// HTTP methods, one row per group
+	methodSection := styles.HeaderStyle.Render("HTTP Method")
+	methodRows := m.renderMethodRows()
 
-	for _, method := range methods {
-		if method == selectedMethod && m.focused == 1 {
-			methodButtons = append(methodButtons, styles.ActiveTabStyle.Render(" "+method+" "))
-		} else if method == selectedMethod {
-			methodButtons = append(methodButtons, styles.TabStyle.Render(" "+method+" "))
-		} else {
-			methodButtons = append(methodButtons, styles.BlurredStyle.Render(" "+method+" "))
-		}
+	customInput := styles.BlurredStyle.Render(m.methodInput.View())
+	if m.focused == 2 {
+		customInput = styles.FocusedStyle.Render(m.methodInput.View())
 	}
 
-	methodRow := lipgloss.JoinHorizontal(lipgloss.Left, methodButtons...)
-
 	// Focus indicator
 	var focusHelp string
-	if m.focused == 1 {
-		focusHelp = styles.HelpStyle.Render("Use ←/→ to select method")
-	} else {
+	switch m.focused {
+	case 1:
+		focusHelp = styles.HelpStyle.Render("Use ←/→ to select method • Tab: Type a custom method")
+	case 2:
+		focusHelp = styles.HelpStyle.Render("Enter: Use this method (sent exactly as typed) • Tab: Request options")
+	case optionsFocus:
+		focusHelp = styles.HelpStyle.Render("↑/↓: Choose option • ←/→ or Space: Change • Enter: Edit paths, names and rules • Tab: Back to URL")
+	default:
 		focusHelp = styles.HelpStyle.Render("Alt+↓ or Tab to select method • Ctrl+R to load saved requests")
 	}
 
@@ -73,7 +72,10 @@ func (m Model) renderURLTab() string {
 		urlSection,
 		"",
 		methodSection,
-		methodRow,
+		methodRows,
+		customInput,
+		"",
+		m.renderOptions(),
 		"",
 		focusHelp,
 	)
@@ -114,9 +116,32 @@ func (m Model) renderHeadersTab() string {
 
 // renderBodyTab renders the request body tab
 func (m Model) renderBodyTab() string {
+	if m.graphQLMode() {
+		return m.renderGraphQLBody()
+	}
+	if m.grpcModeSelected() {
+		return m.renderGRPCBody()
+	}
+	if m.jsonRPCMode() {
+		return m.renderRPCBody()
+	}
+	if m.formMode() {
+		return m.renderFormBody()
+	}
+
 	bodySection := styles.HeaderStyle.Render("Request Body") + "\n"
-	bodySection += styles.HelpStyle.Render("Enter your request body (JSON, XML, plain text, etc.)") + "\n\n"
+	if m.getSelectedMethod() == WebSocketMethod {
+		bodySection += styles.HelpStyle.Render("Message to send • Format: ") +
+			styles.InfoStyle.Render(frameFormatNames[m.frameFormat]) +
+			styles.HelpStyle.Render(" (Ctrl+F to change) • Ctrl+S: Connect/Send") + "\n\n"
+	} else {
+		bodySection += m.renderBodyModes() + "\n" +
+			styles.HelpStyle.Render("Enter your request body (JSON, XML, plain text, etc.)") + "\n\n"
+	}
 	bodySection += styles.FocusedStyle.Render(m.bodyTextarea.View())
+	if m.getSelectedMethod() != WebSocketMethod {
+		bodySection += "\n" + m.renderContentType()
+	}
 
 	return bodySection
 }
@@ -132,8 +157,18 @@ func (m Model) renderResponseTab() string {
 	responseSection += "\n"
 
 	if m.loading {
+		cancel := ""
+		if m.cancelRequest != nil {
+			cancel = styles.HelpStyle.Render(" • " + m.keys.StopStream.Help().Key + " to cancel")
+		}
+		if m.progress.BytesRead > 0 {
+			return responseSection + m.spinner.View() + " " + m.renderProgress() + cancel
+		}
+		if m.progress.Retry != nil || m.progress.Attempt > 1 {
+			return responseSection + m.spinner.View() + " " + m.renderRetryProgress() + cancel
+		}
 		return responseSection + m.spinner.View() + " " +
-			styles.InfoStyle.Render("Sending request...")
+			styles.InfoStyle.Render("Sending request...") + cancel
 	}
 
 	if m.response == "" {
@@ -141,26 +176,55 @@ func (m Model) renderResponseTab() string {
 	}
 
 	// Response sub-tabs
-	responseTabs := m.renderResponseSubTabs() + "\n\n"
+	responseTabs := m.renderResponseSubTabs() + "\n"
+	if m.streaming {
+		responseTabs += m.renderStreamStatus() + "\n"
+	}
+	if m.socketMode {
+		responseTabs += m.renderSocketStatus() + "\n"
+	}
+	if m.grpcMode {
+		responseTabs += m.renderGRPCStatus() + "\n"
+	}
+	if retries := m.renderRetrySummary(); retries != "" {
+		responseTabs += retries + "\n"
+	}
+	if redirects := m.renderRedirectSummary(); redirects != "" {
+		responseTabs += redirects + "\n"
+	}
+	if warning := m.renderCertificateWarning(); warning != "" {
+		responseTabs += warning + "\n"
+	}
+	if searchBar := m.renderSearchBar(); searchBar != "" {
+		responseTabs += searchBar + "\n"
+	}
+	if savePrompt := m.renderSavePrompt(); savePrompt != "" {
+		responseTabs += savePrompt + "\n"
+	}
+	if notice := m.renderTruncationNotice(); notice != "" {
+		responseTabs += notice + "\n"
+	}
+	responseTabs += "\n"
 
 	// Content based on active response sub-tab
 	var content string
 	switch m.responseSubTab {
 	case ResponseBodySubTab:
 		content = m.renderResponseBody()
-	case ResponseHeadersSubTab:
+	case ResponseHeadersSubTab, ResponseCookiesSubTab, ResponseConnectionSubTab, ResponseTimelineSubTab:
 		content = m.renderResponseHeaders()
 	}
 
 	return responseSection + responseTabs + content
 }
 
+var responseSubTabNames = []string{"Body", "Headers", "Cookies", "Connection", "Timeline"}
+
 // renderResponseSubTabs renders the sub-tabs within the response tab
 func (m Model) renderResponseSubTabs() string {
 	var tabs []string
 
-	subTabNames := []string{"Body", "Headers"}
-	for i, name := range subTabNames {
+	for i, name := range responseSubTabNames {
 		if ResponseSubTab(i) == m.responseSubTab {
 			tabs = append(tabs, styles.ActiveTabStyle.Render(name))
 		} else {
@@ -168,42 +232,82 @@ func (m Model) renderResponseSubTabs() string {
 		}
 	}
 
-	helpText := styles.HelpStyle.Render("Use Shift+←/→ to switch between response tabs")
+	helpText := styles.HelpStyle.Render("Use Shift+←/→ to switch between response tabs • /: Search • Ctrl+O: Save")
 
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		lipgloss.JoinHorizontal(lipgloss.Left, tabs...),
-		helpText,
-	)
+	rows := []string{lipgloss.JoinHorizontal(lipgloss.Left, tabs...), helpText}
+	if m.responseSubTab == ResponseBodySubTab {
+		if views := m.renderResponseViews(); views != "" {
+			rows = append(rows, views)
+		}
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
 // renderResponseBody renders the response body content
 func (m Model) renderResponseBody() string {
+	if m.responseView == TreeView && m.jsonTree != nil {
+		help := styles.HelpStyle.Render("←/→: Fold • Enter: Toggle • 1-9: Expand to depth • *: Expand all • 0: Collapse • c/p: Copy value/path")
+		return help + "\n" + m.renderTree()
+	}
+	if m.responseView == TableView && m.table != nil {
+		help := styles.HelpStyle.Render("←/→: Column • s: Sort • Space: Hide/show column • a: Show all • e: Export CSV")
+		return help + "\n" + m.renderTable()
+	}
+	if m.responseView == HexView && m.responseBody != "" {
+		help := styles.HelpStyle.Render("↑/↓/PgUp/PgDn: Scroll • g/G: Start/end • /: Search bytes (hex pairs like 89 50, or \"text\")")
+		return help + "\n" + m.renderHex()
+	}
 	return m.responseViewport.View()
 }
 
 // renderResponseHeaders renders the response headers content
 func (m Model) renderResponseHeaders() string {
+	return m.headersViewport.View()
+}
+
+// formatResponseHeaders builds the styled, sorted header listing shown in the
+// headers viewport
+func (m Model) formatResponseHeaders() string {
 	if len(m.responseHeaders) == 0 {
-		content := styles.HelpStyle.Render("No response headers available")
-		m.headersViewport.SetContent(content)
-	} else {
-		var headerLines []string
-		for key, value := range m.responseHeaders {
-			headerLine := styles.InfoStyle.Render(key) + ": " + styles.JsonStyle.Render(value)
-			headerLines = append(headerLines, headerLine)
-		}
-		content := strings.Join(headerLines, "\n")
-		m.headersViewport.SetContent(content)
+		return styles.HelpStyle.Render("No response headers available")
 	}
 
-	return m.headersViewport.View()
+	keys := make([]string, 0, len(m.responseHeaders))
+	for key := range m.responseHeaders {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var headerLines []string
+	for _, key := range keys {
+		headerLine := styles.InfoStyle.Render(key) + ": " + styles.JsonStyle.Render(m.responseHeaders[key])
+		headerLines = append(headerLines, headerLine)
+	}
+
+	return strings.Join(headerLines, "\n")
+}
+
+// renderIndicators lists the connection settings worth keeping in view:
+// skipped TLS verification and the proxy in use
+func (m Model) renderIndicators() string {
+	var indicators []string
+	for _, indicator := range []string{m.renderTLSWarning(), m.renderProxyStatus()} {
+		if indicator != "" {
+			indicators = append(indicators, indicator)
+		}
+	}
+	return strings.Join(indicators, "  ")
 }
 
 // renderStatusBar renders the status bar with response information
 func (m Model) renderStatusBar() string {
-	if m.statusCode == 0 {
-		return ""
+	indicators := m.renderIndicators()
+	if m.statusCode == 0 && !m.grpcMode {
+		if indicators != "" {
+			return lipgloss.JoinVertical(lipgloss.Left, indicators, m.notice)
+		}
+		return m.notice
 	}
 
 	// Status code with color
@@ -212,6 +316,9 @@ func (m Model) renderStatusBar() string {
 		Foreground(statusColor).
 		Bold(true).
 		Render(fmt.Sprintf("Status: %d", m.statusCode))
+	if m.grpcMode {
+		statusText = m.renderGRPCStatusCode()
+	}
 
 	// Response time
 	responseTimeText := styles.InfoStyle.Render(fmt.Sprintf("Time: %v", m.responseTime))
@@ -226,7 +333,11 @@ func (m Model) renderStatusBar() string {
 	}
 	urlText := styles.HelpStyle.Render(fmt.Sprintf("URL: %s", url))
 
-	return lipgloss.JoinHorizontal(
+	if indicators != "" {
+		statusText = indicators + "  " + statusText
+	}
+
+	bar := lipgloss.JoinHorizontal(
 		lipgloss.Left,
 		statusText,
 		"  ",
@@ -238,6 +349,11 @@ func (m Model) renderStatusBar() string {
 		"  ",
 		styles.HelpStyle.Render("Ctrl+W: Save • Ctrl+R: Load"),
 	)
+
+	if m.notice != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, bar, m.notice)
+	}
+	return bar
 }
 
 // renderLoadRequestTab renders the saved requests loading dialog
@@ -248,7 +364,7 @@ func (m Model) renderLoadRequestTab() string {
 	// Create a nice border around the list
 	listStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(styles.Blue).
+		BorderForeground(styles.Info).
 		Padding(1).
 		Width(m.width - 10).
 		Height(m.height - 15)
@@ -258,7 +374,7 @@ func (m Model) renderLoadRequestTab() string {
 	if len(m.savedRequests) == 0 {
 		emptyStyle := lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(styles.Yellow).
+			BorderForeground(styles.Warning).
 			Padding(2).
 			Width(m.width - 10).
 			Align(lipgloss.Center)
@@ -0,0 +1,304 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pixperk/quest/internal/hexview"
+	"github.com/pixperk/quest/internal/styles"
+	"github.com/pixperk/quest/internal/ws"
+)
+
+// WebSocketMethod is the pseudo-method that selects WebSocket mode
+const WebSocketMethod = "WS"
+
+// maxSocketMessages caps the message log; older entries are dropped
+const maxSocketMessages = 1000
+
+// maxSocketBinaryPreview limits how much of a binary frame is dumped
+const maxSocketBinaryPreview = 256
+
+// FrameFormat is how the body editor is turned into a WebSocket frame
+type FrameFormat int
+
+const (
+	TextFrame FrameFormat = iota
+	JSONFrame
+	BinaryFrame
+)
+
+var frameFormatNames = map[FrameFormat]string{
+	TextFrame:   "Text",
+	JSONFrame:   "JSON",
+	BinaryFrame: "Binary (hex)",
+}
+
+var (
+	socketSentStyle     lipgloss.Style
+	socketReceivedStyle lipgloss.Style
+	socketControlStyle  lipgloss.Style
+)
+
+func init() {
+	styles.OnApply(func() {
+		socketSentStyle = lipgloss.NewStyle().Foreground(styles.Accent).Bold(true)
+		socketReceivedStyle = lipgloss.NewStyle().Foreground(styles.Success).Bold(true)
+		socketControlStyle = lipgloss.NewStyle().Foreground(styles.Special)
+	})
+}
+
+// connectSocket opens a WebSocket to the URL with the configured headers
+func (m Model) connectSocket() (Model, tea.Cmd) {
+	m.resetStream()
+	closing := m.closeSocket()
+	m.cancelGRPC()
+	m.grpcMode = false
+	m.loading = true
+	m.activeTab = ResponseTab
+
+	url := m.urlInput.Value()
+	headers := m.httpClient.HeadersFor(m.requestHeaders)
+
+	return m, tea.Batch(
+		closing,
+		m.spinner.Tick,
+		func() tea.Msg {
+			start := time.Now()
+			conn, handshake, err := ws.Dial(url, headers)
+			return SocketOpenedMessage{
+				Conn:      conn,
+				URL:       ws.NormalizeURL(url),
+				Handshake: handshake,
+				Elapsed:   time.Since(start),
+				Err:       err,
+			}
+		},
+	)
+}
+
+// openSocket shows the message log for a freshly connected socket
+func (m *Model) openSocket(msg SocketOpenedMessage) tea.Cmd {
+	m.socketMode = true
+	m.socket = msg.Conn
+	m.socketURL = msg.URL
+	m.socketErr = nil
+	m.socketLog = nil
+	m.socketRendered = nil
+	m.jsonTree, m.table = nil, nil
+	m.responseView = PrettyView
+	m.refreshSocket()
+	return waitForSocket(msg.Conn)
+}
+
+// closeSocket detaches the connection and returns a command that leaves it
+// with a "going away" close frame, so a slow peer doesn't hold up the UI
+func (m *Model) closeSocket() tea.Cmd {
+	conn := m.socket
+	if conn == nil {
+		return nil
+	}
+	m.socket = nil
+	return func() tea.Msg {
+		conn.Close(1001, "")
+		return nil
+	}
+}
+
+// waitForSocket delivers the next frame from conn, or SocketClosedMessage
+// once the connection ends
+func waitForSocket(conn *ws.Conn) tea.Cmd {
+	return func() tea.Msg {
+		message, ok := <-conn.Messages
+		if !ok {
+			return SocketClosedMessage{Conn: conn, Err: conn.Err()}
+		}
+		return SocketMessage{Conn: conn, Message: message}
+	}
+}
+
+// sendFrame sends the body editor's contents in the selected frame format
+func (m *Model) sendFrame() tea.Cmd {
+	kind, data, err := m.frameData()
+	if err != nil {
+		m.notice = styles.ErrorStyle.Render("Not sent: " + err.Error())
+		return nil
+	}
+
+	conn := m.socket
+	return func() tea.Msg {
+		message, err := conn.Send(kind, data)
+		return SocketSentMessage{Conn: conn, Message: message, Err: err}
+	}
+}
+
+// frameData converts the body editor's contents into a frame payload
+func (m Model) frameData() (ws.Kind, []byte, error) {
+	body := m.bodyTextarea.Value()
+
+	switch m.frameFormat {
+	case JSONFrame:
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, []byte(body)); err != nil {
+			return 0, nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return ws.Text, compact.Bytes(), nil
+
+	case BinaryFrame:
+		digits := strings.NewReplacer(" ", "", "\n", "", "\t", "", ":", "", "0x", "").Replace(body)
+		data, err := hex.DecodeString(digits)
+		if err != nil {
+			return 0, nil, fmt.Errorf("binary frames are written as hex bytes: %w", err)
+		}
+		return ws.Binary, data, nil
+	}
+
+	return ws.Text, []byte(body), nil
+}
+
+// pingSocket sends a ping; the pong shows up in the log when it arrives
+func (m *Model) pingSocket() tea.Cmd {
+	conn := m.socket
+	return func() tea.Msg {
+		message, err := conn.Ping()
+		return SocketSentMessage{Conn: conn, Message: message, Err: err}
+	}
+}
+
+// disconnectSocket starts a normal closing handshake
+func (m *Model) disconnectSocket() tea.Cmd {
+	conn := m.socket
+	return func() tea.Msg {
+		message, err := conn.Close(1000, "")
+		return SocketSentMessage{Conn: conn, Message: message, Err: err}
+	}
+}
+
+// appendSocketMessage records a log entry and re-renders the log
+func (m *Model) appendSocketMessage(message ws.Message) {
+	m.socketLog = append(m.socketLog, message)
+	m.socketRendered = append(m.socketRendered, m.renderSocketMessage(message))
+	if over := len(m.socketLog) - maxSocketMessages; over > 0 {
+		m.socketLog = m.socketLog[over:]
+		m.socketRendered = m.socketRendered[over:]
+	}
+	m.refreshSocket()
+}
+
+// refreshSocket rebuilds the rendered and plain-text log, following the
+// newest message if the view was already at the bottom
+func (m *Model) refreshSocket() {
+	follow := m.responseViewport.AtBottom()
+
+	if len(m.socketRendered) == 0 {
+		m.response = styles.HelpStyle.Render("Connected. Write a message in the Body tab and press Ctrl+S to send it.")
+	} else {
+		m.response = strings.Join(m.socketRendered, "\n")
+	}
+
+	var raw strings.Builder
+	for _, message := range m.socketLog {
+		raw.WriteString(socketMessageText(message))
+	}
+	m.responseBody = raw.String()
+	m.responseSize = int64(len(m.responseBody))
+
+	if m.searchInput.Value() != "" {
+		m.runSearch()
+		return
+	}
+	m.refreshResponseViewports()
+	if follow {
+		m.responseViewport.GotoBottom()
+	}
+}
+
+// socketMessageText is the plain-text log line used for saving and search
+func socketMessageText(message ws.Message) string {
+	arrow := "<-"
+	if message.Direction == ws.Sent {
+		arrow = "->"
+	}
+
+	payload := string(message.Data)
+	switch message.Kind {
+	case ws.Binary:
+		payload = hex.EncodeToString(message.Data)
+	case ws.Close:
+		payload = strings.TrimSpace(ws.CloseCodeText(message.CloseCode) + " " + message.CloseText)
+	}
+
+	return fmt.Sprintf("%s %s %s %s\n", message.Time.Format("15:04:05.000"), arrow, message.Kind, payload)
+}
+
+// renderSocketMessage renders a timestamped, direction-tagged log entry
+func (m Model) renderSocketMessage(message ws.Message) string {
+	arrow := socketReceivedStyle.Render("←")
+	if message.Direction == ws.Sent {
+		arrow = socketSentStyle.Render("→")
+	}
+
+	header := streamMetaStyle.Render(message.Time.Format("15:04:05.000")) + " " + arrow + " "
+
+	switch message.Kind {
+	case ws.Text:
+		header += streamTypeStyle.Render("text") + " " + streamMetaStyle.Render(formatBytes(int64(len(message.Data))))
+		trimmed := strings.TrimSpace(string(message.Data))
+		if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+			return header + "\n" + m.highlighter.Highlight(trimmed, "application/json")
+		}
+		return header + "\n" + string(message.Data)
+
+	case ws.Binary:
+		header += streamTypeStyle.Render("binary") + " " + streamMetaStyle.Render(formatBytes(int64(len(message.Data))))
+		shown := message.Data[:min(len(message.Data), maxSocketBinaryPreview)]
+		lines := []string{header}
+		for _, line := range hexview.Dump(shown) {
+			lines = append(lines, styleHexLine(line))
+		}
+		if rest := len(message.Data) - len(shown); rest > 0 {
+			lines = append(lines, streamMetaStyle.Render(fmt.Sprintf("… %s more", formatBytes(int64(rest)))))
+		}
+		return strings.Join(lines, "\n")
+
+	case ws.Close:
+		text := ws.CloseCodeText(message.CloseCode)
+		if message.CloseText != "" {
+			text += ": " + message.CloseText
+		}
+		return header + socketControlStyle.Render("close ") + text
+
+	default:
+		line := header + socketControlStyle.Render(message.Kind.String())
+		if len(message.Data) > 0 {
+			line += " " + streamMetaStyle.Render(string(message.Data))
+		}
+		return line
+	}
+}
+
+// renderSocketStatus shows the connection state and the keys that apply
+func (m Model) renderSocketStatus() string {
+	count := styles.InfoStyle.Render(fmt.Sprintf("%d messages", len(m.socketLog)))
+
+	var state, keys string
+	switch {
+	case m.socket != nil:
+		state = streamLiveStyle.Render("● Connected to " + m.socketURL)
+		keys = "Ctrl+S: Send body as " + frameFormatNames[m.frameFormat] + " • Ctrl+P: Ping • x: Close"
+	case m.socketErr != nil:
+		state = styles.ErrorStyle.Render("✕ Disconnected: " + m.socketErr.Error())
+		keys = "Ctrl+S: Reconnect"
+	default:
+		state = streamMetaStyle.Render("■ Closed")
+		keys = "Ctrl+S: Reconnect"
+	}
+
+	return state + "  " + count + "  " + styles.HelpStyle.Render(keys)
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/pixperk/quest/internal/ws"
+)
+
+func TestCloseSocketSendsGoingAway(t *testing.T) {
+	codes := make(chan int, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _, err = conn.ReadMessage()
+		if closeErr, ok := err.(*websocket.CloseError); ok {
+			codes <- closeErr.Code
+		}
+	}))
+	defer server.Close()
+
+	conn, _, err := ws.Dial(server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(t)
+	m.socket = conn
+
+	closing := m.closeSocket()
+	if m.socket != nil {
+		t.Error("socket still attached after closeSocket")
+	}
+	if closing == nil {
+		t.Fatal("no command to close the socket")
+	}
+	closing()
+
+	select {
+	case code := <-codes:
+		if code != websocket.CloseGoingAway {
+			t.Errorf("close code = %d, want %d", code, websocket.CloseGoingAway)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("server got no close frame")
+	}
+
+	if m.closeSocket() != nil {
+		t.Error("closeSocket without a socket returned a command")
+	}
+}
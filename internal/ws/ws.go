@@ -0,0 +1,295 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultHandshakeTimeout bounds how long the opening handshake may take
+const DefaultHandshakeTimeout = 30 * time.Second
+
+// closeTimeout is how long to wait for the peer to answer a close frame
+const closeTimeout = 5 * time.Second
+
+// Direction tells whether a message was sent or received
+type Direction int
+
+const (
+	Sent Direction = iota
+	Received
+)
+
+// Kind is the type of a WebSocket frame
+type Kind int
+
+const (
+	Text Kind = iota
+	Binary
+	Ping
+	Pong
+	Close
+)
+
+var kindNames = map[Kind]string{
+	Text:   "text",
+	Binary: "binary",
+	Ping:   "ping",
+	Pong:   "pong",
+	Close:  "close",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Message is an entry in a connection's log: a data frame or a control
+// frame, in either direction
+type Message struct {
+	Direction Direction
+	Kind      Kind
+	Data      []byte
+	// CloseCode and CloseText are set for Close messages
+	CloseCode int
+	CloseText string
+	Time      time.Time
+}
+
+// Handshake describes the server's answer to the opening handshake
+type Handshake struct {
+	StatusCode int
+	Headers    map[string]string
+}
+
+// Conn is an open WebSocket connection. Received frames, including control
+// frames, are delivered on Messages, which is closed when the connection
+// ends.
+type Conn struct {
+	Messages <-chan Message
+
+	conn     *websocket.Conn
+	messages chan Message
+	done     chan struct{}
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	err     error
+	closing bool
+}
+
+// IsURL reports whether rawURL uses the ws or wss scheme
+func IsURL(rawURL string) bool {
+	lower := strings.ToLower(rawURL)
+	return strings.HasPrefix(lower, "ws://") || strings.HasPrefix(lower, "wss://")
+}
+
+// NormalizeURL maps http(s) URLs onto ws(s) so either can be used to connect
+func NormalizeURL(rawURL string) string {
+	lower := strings.ToLower(rawURL)
+	switch {
+	case strings.HasPrefix(lower, "http://"):
+		return "ws://" + rawURL[len("http://"):]
+	case strings.HasPrefix(lower, "https://"):
+		return "wss://" + rawURL[len("https://"):]
+	}
+	return rawURL
+}
+
+// Dial opens a connection to rawURL, sending headers with the handshake.
+// The caller supplies every header, User-Agent included.
+func Dial(rawURL string, headers map[string]string) (*Conn, Handshake, error) {
+	header := http.Header{}
+	for key, value := range headers {
+		header.Set(key, value)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultHandshakeTimeout)
+	defer cancel()
+
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, NormalizeURL(rawURL), header)
+
+	var handshake Handshake
+	if resp != nil {
+		handshake.StatusCode = resp.StatusCode
+		handshake.Headers = make(map[string]string)
+		for key, values := range resp.Header {
+			handshake.Headers[key] = strings.Join(values, ", ")
+		}
+	}
+	if err != nil {
+		if errors.Is(err, websocket.ErrBadHandshake) && resp != nil {
+			return nil, handshake, fmt.Errorf("handshake failed: server answered %s", resp.Status)
+		}
+		return nil, handshake, err
+	}
+
+	messages := make(chan Message, 256)
+	c := &Conn{
+		Messages: messages,
+		conn:     conn,
+		messages: messages,
+		done:     make(chan struct{}),
+	}
+
+	conn.SetPingHandler(func(data string) error {
+		c.emit(Message{Direction: Received, Kind: Ping, Data: []byte(data)})
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		if err == nil {
+			c.emit(Message{Direction: Sent, Kind: Pong, Data: []byte(data)})
+		}
+		return err
+	})
+	conn.SetPongHandler(func(data string) error {
+		c.emit(Message{Direction: Received, Kind: Pong, Data: []byte(data)})
+		return nil
+	})
+	// The default handler fails when we started the closing handshake, so
+	// log the peer's frame here and only echo it when the peer started it
+	conn.SetCloseHandler(func(code int, text string) error {
+		c.emit(Message{Direction: Received, Kind: Close, CloseCode: code, CloseText: text})
+
+		c.mu.Lock()
+		closing := c.closing
+		c.mu.Unlock()
+		if closing {
+			return nil
+		}
+
+		payload := websocket.FormatCloseMessage(code, "")
+		if err := conn.WriteControl(websocket.CloseMessage, payload, time.Now().Add(time.Second)); err == nil {
+			c.emit(Message{Direction: Sent, Kind: Close, CloseCode: code})
+		}
+		return nil
+	})
+
+	go c.readLoop()
+
+	return c, handshake, nil
+}
+
+// readLoop forwards frames until the connection closes
+func (c *Conn) readLoop() {
+	defer close(c.messages)
+	defer close(c.done)
+	defer c.conn.Close()
+
+	for {
+		messageType, data, err := c.conn.ReadMessage()
+		if err != nil {
+			// Close frames were already logged by the close handler
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) {
+				return
+			}
+
+			c.mu.Lock()
+			if !c.closing {
+				c.err = err
+			}
+			c.mu.Unlock()
+			return
+		}
+
+		kind := Text
+		if messageType == websocket.BinaryMessage {
+			kind = Binary
+		}
+		c.emit(Message{Direction: Received, Kind: kind, Data: data})
+	}
+}
+
+func (c *Conn) emit(msg Message) {
+	msg.Time = time.Now()
+	select {
+	case c.messages <- msg:
+	case <-c.done:
+	}
+}
+
+// Send writes a text or binary frame and returns its log entry
+func (c *Conn) Send(kind Kind, data []byte) (Message, error) {
+	messageType := websocket.TextMessage
+	if kind == Binary {
+		messageType = websocket.BinaryMessage
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(DefaultHandshakeTimeout))
+	if err := c.conn.WriteMessage(messageType, data); err != nil {
+		return Message{}, err
+	}
+	return Message{Direction: Sent, Kind: kind, Data: data, Time: time.Now()}, nil
+}
+
+// Ping sends a ping frame; the answering pong arrives on Messages
+func (c *Conn) Ping() (Message, error) {
+	data := []byte(time.Now().Format("15:04:05.000"))
+	if err := c.conn.WriteControl(websocket.PingMessage, data, time.Now().Add(time.Second)); err != nil {
+		return Message{}, err
+	}
+	return Message{Direction: Sent, Kind: Ping, Data: data, Time: time.Now()}, nil
+}
+
+// Close starts the closing handshake with code and reason. The peer's close
+// frame arrives on Messages; if it doesn't answer in time the connection is
+// dropped.
+func (c *Conn) Close(code int, reason string) (Message, error) {
+	c.mu.Lock()
+	c.closing = true
+	c.mu.Unlock()
+
+	payload := websocket.FormatCloseMessage(code, reason)
+	if err := c.conn.WriteControl(websocket.CloseMessage, payload, time.Now().Add(time.Second)); err != nil {
+		c.conn.Close()
+		return Message{}, err
+	}
+	c.conn.SetReadDeadline(time.Now().Add(closeTimeout))
+
+	return Message{Direction: Sent, Kind: Close, CloseCode: code, CloseText: reason, Time: time.Now()}, nil
+}
+
+// Err returns the error that ended the connection, if it didn't end with a
+// close frame or a call to Close
+func (c *Conn) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// closeCodeNames describes the registered close codes (RFC 6455 §7.4.1)
+var closeCodeNames = map[int]string{
+	websocket.CloseNormalClosure:           "normal closure",
+	websocket.CloseGoingAway:               "going away",
+	websocket.CloseProtocolError:           "protocol error",
+	websocket.CloseUnsupportedData:         "unsupported data",
+	websocket.CloseNoStatusReceived:        "no status",
+	websocket.CloseAbnormalClosure:         "abnormal closure",
+	websocket.CloseInvalidFramePayloadData: "invalid payload",
+	websocket.ClosePolicyViolation:         "policy violation",
+	websocket.CloseMessageTooBig:           "message too big",
+	websocket.CloseMandatoryExtension:      "mandatory extension",
+	websocket.CloseInternalServerErr:       "internal server error",
+	websocket.CloseServiceRestart:          "service restart",
+	websocket.CloseTryAgainLater:           "try again later",
+	websocket.CloseTLSHandshake:            "TLS handshake failure",
+}
+
+// CloseCodeText names a close code, e.g. "1000 normal closure"
+func CloseCodeText(code int) string {
+	if name, ok := closeCodeNames[code]; ok {
+		return fmt.Sprintf("%d %s", code, name)
+	}
+	if code >= 4000 && code <= 4999 {
+		return fmt.Sprintf("%d application-defined", code)
+	}
+	return fmt.Sprintf("%d", code)
+}
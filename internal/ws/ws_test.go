@@ -0,0 +1,181 @@
+package ws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+		isWS bool
+	}{
+		{"http://example.com/socket", "ws://example.com/socket", false},
+		{"HTTPS://example.com", "wss://example.com", false},
+		{"ws://example.com", "ws://example.com", true},
+		{"WSS://example.com", "WSS://example.com", true},
+		{"example.com", "example.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeURL(tt.raw); got != tt.want {
+			t.Errorf("NormalizeURL(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+		if got := IsURL(tt.raw); got != tt.isWS {
+			t.Errorf("IsURL(%q) = %v, want %v", tt.raw, got, tt.isWS)
+		}
+	}
+}
+
+func TestCloseCodeText(t *testing.T) {
+	tests := []struct {
+		code int
+		want string
+	}{
+		{1000, "1000 normal closure"},
+		{1001, "1001 going away"},
+		{4001, "4001 application-defined"},
+		{3000, "3000"},
+	}
+
+	for _, tt := range tests {
+		if got := CloseCodeText(tt.code); got != tt.want {
+			t.Errorf("CloseCodeText(%d) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+// echoServer echoes data frames back until the client closes; a text frame
+// "close" makes it start the closing handshake with code 4001 instead
+func echoServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(data) == "close" {
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(4001, "done"), time.Now().Add(time.Second))
+				continue
+			}
+			conn.WriteMessage(messageType, data)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// entry is the part of a Message a test compares
+type entry struct {
+	direction Direction
+	kind      Kind
+	data      string
+	code      int
+	text      string
+}
+
+// drain collects the rest of conn's log, failing if it doesn't end in time
+func drain(t *testing.T, conn *Conn) []entry {
+	t.Helper()
+	var got []entry
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case message, ok := <-conn.Messages:
+			if !ok {
+				return got
+			}
+			got = append(got, entry{message.Direction, message.Kind, string(message.Data), message.CloseCode, message.CloseText})
+		case <-timeout:
+			t.Fatalf("connection didn't end; log so far %+v", got)
+		}
+	}
+}
+
+func TestConnPingAndClose(t *testing.T) {
+	server := echoServer(t)
+	conn, handshake, err := Dial(server.URL, map[string]string{"User-Agent": "quest-test"})
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	if handshake.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("handshake status = %d, want 101", handshake.StatusCode)
+	}
+
+	ping, err := conn.Ping()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pong := <-conn.Messages
+	if pong.Direction != Received || pong.Kind != Pong || string(pong.Data) != string(ping.Data) {
+		t.Errorf("answer to ping %q = %+v, want a pong with the same data", ping.Data, pong)
+	}
+
+	sent, err := conn.Close(1000, "bye")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent.Kind != Close || sent.CloseCode != 1000 || sent.CloseText != "bye" {
+		t.Errorf("Close logged %+v, want a close 1000 bye entry", sent)
+	}
+
+	got := drain(t, conn)
+	want := []entry{{Received, Close, "", 1000, ""}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("log after close = %+v, want %+v", got, want)
+	}
+	if err := conn.Err(); err != nil {
+		t.Errorf("Err after a clean close = %v, want nil", err)
+	}
+}
+
+func TestConnEchoes(t *testing.T) {
+	server := echoServer(t)
+	conn, _, err := Dial(server.URL, nil)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+
+	conn.Send(Text, []byte("hello"))
+	conn.Send(Binary, []byte{0xde, 0xad})
+	conn.Send(Text, []byte("close"))
+
+	got := drain(t, conn)
+	want := []entry{
+		{Received, Text, "hello", 0, ""},
+		{Received, Binary, "\xde\xad", 0, ""},
+		{Received, Close, "", 4001, "done"},
+		{Sent, Close, "", 4001, ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("log = %+v, want %+v", got, want)
+	}
+}
+
+func TestDialHandshakeFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Reason", "no token")
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, handshake, err := Dial(server.URL, nil)
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Dial error = %v, want the 403 reported", err)
+	}
+	if handshake.StatusCode != http.StatusForbidden || handshake.Headers["X-Reason"] != "no token" {
+		t.Errorf("handshake = %+v, want the 403 response", handshake)
+	}
+}
@@ -1,14 +1,29 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pixperk/quest/internal/config"
 	"github.com/pixperk/quest/internal/ui"
 )
 
 func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Print(config.Usage)
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "quest:", err)
+		fmt.Fprintln(os.Stderr, "Run quest -h for the flags.")
+		os.Exit(2)
+	}
+
 	if len(os.Getenv("DEBUG")) > 0 {
 		f, err := tea.LogToFile("debug.log", "debug")
 		if err != nil {
@@ -17,7 +32,11 @@ func main() {
 		defer f.Close()
 	}
 
-	m := ui.NewModel()
+	m, err := ui.NewModel(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "quest:", err)
+		os.Exit(2)
+	}
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {